@@ -18,14 +18,17 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime/debug"
 	"strconv"
+	"sync"
 
 	"github.com/optimizely/go-sdk/pkg/config"
+	"github.com/optimizely/go-sdk/pkg/decide"
 	"github.com/optimizely/go-sdk/pkg/decision"
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/event"
@@ -39,17 +42,188 @@ import (
 
 // OptimizelyClient is the entry point to the Optimizely SDK
 type OptimizelyClient struct {
-	ConfigManager      config.ProjectConfigManager
-	DecisionService    decision.Service
-	EventProcessor     event.Processor
-	notificationCenter notification.Center
-	execGroup          *utils.ExecGroup
-	logger             logging.OptimizelyLogProducer
+	ConfigManager        config.ProjectConfigManager
+	DecisionService      decision.Service
+	EventProcessor       event.Processor
+	notificationCenter   notification.Center
+	execGroup            *utils.ExecGroup
+	logger               logging.OptimizelyLogProducer
+	defaultDecideOptions *decide.Options
+
+	readyOnce sync.Once
+	ready     <-chan struct{}
+}
+
+// CreateUserContext creates a context of the user for which decision APIs will be called.
+// A user context will be created successfully even when the SDK is not fully configured yet.
+func (o *OptimizelyClient) CreateUserContext(userID string, attributes map[string]interface{}) OptimizelyUserContext {
+	return newOptimizelyUserContext(o, userID, attributes)
+}
+
+func (o *OptimizelyClient) decide(ctx context.Context, userContext OptimizelyUserContext, key string, options ...decide.OptimizelyDecideOptions) (optimizelyDecision OptimizelyDecision) {
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			switch t := r.(type) {
+			case error:
+				err = t
+			case string:
+				err = errors.New(t)
+			default:
+				err = errors.New("unexpected error")
+			}
+			errorMessage := fmt.Sprintf("decide call, optimizely SDK is panicking with the error:")
+			o.logger.Error(errorMessage, err)
+			o.logger.Debug(string(debug.Stack()))
+			optimizelyDecision = NewErrorDecision(key, userContext, err)
+		}
+	}()
+
+	projectConfig, err := o.getProjectConfig()
+	if err != nil {
+		return NewErrorDecision(key, userContext, decide.GetDecideError(decide.SDKNotReady))
+	}
+
+	feature, err := projectConfig.GetFeatureByKey(key)
+	if err != nil {
+		return NewErrorDecision(key, userContext, decide.GetDecideError(decide.FlagKeyInvalid, key))
+	}
+
+	allOptions, err := o.getAllOptions(options...)
+	if err != nil {
+		return NewErrorDecision(key, userContext, err)
+	}
+	decisionReasons := decide.NewDecisionReasons(&allOptions)
+
+	decisionContext := decision.FeatureDecisionContext{
+		Feature:               &feature,
+		ProjectConfig:         projectConfig,
+		Options:               allOptions,
+		Reasons:               decisionReasons,
+		ForcedDecisionService: userContext.forcedDecisionService,
+	}
+	usrContext := userContext.toUserContext()
+
+	featureDecision, err := o.getFeatureDecisionFromService(ctx, decisionContext, usrContext)
+	if err != nil {
+		o.logger.Warning(fmt.Sprintf(`Received error while making a decision for feature "%s": %s`, key, err))
+	}
+
+	var variationKey, ruleKey string
+	enabled := false
+	if featureDecision.Variation != nil {
+		variationKey = featureDecision.Variation.Key
+		ruleKey = featureDecision.Experiment.Key
+		enabled = featureDecision.Variation.FeatureEnabled
+	}
+
+	decisionEventDispatched := false
+	if !allOptions.DisableDecisionEvent && shouldSendImpression(decisionContext.ProjectConfig, featureDecision) {
+		impressionEvent := createFlagImpressionEvent(decisionContext.ProjectConfig, key, featureDecision, usrContext)
+		decisionEventDispatched = o.EventProcessor.ProcessEvent(impressionEvent)
+	}
+
+	variableMap := map[string]interface{}{}
+	if !allOptions.ExcludeVariables {
+		for _, v := range feature.VariableMap {
+			val := v.DefaultValue
+
+			if enabled {
+				if variable, ok := featureDecision.Variation.Variables[v.ID]; ok {
+					val = variable.Value
+				}
+			}
+
+			typedValue, typedError := o.getTypedValue(val, v.Type)
+			if typedError != nil {
+				decisionReasons.AddError(decide.GetDecideMessage(decide.VariableValueInvalid, v.Key))
+			}
+			variableMap[v.Key] = typedValue
+		}
+	}
+	reasons := decisionReasons.ToReport()
+
+	if o.notificationCenter != nil {
+		decisionNotification := decision.FlagNotification(key, variationKey, ruleKey, enabled, decisionEventDispatched, usrContext, variableMap, reasons)
+		if audienceTraces := decisionReasons.AudienceTraces(); len(audienceTraces) > 0 {
+			decisionNotification.DecisionInfo["audienceTraces"] = audienceTraces
+		}
+		if e := o.notificationCenter.Send(notification.Decision, *decisionNotification); e != nil {
+			o.logger.Warning("Problem with sending notification")
+		}
+	}
+
+	return NewOptimizelyDecision(variationKey, ruleKey, key, enabled, optimizelyjson.NewOptimizelyJSONfromMap(variableMap), userContext, reasons)
+}
+
+func (o *OptimizelyClient) decideForKeys(ctx context.Context, userContext OptimizelyUserContext, keys []string, options ...decide.OptimizelyDecideOptions) map[string]OptimizelyDecision {
+	decisionMap := map[string]OptimizelyDecision{}
+	allOptions, err := o.getAllOptions(options...)
+	if err != nil {
+		o.logger.Error("Error parsing decide options", err)
+		return decisionMap
+	}
+
+	for _, key := range keys {
+		optimizelyDecision := o.decide(ctx, userContext, key, options...)
+		if !allOptions.EnabledFlagsOnly || optimizelyDecision.Enabled {
+			decisionMap[key] = optimizelyDecision
+		}
+	}
+	return decisionMap
+}
+
+func (o *OptimizelyClient) decideAll(ctx context.Context, userContext OptimizelyUserContext, options ...decide.OptimizelyDecideOptions) map[string]OptimizelyDecision {
+	projectConfig, err := o.getProjectConfig()
+	if err != nil {
+		o.logger.Error("Error retrieving ProjectConfig", err)
+		return map[string]OptimizelyDecision{}
+	}
+
+	var allFlagKeys []string
+	for _, feature := range projectConfig.GetFeatureList() {
+		allFlagKeys = append(allFlagKeys, feature.Key)
+	}
+	return o.decideForKeys(ctx, userContext, allFlagKeys, options...)
+}
+
+// getFeatureDecisionFromService makes the feature decision bound to the given context when the decision service supports it
+func (o *OptimizelyClient) getFeatureDecisionFromService(ctx context.Context, decisionContext decision.FeatureDecisionContext, userContext entities.UserContext) (decision.FeatureDecision, error) {
+	if contextService, ok := o.DecisionService.(decision.ContextService); ok {
+		return contextService.GetFeatureDecisionWithContext(ctx, decisionContext, userContext)
+	}
+	return o.DecisionService.GetFeatureDecision(decisionContext, userContext)
+}
+
+// getExperimentDecisionFromService makes the experiment decision bound to the given context when the decision service supports it
+func (o *OptimizelyClient) getExperimentDecisionFromService(ctx context.Context, decisionContext decision.ExperimentDecisionContext, userContext entities.UserContext) (decision.ExperimentDecision, error) {
+	if contextService, ok := o.DecisionService.(decision.ContextService); ok {
+		return contextService.GetExperimentDecisionWithContext(ctx, decisionContext, userContext)
+	}
+	return o.DecisionService.GetExperimentDecision(decisionContext, userContext)
+}
+
+// getAllOptions returns the default decide options of the client combined with the given ones
+func (o *OptimizelyClient) getAllOptions(options ...decide.OptimizelyDecideOptions) (decide.Options, error) {
+	decideOptions, err := decide.NewOptions(options...)
+	if err != nil {
+		return decide.Options{}, err
+	}
+	if o.defaultDecideOptions == nil {
+		return *decideOptions, nil
+	}
+	return o.defaultDecideOptions.Merge(*decideOptions), nil
 }
 
 // Activate returns the key of the variation the user is bucketed into and queues up an impression event to be sent to
-// the Optimizely log endpoint for results processing.
+// the Optimizely log endpoint for results processing. A user held out by a global holdout gets no variation, the
+// impression of the holdout is queued up instead.
 func (o *OptimizelyClient) Activate(experimentKey string, userContext entities.UserContext) (result string, err error) {
+	return o.ActivateWithContext(context.Background(), experimentKey, userContext)
+}
+
+// ActivateWithContext does the same as Activate, with the decision bound to the given context.
+func (o *OptimizelyClient) ActivateWithContext(ctx context.Context, experimentKey string, userContext entities.UserContext) (result string, err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -67,7 +241,7 @@ func (o *OptimizelyClient) Activate(experimentKey string, userContext entities.U
 		}
 	}()
 
-	decisionContext, experimentDecision, err := o.getExperimentDecision(experimentKey, userContext)
+	decisionContext, experimentDecision, err := o.getExperimentDecision(ctx, experimentKey, userContext)
 	if err != nil {
 		o.logger.Error("received an error while computing experiment decision", err)
 		return result, err
@@ -78,6 +252,9 @@ func (o *OptimizelyClient) Activate(experimentKey string, userContext entities.U
 		result = experimentDecision.Variation.Key
 		impressionEvent := event.CreateImpressionUserEvent(decisionContext.ProjectConfig, *decisionContext.Experiment, *experimentDecision.Variation, userContext)
 		o.EventProcessor.ProcessEvent(impressionEvent)
+	} else if holdoutDecision := experimentDecision.Holdout; holdoutDecision != nil && shouldSendImpression(decisionContext.ProjectConfig, *holdoutDecision) {
+		// a held out user gets no variation, the impression of the holdout is sent instead
+		o.EventProcessor.ProcessEvent(createFlagImpressionEvent(decisionContext.ProjectConfig, "", *holdoutDecision, userContext))
 	}
 
 	return result, err
@@ -86,6 +263,11 @@ func (o *OptimizelyClient) Activate(experimentKey string, userContext entities.U
 // IsFeatureEnabled returns true if the feature is enabled for the given user. If the user is part of a feature test
 // then an impression event will be queued up to be sent to the Optimizely log endpoint for results processing.
 func (o *OptimizelyClient) IsFeatureEnabled(featureKey string, userContext entities.UserContext) (result bool, err error) {
+	return o.IsFeatureEnabledWithContext(context.Background(), featureKey, userContext)
+}
+
+// IsFeatureEnabledWithContext does the same as IsFeatureEnabled, with the decision bound to the given context.
+func (o *OptimizelyClient) IsFeatureEnabledWithContext(ctx context.Context, featureKey string, userContext entities.UserContext) (result bool, err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -103,7 +285,7 @@ func (o *OptimizelyClient) IsFeatureEnabled(featureKey string, userContext entit
 		}
 	}()
 
-	decisionContext, featureDecision, err := o.getFeatureDecision(featureKey, "", userContext)
+	decisionContext, featureDecision, err := o.getFeatureDecision(ctx, featureKey, "", userContext)
 	if err != nil {
 		o.logger.Error("received an error while computing feature decision", err)
 		return result, err
@@ -129,9 +311,8 @@ func (o *OptimizelyClient) IsFeatureEnabled(featureKey string, userContext entit
 		}
 	}
 
-	if featureDecision.Source == decision.FeatureTest && featureDecision.Variation != nil {
-		// send impression event for feature tests
-		impressionEvent := event.CreateImpressionUserEvent(decisionContext.ProjectConfig, featureDecision.Experiment, *featureDecision.Variation, userContext)
+	if shouldSendImpression(decisionContext.ProjectConfig, featureDecision) {
+		impressionEvent := createFlagImpressionEvent(decisionContext.ProjectConfig, featureKey, featureDecision, userContext)
 		o.EventProcessor.ProcessEvent(impressionEvent)
 	}
 	return result, err
@@ -140,6 +321,11 @@ func (o *OptimizelyClient) IsFeatureEnabled(featureKey string, userContext entit
 // GetEnabledFeatures returns an array containing the keys of all features in the project that are enabled for the given
 // user. For features tests, impression events will be queued up to be sent to the Optimizely log endpoint for results processing.
 func (o *OptimizelyClient) GetEnabledFeatures(userContext entities.UserContext) (enabledFeatures []string, err error) {
+	return o.GetEnabledFeaturesWithContext(context.Background(), userContext)
+}
+
+// GetEnabledFeaturesWithContext does the same as GetEnabledFeatures, with the decision bound to the given context.
+func (o *OptimizelyClient) GetEnabledFeaturesWithContext(ctx context.Context, userContext entities.UserContext) (enabledFeatures []string, err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -165,7 +351,7 @@ func (o *OptimizelyClient) GetEnabledFeatures(userContext entities.UserContext)
 
 	featureList := projectConfig.GetFeatureList()
 	for _, feature := range featureList {
-		if isEnabled, _ := o.IsFeatureEnabled(feature.Key, userContext); isEnabled {
+		if isEnabled, _ := o.IsFeatureEnabledWithContext(ctx, feature.Key, userContext); isEnabled {
 			enabledFeatures = append(enabledFeatures, feature.Key)
 		}
 	}
@@ -174,8 +360,13 @@ func (o *OptimizelyClient) GetEnabledFeatures(userContext entities.UserContext)
 
 // GetFeatureVariableBoolean returns the feature variable value of type bool associated with the given feature and variable keys.
 func (o *OptimizelyClient) GetFeatureVariableBoolean(featureKey, variableKey string, userContext entities.UserContext) (convertedValue bool, err error) {
+	return o.GetFeatureVariableBooleanWithContext(context.Background(), featureKey, variableKey, userContext)
+}
+
+// GetFeatureVariableBooleanWithContext does the same as GetFeatureVariableBoolean, with the decision bound to the given context.
+func (o *OptimizelyClient) GetFeatureVariableBooleanWithContext(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (convertedValue bool, err error) {
 
-	stringValue, variableType, featureDecision, err := o.getFeatureVariable(featureKey, variableKey, userContext)
+	stringValue, variableType, featureDecision, err := o.getFeatureVariable(ctx, featureKey, variableKey, userContext)
 	defer func() {
 		if o.notificationCenter != nil {
 			variableMap := map[string]interface{}{
@@ -207,8 +398,13 @@ func (o *OptimizelyClient) GetFeatureVariableBoolean(featureKey, variableKey str
 
 // GetFeatureVariableDouble returns the feature variable value of type double associated with the given feature and variable keys.
 func (o *OptimizelyClient) GetFeatureVariableDouble(featureKey, variableKey string, userContext entities.UserContext) (convertedValue float64, err error) {
+	return o.GetFeatureVariableDoubleWithContext(context.Background(), featureKey, variableKey, userContext)
+}
+
+// GetFeatureVariableDoubleWithContext does the same as GetFeatureVariableDouble, with the decision bound to the given context.
+func (o *OptimizelyClient) GetFeatureVariableDoubleWithContext(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (convertedValue float64, err error) {
 
-	stringValue, variableType, featureDecision, err := o.getFeatureVariable(featureKey, variableKey, userContext)
+	stringValue, variableType, featureDecision, err := o.getFeatureVariable(ctx, featureKey, variableKey, userContext)
 	defer func() {
 		if o.notificationCenter != nil {
 			variableMap := map[string]interface{}{
@@ -240,8 +436,13 @@ func (o *OptimizelyClient) GetFeatureVariableDouble(featureKey, variableKey stri
 
 // GetFeatureVariableInteger returns the feature variable value of type int associated with the given feature and variable keys.
 func (o *OptimizelyClient) GetFeatureVariableInteger(featureKey, variableKey string, userContext entities.UserContext) (convertedValue int, err error) {
+	return o.GetFeatureVariableIntegerWithContext(context.Background(), featureKey, variableKey, userContext)
+}
 
-	stringValue, variableType, featureDecision, err := o.getFeatureVariable(featureKey, variableKey, userContext)
+// GetFeatureVariableIntegerWithContext does the same as GetFeatureVariableInteger, with the decision bound to the given context.
+func (o *OptimizelyClient) GetFeatureVariableIntegerWithContext(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (convertedValue int, err error) {
+
+	stringValue, variableType, featureDecision, err := o.getFeatureVariable(ctx, featureKey, variableKey, userContext)
 	defer func() {
 		if o.notificationCenter != nil {
 			variableMap := map[string]interface{}{
@@ -273,8 +474,13 @@ func (o *OptimizelyClient) GetFeatureVariableInteger(featureKey, variableKey str
 
 // GetFeatureVariableString returns the feature variable value of type string associated with the given feature and variable keys.
 func (o *OptimizelyClient) GetFeatureVariableString(featureKey, variableKey string, userContext entities.UserContext) (stringValue string, err error) {
+	return o.GetFeatureVariableStringWithContext(context.Background(), featureKey, variableKey, userContext)
+}
 
-	stringValue, variableType, featureDecision, err := o.getFeatureVariable(featureKey, variableKey, userContext)
+// GetFeatureVariableStringWithContext does the same as GetFeatureVariableString, with the decision bound to the given context.
+func (o *OptimizelyClient) GetFeatureVariableStringWithContext(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (stringValue string, err error) {
+
+	stringValue, variableType, featureDecision, err := o.getFeatureVariable(ctx, featureKey, variableKey, userContext)
 
 	defer func() {
 		if o.notificationCenter != nil {
@@ -304,8 +510,13 @@ func (o *OptimizelyClient) GetFeatureVariableString(featureKey, variableKey stri
 
 // GetFeatureVariableJSON returns the feature variable value of type json associated with the given feature and variable keys.
 func (o *OptimizelyClient) GetFeatureVariableJSON(featureKey, variableKey string, userContext entities.UserContext) (optlyJSON *optimizelyjson.OptimizelyJSON, err error) {
+	return o.GetFeatureVariableJSONWithContext(context.Background(), featureKey, variableKey, userContext)
+}
+
+// GetFeatureVariableJSONWithContext does the same as GetFeatureVariableJSON, with the decision bound to the given context.
+func (o *OptimizelyClient) GetFeatureVariableJSONWithContext(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (optlyJSON *optimizelyjson.OptimizelyJSON, err error) {
 
-	stringVal, variableType, featureDecision, err := o.getFeatureVariable(featureKey, variableKey, userContext)
+	stringVal, variableType, featureDecision, err := o.getFeatureVariable(ctx, featureKey, variableKey, userContext)
 	defer func() {
 		if o.notificationCenter != nil {
 			var variableValue interface{}
@@ -340,9 +551,9 @@ func (o *OptimizelyClient) GetFeatureVariableJSON(featureKey, variableKey string
 }
 
 // getFeatureVariable is a helper function, returns feature variable as a string along with it's associated type and feature decision
-func (o *OptimizelyClient) getFeatureVariable(featureKey, variableKey string, userContext entities.UserContext) (string, entities.VariableType, *decision.FeatureDecision, error) {
+func (o *OptimizelyClient) getFeatureVariable(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (string, entities.VariableType, *decision.FeatureDecision, error) {
 
-	featureDecisionContext, featureDecision, err := o.getFeatureDecision(featureKey, variableKey, userContext)
+	featureDecisionContext, featureDecision, err := o.getFeatureDecision(ctx, featureKey, variableKey, userContext)
 	if err != nil {
 		return "", "", &featureDecision, err
 	}
@@ -360,8 +571,13 @@ func (o *OptimizelyClient) getFeatureVariable(featureKey, variableKey string, us
 
 // GetFeatureVariable returns feature variable as a string along with it's associated type.
 func (o *OptimizelyClient) GetFeatureVariable(featureKey, variableKey string, userContext entities.UserContext) (string, entities.VariableType, error) {
+	return o.GetFeatureVariableWithContext(context.Background(), featureKey, variableKey, userContext)
+}
+
+// GetFeatureVariableWithContext does the same as GetFeatureVariable, with the decision bound to the given context.
+func (o *OptimizelyClient) GetFeatureVariableWithContext(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (string, entities.VariableType, error) {
 
-	stringValue, variableType, featureDecision, err := o.getFeatureVariable(featureKey, variableKey, userContext)
+	stringValue, variableType, featureDecision, err := o.getFeatureVariable(ctx, featureKey, variableKey, userContext)
 
 	func() {
 		var convertedValue interface{}
@@ -401,22 +617,32 @@ func (o *OptimizelyClient) GetFeatureVariable(featureKey, variableKey string, us
 
 // GetAllFeatureVariablesWithDecision returns all the variables for a given feature along with the enabled state.
 func (o *OptimizelyClient) GetAllFeatureVariablesWithDecision(featureKey string, userContext entities.UserContext) (enabled bool, variableMap map[string]interface{}, err error) {
+	return o.GetAllFeatureVariablesWithDecisionWithContext(context.Background(), featureKey, userContext)
+}
+
+// GetAllFeatureVariablesWithDecisionWithContext does the same as GetAllFeatureVariablesWithDecision, with the decision bound to the given context.
+func (o *OptimizelyClient) GetAllFeatureVariablesWithDecisionWithContext(ctx context.Context, featureKey string, userContext entities.UserContext) (enabled bool, variableMap map[string]interface{}, err error) {
+	_, enabled, variableMap, err = o.getAllFeatureVariables(ctx, featureKey, userContext)
+	return enabled, variableMap, err
+}
+
+func (o *OptimizelyClient) getAllFeatureVariables(ctx context.Context, featureKey string, userContext entities.UserContext) (feature *entities.Feature, enabled bool, variableMap map[string]interface{}, err error) {
 
 	variableMap = make(map[string]interface{})
-	decisionContext, featureDecision, err := o.getFeatureDecision(featureKey, "", userContext)
+	decisionContext, featureDecision, err := o.getFeatureDecision(ctx, featureKey, "", userContext)
 	if err != nil {
 		o.logger.Error("Optimizely SDK tracking error", err)
-		return enabled, variableMap, err
+		return feature, enabled, variableMap, err
 	}
 
 	if featureDecision.Variation != nil {
 		enabled = featureDecision.Variation.FeatureEnabled
 	}
 
-	feature := decisionContext.Feature
+	feature = decisionContext.Feature
 	if feature == nil {
 		o.logger.Warning(fmt.Sprintf(`feature "%s" does not exist`, featureKey))
-		return enabled, variableMap, nil
+		return feature, enabled, variableMap, nil
 	}
 
 	errs := new(multierror.Error)
@@ -444,17 +670,22 @@ func (o *OptimizelyClient) GetAllFeatureVariablesWithDecision(featureKey string,
 			o.logger.Warning("Problem with sending notification")
 		}
 	}
-	return enabled, variableMap, errs.ErrorOrNil()
+	return feature, enabled, variableMap, errs.ErrorOrNil()
 }
 
 // GetDetailedFeatureDecisionUnsafe triggers an impression event and returns all the variables
 // for a given feature along with the experiment key, variation key and the enabled state.
 // Usage of this method is unsafe and not recommended since it can be removed in any of the next releases.
 func (o *OptimizelyClient) GetDetailedFeatureDecisionUnsafe(featureKey string, userContext entities.UserContext, disableTracking bool) (decisionInfo decision.UnsafeFeatureDecisionInfo, err error) {
+	return o.GetDetailedFeatureDecisionUnsafeWithContext(context.Background(), featureKey, userContext, disableTracking)
+}
+
+// GetDetailedFeatureDecisionUnsafeWithContext does the same as GetDetailedFeatureDecisionUnsafe, with the decision bound to the given context.
+func (o *OptimizelyClient) GetDetailedFeatureDecisionUnsafeWithContext(ctx context.Context, featureKey string, userContext entities.UserContext, disableTracking bool) (decisionInfo decision.UnsafeFeatureDecisionInfo, err error) {
 
 	decisionInfo = decision.UnsafeFeatureDecisionInfo{}
 	decisionInfo.VariableMap = make(map[string]interface{})
-	decisionContext, featureDecision, err := o.getFeatureDecision(featureKey, "", userContext)
+	decisionContext, featureDecision, err := o.getFeatureDecision(ctx, featureKey, "", userContext)
 	if err != nil {
 		o.logger.Error("Optimizely SDK tracking error", err)
 		return decisionInfo, err
@@ -466,12 +697,11 @@ func (o *OptimizelyClient) GetDetailedFeatureDecisionUnsafe(featureKey string, u
 		if featureDecision.Source == decision.FeatureTest {
 			decisionInfo.VariationKey = featureDecision.Variation.Key
 			decisionInfo.ExperimentKey = featureDecision.Experiment.Key
-			// Triggers impression events when applicable
-			if !disableTracking {
-				// send impression event for feature tests
-				impressionEvent := event.CreateImpressionUserEvent(decisionContext.ProjectConfig, featureDecision.Experiment, *featureDecision.Variation, userContext)
-				o.EventProcessor.ProcessEvent(impressionEvent)
-			}
+		}
+		// Triggers impression events when applicable
+		if !disableTracking && shouldSendImpression(decisionContext.ProjectConfig, featureDecision) {
+			impressionEvent := createFlagImpressionEvent(decisionContext.ProjectConfig, featureKey, featureDecision, userContext)
+			o.EventProcessor.ProcessEvent(impressionEvent)
 		}
 	}
 
@@ -513,7 +743,12 @@ func (o *OptimizelyClient) GetDetailedFeatureDecisionUnsafe(featureKey string, u
 
 // GetAllFeatureVariables returns all the variables as OptimizelyJSON object for a given feature.
 func (o *OptimizelyClient) GetAllFeatureVariables(featureKey string, userContext entities.UserContext) (optlyJSON *optimizelyjson.OptimizelyJSON, err error) {
-	_, variableMap, err := o.GetAllFeatureVariablesWithDecision(featureKey, userContext)
+	return o.GetAllFeatureVariablesWithContext(context.Background(), featureKey, userContext)
+}
+
+// GetAllFeatureVariablesWithContext does the same as GetAllFeatureVariables, with the decision bound to the given context.
+func (o *OptimizelyClient) GetAllFeatureVariablesWithContext(ctx context.Context, featureKey string, userContext entities.UserContext) (optlyJSON *optimizelyjson.OptimizelyJSON, err error) {
+	_, variableMap, err := o.GetAllFeatureVariablesWithDecisionWithContext(ctx, featureKey, userContext)
 	if err != nil {
 		return optlyJSON, err
 	}
@@ -521,8 +756,41 @@ func (o *OptimizelyClient) GetAllFeatureVariables(featureKey string, userContext
 	return optlyJSON, nil
 }
 
+// DecodeFeatureVariables evaluates all the variables of the given feature and stores them in the struct pointed to
+// by target. Struct fields are mapped to variables with the `optimizely:"variableKey"` tag, json variables can be
+// decoded into any type supported by encoding/json. Fields without a matching variable or with a type which does not
+// match the type of the variable are reported in the returned error, the other fields are still set.
+func (o *OptimizelyClient) DecodeFeatureVariables(featureKey string, userContext entities.UserContext, target interface{}) error {
+	return o.DecodeFeatureVariablesWithContext(context.Background(), featureKey, userContext, target)
+}
+
+// DecodeFeatureVariablesWithContext does the same as DecodeFeatureVariables, with the decision bound to the given context.
+func (o *OptimizelyClient) DecodeFeatureVariablesWithContext(ctx context.Context, featureKey string, userContext entities.UserContext, target interface{}) error {
+	if err := validateDecodeTarget(target); err != nil {
+		return err
+	}
+
+	feature, _, variableMap, err := o.getAllFeatureVariables(ctx, featureKey, userContext)
+	if feature == nil {
+		if err == nil {
+			err = fmt.Errorf(`feature "%s" does not exist`, featureKey)
+		}
+		return err
+	}
+
+	errs := new(multierror.Error)
+	errs = multierror.Append(errs, err)
+	errs = multierror.Append(errs, decodeVariables(feature.VariableMap, variableMap, target))
+	return errs.ErrorOrNil()
+}
+
 // GetVariation returns the key of the variation the user is bucketed into. Does not generate impression events.
 func (o *OptimizelyClient) GetVariation(experimentKey string, userContext entities.UserContext) (result string, err error) {
+	return o.GetVariationWithContext(context.Background(), experimentKey, userContext)
+}
+
+// GetVariationWithContext does the same as GetVariation, with the decision bound to the given context.
+func (o *OptimizelyClient) GetVariationWithContext(ctx context.Context, experimentKey string, userContext entities.UserContext) (result string, err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -540,7 +808,7 @@ func (o *OptimizelyClient) GetVariation(experimentKey string, userContext entiti
 		}
 	}()
 
-	_, experimentDecision, err := o.getExperimentDecision(experimentKey, userContext)
+	_, experimentDecision, err := o.getExperimentDecision(ctx, experimentKey, userContext)
 	if err != nil {
 		o.logger.Error("received an error while computing experiment decision", err)
 	}
@@ -597,7 +865,7 @@ func (o *OptimizelyClient) Track(eventKey string, userContext entities.UserConte
 	return nil
 }
 
-func (o *OptimizelyClient) getFeatureDecision(featureKey, variableKey string, userContext entities.UserContext) (decisionContext decision.FeatureDecisionContext, featureDecision decision.FeatureDecision, err error) {
+func (o *OptimizelyClient) getFeatureDecision(ctx context.Context, featureKey, variableKey string, userContext entities.UserContext) (decisionContext decision.FeatureDecisionContext, featureDecision decision.FeatureDecision, err error) {
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -645,7 +913,7 @@ func (o *OptimizelyClient) getFeatureDecision(featureKey, variableKey string, us
 		Variable:      variable,
 	}
 
-	featureDecision, err = o.DecisionService.GetFeatureDecision(decisionContext, userContext)
+	featureDecision, err = o.getFeatureDecisionFromService(ctx, decisionContext, userContext)
 	if err != nil {
 		o.logger.Warning(fmt.Sprintf(`Received error while making a decision for feature "%s": %s`, featureKey, err))
 		return decisionContext, featureDecision, nil
@@ -654,7 +922,7 @@ func (o *OptimizelyClient) getFeatureDecision(featureKey, variableKey string, us
 	return decisionContext, featureDecision, nil
 }
 
-func (o *OptimizelyClient) getExperimentDecision(experimentKey string, userContext entities.UserContext) (decisionContext decision.ExperimentDecisionContext, experimentDecision decision.ExperimentDecision, err error) {
+func (o *OptimizelyClient) getExperimentDecision(ctx context.Context, experimentKey string, userContext entities.UserContext) (decisionContext decision.ExperimentDecisionContext, experimentDecision decision.ExperimentDecision, err error) {
 
 	userID := userContext.ID
 	o.logger.Debug(fmt.Sprintf(`Evaluating experiment "%s" for user "%s".`, experimentKey, userID))
@@ -675,7 +943,7 @@ func (o *OptimizelyClient) getExperimentDecision(experimentKey string, userConte
 		ProjectConfig: projectConfig,
 	}
 
-	experimentDecision, err = o.DecisionService.GetExperimentDecision(decisionContext, userContext)
+	experimentDecision, err = o.getExperimentDecisionFromService(ctx, decisionContext, userContext)
 	if err != nil {
 		o.logger.Warning(fmt.Sprintf(`Received error while making a decision for experiment "%s": %s`, experimentKey, err))
 		return decisionContext, experimentDecision, nil
@@ -769,11 +1037,108 @@ func (o *OptimizelyClient) GetOptimizelyConfig() (optimizelyConfig *config.Optim
 
 }
 
+// Ready returns a channel which is closed once the client has a project config and is able to make decisions.
+// Until then decisions fail with an error, or return the default values when the decide APIs are used.
+func (o *OptimizelyClient) Ready() <-chan struct{} {
+	o.readyOnce.Do(func() {
+		o.ready = o.newReadyChannel()
+	})
+	return o.ready
+}
+
+// WaitForReady blocks until the client is ready or the given context is done, in which case the context error is returned.
+func (o *OptimizelyClient) WaitForReady(ctx context.Context) error {
+	select {
+	case <-o.Ready():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *OptimizelyClient) newReadyChannel() <-chan struct{} {
+	if notifier, ok := o.ConfigManager.(config.ReadinessNotifier); ok {
+		return notifier.Ready()
+	}
+
+	ready := make(chan struct{})
+	var closeOnce sync.Once
+	closeReady := func() {
+		closeOnce.Do(func() {
+			close(ready)
+		})
+	}
+
+	if isNil(o.ConfigManager) {
+		return ready
+	}
+	if _, err := o.ConfigManager.OnProjectConfigUpdate(func(notification.ProjectConfigUpdateNotification) {
+		closeReady()
+	}); err != nil {
+		o.logger.Debug(fmt.Sprintf("Unable to listen for project config updates: %v", err))
+	}
+	// the config may have been set before the handler was registered
+	if _, err := o.getProjectConfig(); err == nil {
+		closeReady()
+	}
+	return ready
+}
+
+// ReadinessState describes whether the client has a project config and where it comes from
+type ReadinessState struct {
+	Ready  bool
+	Source config.ConfigSource
+}
+
+// ReadinessState returns the current readiness state of the client. The source is only known when the config manager
+// reports it, a config served from a datafile cached on disk has config.ConfigSourceCache as source.
+func (o *OptimizelyClient) ReadinessState() ReadinessState {
+	state := ReadinessState{}
+	if projectConfig, err := o.getProjectConfig(); err != nil || isNil(projectConfig) {
+		return state
+	}
+	state.Ready = true
+	if reporter, ok := o.ConfigManager.(config.SourceReporter); ok {
+		state.Source = reporter.ConfigSource()
+	}
+	return state
+}
+
 // Close closes the Optimizely instance and stops any ongoing tasks from its children components.
 func (o *OptimizelyClient) Close() {
 	o.execGroup.TerminateAndWait()
 }
 
+// shouldSendImpression returns whether a feature decision produces an impression event. The decisions of feature tests
+// and holdouts always do, the decisions of rollouts only when sendFlagDecisions is enabled in the datafile.
+func shouldSendImpression(projectConfig config.ProjectConfig, featureDecision decision.FeatureDecision) bool {
+	if featureDecision.Variation == nil {
+		return false
+	}
+	switch featureDecision.Source {
+	case decision.FeatureTest, decision.Holdout, decision.LocalHoldout:
+		return true
+	case decision.Rollout:
+		return projectConfig.GetSendFlagDecisions()
+	default:
+		return false
+	}
+}
+
+// createFlagImpressionEvent creates the impression of the feature decision, the rule type of its metadata follows the
+// source of the decision
+func createFlagImpressionEvent(projectConfig config.ProjectConfig, flagKey string, featureDecision decision.FeatureDecision,
+	userContext entities.UserContext) event.UserEvent {
+	ruleType := event.RuleTypeFeatureTest
+	switch featureDecision.Source {
+	case decision.Rollout:
+		ruleType = event.RuleTypeRollout
+	case decision.Holdout, decision.LocalHoldout:
+		ruleType = event.RuleTypeHoldout
+	}
+	return event.CreateFlagImpressionUserEvent(projectConfig, flagKey, ruleType, featureDecision.Experiment, *featureDecision.Variation, userContext)
+}
+
 func isNil(v interface{}) bool {
 	return v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil())
 }
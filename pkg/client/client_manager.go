@@ -0,0 +1,210 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client //
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/logging"
+	"github.com/optimizely/go-sdk/pkg/registry"
+)
+
+// DefaultIdleTimeout is the time after which a client which has not been used is closed by the ClientManager
+const DefaultIdleTimeout = 30 * time.Minute
+
+type managedClient struct {
+	once     sync.Once
+	client   *OptimizelyClient
+	err      error
+	lastUsed time.Time
+}
+
+// ClientManager lazily creates and caches one OptimizelyClient per SDK key, all built with the same options, and
+// closes the clients which have not been used for a while.
+type ClientManager struct {
+	clientOptions []OptionFunc
+	idleTimeout   time.Duration
+	logger        logging.OptimizelyLogProducer
+
+	clients   map[string]*managedClient
+	lock      sync.Mutex
+	now       func() time.Time
+	newClient func(sdkKey string) (*OptimizelyClient, error)
+}
+
+// ManagerOptionFunc is used to provide custom configuration to the ClientManager.
+type ManagerOptionFunc func(*ClientManager)
+
+// WithClientOptions sets the options used to create every client of the manager. The options are applied for each
+// client, so they should create the components they set rather than share one instance between all the clients.
+func WithClientOptions(clientOptions ...OptionFunc) ManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.clientOptions = append(m.clientOptions, clientOptions...)
+	}
+}
+
+// WithIdleTimeout sets the time after which an unused client is closed, a non-positive value disables it
+func WithIdleTimeout(idleTimeout time.Duration) ManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.idleTimeout = idleTimeout
+	}
+}
+
+// NewClientManager returns a new instance of the ClientManager
+func NewClientManager(options ...ManagerOptionFunc) *ClientManager {
+	manager := &ClientManager{
+		idleTimeout: DefaultIdleTimeout,
+		logger:      logging.GetLogger("", "ClientManager"),
+		clients:     map[string]*managedClient{},
+		now:         time.Now,
+	}
+	manager.newClient = func(sdkKey string) (*OptimizelyClient, error) {
+		factory := &OptimizelyFactory{SDKKey: sdkKey}
+		return factory.Client(manager.clientOptions...)
+	}
+
+	for _, opt := range options {
+		opt(manager)
+	}
+	return manager
+}
+
+// Client returns the client of the given SDK key, creating it on first use
+func (m *ClientManager) Client(sdkKey string) (*OptimizelyClient, error) {
+	if sdkKey == "" {
+		return nil, errors.New("unable to get client: SDK key is empty")
+	}
+
+	m.lock.Lock()
+	entry, ok := m.clients[sdkKey]
+	if !ok {
+		entry = &managedClient{}
+		m.clients[sdkKey] = entry
+	}
+	entry.lastUsed = m.now()
+	m.lock.Unlock()
+
+	// clients are created outside of the lock, so that a slow startup does not block the other SDK keys
+	entry.once.Do(func() {
+		entry.client, entry.err = m.newClient(sdkKey)
+	})
+
+	if entry.err != nil {
+		m.lock.Lock()
+		if m.clients[sdkKey] == entry {
+			delete(m.clients, sdkKey)
+		}
+		m.lock.Unlock()
+		return nil, entry.err
+	}
+	return entry.client, nil
+}
+
+// Start closes the idle clients periodically until the given context is done
+func (m *ClientManager) Start(ctx context.Context) {
+	if m.idleTimeout <= 0 {
+		m.logger.Info("Closing idle clients is disabled")
+		return
+	}
+	t := time.NewTicker(m.idleTimeout)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			m.CloseIdle()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CloseIdle closes the clients which have not been used for longer than the idle timeout
+func (m *ClientManager) CloseIdle() {
+	if m.idleTimeout <= 0 {
+		return
+	}
+
+	m.lock.Lock()
+	idle := map[string]*managedClient{}
+	for sdkKey, entry := range m.clients {
+		if m.now().Sub(entry.lastUsed) > m.idleTimeout {
+			idle[sdkKey] = entry
+			delete(m.clients, sdkKey)
+		}
+	}
+	m.lock.Unlock()
+
+	for sdkKey, entry := range idle {
+		m.logger.Debug(fmt.Sprintf(`Closing client of SDK key "%s", unused since %v`, sdkKey, entry.lastUsed))
+		m.closeManagedClient(sdkKey, entry)
+	}
+}
+
+// Remove closes and removes the client of the given SDK key, returns false if there is none
+func (m *ClientManager) Remove(sdkKey string) bool {
+	m.lock.Lock()
+	entry, ok := m.clients[sdkKey]
+	delete(m.clients, sdkKey)
+	m.lock.Unlock()
+
+	if ok {
+		m.closeManagedClient(sdkKey, entry)
+	}
+	return ok
+}
+
+// Close closes all the clients, which flushes their event processors, and removes them from the manager
+func (m *ClientManager) Close() {
+	m.lock.Lock()
+	clients := m.clients
+	m.clients = map[string]*managedClient{}
+	m.lock.Unlock()
+
+	wg := sync.WaitGroup{}
+	for sdkKey, entry := range clients {
+		wg.Add(1)
+		go func(sdkKey string, entry *managedClient) {
+			defer wg.Done()
+			m.closeManagedClient(sdkKey, entry)
+		}(sdkKey, entry)
+	}
+	wg.Wait()
+}
+
+// closeManagedClient closes a client which has been removed from the manager and releases its notification center
+func (m *ClientManager) closeManagedClient(sdkKey string, entry *managedClient) {
+	// waits for a creation in progress
+	entry.once.Do(func() {})
+	if entry.client == nil {
+		return
+	}
+	entry.client.Close()
+
+	// the lock keeps a new client of the same SDK key from being created until the notification center is removed
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.clients[sdkKey]; ok {
+		// the client has already been created again and may share the notification center
+		return
+	}
+	registry.RemoveNotificationCenterIfCurrent(sdkKey, entry.client.notificationCenter)
+}
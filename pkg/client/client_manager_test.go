@@ -0,0 +1,224 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/optimizely/go-sdk/pkg/registry"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientManagerTestSuite struct {
+	suite.Suite
+	manager *ClientManager
+	now     time.Time
+}
+
+func (s *ClientManagerTestSuite) SetupTest() {
+	withDatafile := func(f *OptimizelyFactory) {
+		f.Datafile = []byte(`{"revision":"42","version": "4","projectId":"1","events":[{"id":"1","key":"event_key","experimentIds":[]}]}`)
+	}
+	s.manager = NewClientManager(WithClientOptions(withDatafile), WithIdleTimeout(time.Minute))
+	s.now = time.Now()
+	s.manager.now = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *ClientManagerTestSuite) TearDownTest() {
+	s.manager.Close()
+}
+
+func (s *ClientManagerTestSuite) TestClientIsCreatedOnce() {
+	client1, err := s.manager.Client("sdk_key_1")
+	s.NoError(err)
+	s.NotNil(client1)
+
+	config, err := client1.ConfigManager.GetConfig()
+	s.NoError(err)
+	s.Equal("42", config.GetRevision())
+
+	sameClient, err := s.manager.Client("sdk_key_1")
+	s.NoError(err)
+	s.True(client1 == sameClient)
+
+	client2, err := s.manager.Client("sdk_key_2")
+	s.NoError(err)
+	s.False(client1 == client2)
+	s.Len(s.manager.clients, 2)
+}
+
+func (s *ClientManagerTestSuite) TestClientConcurrentCreation() {
+	created := 0
+	var lock sync.Mutex
+	newClient := s.manager.newClient
+	s.manager.newClient = func(sdkKey string) (*OptimizelyClient, error) {
+		lock.Lock()
+		created++
+		lock.Unlock()
+		return newClient(sdkKey)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.manager.Client("sdk_key_1")
+			s.NoError(err)
+		}()
+	}
+	wg.Wait()
+	s.Equal(1, created)
+}
+
+func (s *ClientManagerTestSuite) TestClientWithError() {
+	_, err := s.manager.Client("")
+	s.Error(err)
+
+	s.manager.newClient = func(sdkKey string) (*OptimizelyClient, error) {
+		return nil, errors.New("unable to create client")
+	}
+	_, err = s.manager.Client("sdk_key_1")
+	s.Equal(errors.New("unable to create client"), err)
+	s.Empty(s.manager.clients)
+}
+
+func (s *ClientManagerTestSuite) TestCloseIdle() {
+	idleClient, err := s.manager.Client("idle_sdk_key")
+	s.NoError(err)
+	idleNotificationCenter := registry.GetNotificationCenter("idle_sdk_key")
+
+	s.now = s.now.Add(50 * time.Second)
+	_, err = s.manager.Client("active_sdk_key")
+	s.NoError(err)
+
+	s.now = s.now.Add(20 * time.Second)
+	s.manager.CloseIdle()
+	s.NotContains(s.manager.clients, "idle_sdk_key")
+	s.Contains(s.manager.clients, "active_sdk_key")
+	s.True(idleNotificationCenter != registry.GetNotificationCenter("idle_sdk_key"))
+
+	// the next use creates a new client
+	newClient, err := s.manager.Client("idle_sdk_key")
+	s.NoError(err)
+	s.False(idleClient == newClient)
+}
+
+func (s *ClientManagerTestSuite) TestRemoveThenRecreate() {
+	release := make(chan struct{})
+	var created int32
+	newClient := s.manager.newClient
+	s.manager.newClient = func(sdkKey string) (*OptimizelyClient, error) {
+		if atomic.AddInt32(&created, 1) == 1 {
+			// the first creation is slow
+			<-release
+		}
+		return newClient(sdkKey)
+	}
+	waitForClients := func(count int) {
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			s.manager.lock.Lock()
+			n := len(s.manager.clients)
+			s.manager.lock.Unlock()
+			if n == count {
+				return
+			}
+		}
+		s.FailNow("unexpected number of clients")
+	}
+
+	// the client is removed while it is still being created
+	go func() {
+		_, _ = s.manager.Client("recreated_sdk_key")
+	}()
+	waitForClients(1)
+	removed := make(chan bool)
+	go func() {
+		removed <- s.manager.Remove("recreated_sdk_key")
+	}()
+	waitForClients(0)
+
+	// the new client shares the notification center of the removed one, it must not be removed when it is closed
+	recreatedClient, err := s.manager.Client("recreated_sdk_key")
+	s.NoError(err)
+	close(release)
+	s.True(<-removed)
+	s.True(recreatedClient.notificationCenter == registry.GetNotificationCenter("recreated_sdk_key"))
+}
+
+func (s *ClientManagerTestSuite) TestCloseIdleDisabled() {
+	s.manager.idleTimeout = 0
+	_, err := s.manager.Client("sdk_key_1")
+	s.NoError(err)
+
+	s.now = s.now.Add(time.Hour)
+	s.manager.CloseIdle()
+	s.Contains(s.manager.clients, "sdk_key_1")
+}
+
+func (s *ClientManagerTestSuite) TestStart() {
+	s.manager.idleTimeout = 10 * time.Millisecond
+	s.manager.now = time.Now
+	_, err := s.manager.Client("sdk_key_1")
+	s.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.manager.Start(ctx)
+
+	s.Eventually(func() bool {
+		s.manager.lock.Lock()
+		defer s.manager.lock.Unlock()
+		return len(s.manager.clients) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (s *ClientManagerTestSuite) TestRemove() {
+	_, err := s.manager.Client("sdk_key_1")
+	s.NoError(err)
+
+	s.True(s.manager.Remove("sdk_key_1"))
+	s.False(s.manager.Remove("sdk_key_1"))
+	s.Empty(s.manager.clients)
+}
+
+func (s *ClientManagerTestSuite) TestClose() {
+	dispatcher := &MockDispatcher{}
+	s.manager.clientOptions = append(s.manager.clientOptions, WithEventDispatcher(dispatcher))
+	client, err := s.manager.Client("sdk_key_1")
+	s.NoError(err)
+	_, err = s.manager.Client("sdk_key_2")
+	s.NoError(err)
+
+	s.NoError(client.Track("event_key", entities.UserContext{ID: "test_user"}, nil))
+	s.manager.Close()
+	s.Empty(s.manager.clients)
+	s.Len(dispatcher.Events, 1)
+}
+
+func TestClientManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientManagerTestSuite))
+}
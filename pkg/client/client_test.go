@@ -20,9 +20,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/optimizely/go-sdk/pkg/config"
 	"github.com/optimizely/go-sdk/pkg/decision"
@@ -164,6 +166,9 @@ func (TestConfig) GetAttributeID(key string) string { // returns "" if there is
 func (TestConfig) GetBotFiltering() bool {
 	return false
 }
+func (TestConfig) GetSendFlagDecisions() bool {
+	return false
+}
 func (TestConfig) GetClientName() string {
 	return "go-sdk"
 }
@@ -1237,6 +1242,58 @@ func TestGetOptimizelyConfig(t *testing.T) {
 	assert.Equal(t, &config.OptimizelyConfig{Revision: "232"}, optimizelyConfig)
 }
 
+func TestReadyWithConfigManager(t *testing.T) {
+	client := OptimizelyClient{
+		ConfigManager: ValidProjectConfigManager(),
+		logger:        logging.GetLogger("", ""),
+	}
+
+	select {
+	case <-client.Ready():
+	default:
+		t.Fatal("client should be ready when the config manager has a project config")
+	}
+	assert.NoError(t, client.WaitForReady(context.Background()))
+}
+
+func TestWaitForReady(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	configManager := config.NewAsyncPollingProjectConfigManager("", config.WithRequester(mockRequester))
+
+	client := OptimizelyClient{
+		ConfigManager: configManager,
+		logger:        logging.GetLogger("", ""),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, client.WaitForReady(ctx))
+
+	go configManager.SyncConfig()
+	assert.NoError(t, client.WaitForReady(context.Background()))
+	mockRequester.AssertExpectations(t)
+}
+
+func TestReadinessState(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	configManager := config.NewAsyncPollingProjectConfigManager("", config.WithRequester(mockRequester))
+
+	client := OptimizelyClient{
+		ConfigManager: configManager,
+		logger:        logging.GetLogger("", ""),
+	}
+	assert.Equal(t, ReadinessState{}, client.ReadinessState())
+
+	configManager.SyncConfig()
+	assert.Equal(t, ReadinessState{Ready: true, Source: config.ConfigSourceRemote}, client.ReadinessState())
+
+	// the source is unknown when the config manager does not report it
+	client.ConfigManager = ValidProjectConfigManager()
+	assert.Equal(t, ReadinessState{Ready: true}, client.ReadinessState())
+}
+
 func TestGetFeatureDecisionValid(t *testing.T) {
 	testFeatureKey := "test_feature_key"
 	testVariableKey := "test_feature_flag_key"
@@ -1278,7 +1335,7 @@ func TestGetFeatureDecisionValid(t *testing.T) {
 		logger:          logging.GetLogger("", ""),
 	}
 
-	_, featureDecision, err := client.getFeatureDecision(testFeatureKey, testVariableKey, testUserContext)
+	_, featureDecision, err := client.getFeatureDecision(context.Background(), testFeatureKey, testVariableKey, testUserContext)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedFeatureDecision, featureDecision)
 }
@@ -1324,7 +1381,7 @@ func TestGetFeatureDecisionErrProjectConfig(t *testing.T) {
 		logger:          logging.GetLogger("", ""),
 	}
 
-	_, _, err := client.getFeatureDecision(testFeatureKey, testVariableKey, testUserContext)
+	_, _, err := client.getFeatureDecision(context.Background(), testFeatureKey, testVariableKey, testUserContext)
 	assert.Error(t, err)
 }
 
@@ -1368,7 +1425,7 @@ func TestGetFeatureDecisionPanicProjectConfig(t *testing.T) {
 		logger:          logging.GetLogger("", ""),
 	}
 
-	_, _, err := client.getFeatureDecision(testFeatureKey, testVariableKey, testUserContext)
+	_, _, err := client.getFeatureDecision(context.Background(), testFeatureKey, testVariableKey, testUserContext)
 	assert.Error(t, err)
 }
 
@@ -1403,7 +1460,7 @@ func TestGetFeatureDecisionPanicDecisionService(t *testing.T) {
 		logger:          logging.GetLogger("", ""),
 	}
 
-	_, _, err := client.getFeatureDecision(testFeatureKey, testVariableKey, testUserContext)
+	_, _, err := client.getFeatureDecision(context.Background(), testFeatureKey, testVariableKey, testUserContext)
 	assert.Error(t, err)
 	assert.EqualError(t, err, "I'm panicking")
 }
@@ -1449,7 +1506,7 @@ func TestGetFeatureDecisionErrFeatureDecision(t *testing.T) {
 		logger:          logging.GetLogger("", ""),
 	}
 
-	_, decision, err := client.getFeatureDecision(testFeatureKey, testVariableKey, testUserContext)
+	_, decision, err := client.getFeatureDecision(context.Background(), testFeatureKey, testVariableKey, testUserContext)
 	assert.Equal(t, expectedFeatureDecision, decision)
 	assert.NoError(t, err)
 }
@@ -1952,6 +2009,110 @@ func TestGetAllFeatureVariables(t *testing.T) {
 	assert.Equal(t, "some_value", jsonVarMap["field2"])
 }
 
+func TestDecodeFeatureVariables(t *testing.T) {
+	testFeatureKey := "test_feature_key"
+	testUserContext := entities.UserContext{ID: "test_user_1"}
+
+	variables := []variable{
+		{key: "var_str", defaultVal: "default", varVal: "var", varType: entities.String},
+		{key: "var_bool", defaultVal: "false", varVal: "true", varType: entities.Boolean},
+		{key: "var_int", defaultVal: "10", varVal: "20", varType: entities.Integer},
+		{key: "var_double", defaultVal: "1.0", varVal: "2.5", varType: entities.Double},
+		{key: "var_json", defaultVal: "{}", varVal: "{\"field1\":12, \"field2\": \"some_value\"}", varType: entities.JSON},
+	}
+
+	mockConfig, variableMap, varVariableMap := getMockConfigAndMapsForVariables(testFeatureKey, variables)
+	testVariation := entities.Variation{
+		ID:             "22222",
+		Key:            "22222",
+		FeatureEnabled: true,
+		Variables:      varVariableMap,
+	}
+	testExperiment := entities.Experiment{
+		ID:         "111111",
+		Variations: map[string]entities.Variation{"22222": testVariation},
+	}
+	testFeature := getTestFeature(testFeatureKey, testExperiment)
+	testFeature.VariableMap = variableMap
+	mockConfig.On("GetFeatureByKey", testFeatureKey).Return(testFeature, nil)
+
+	mockConfigManager := new(MockProjectConfigManager)
+	mockConfigManager.On("GetConfig").Return(mockConfig, nil)
+
+	testDecisionContext := decision.FeatureDecisionContext{
+		Feature:       &testFeature,
+		ProjectConfig: mockConfig,
+	}
+
+	expectedFeatureDecision := getTestFeatureDecision(testExperiment, testVariation)
+	mockDecisionService := new(MockDecisionService)
+	mockDecisionService.On("GetFeatureDecision", testDecisionContext, testUserContext).Return(expectedFeatureDecision, nil)
+
+	client := OptimizelyClient{
+		ConfigManager:   mockConfigManager,
+		DecisionService: mockDecisionService,
+		logger:          logging.GetLogger("", ""),
+	}
+
+	type jsonConfig struct {
+		Field1 int    `json:"field1"`
+		Field2 string `json:"field2"`
+	}
+	var cfg struct {
+		Str    string     `optimizely:"var_str"`
+		Bool   bool       `optimizely:"var_bool"`
+		Int    int64      `optimizely:"var_int"`
+		Double float64    `optimizely:"var_double"`
+		JSON   jsonConfig `optimizely:"var_json"`
+		Other  string
+	}
+	err := client.DecodeFeatureVariables(testFeatureKey, testUserContext, &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "var", cfg.Str)
+	assert.True(t, cfg.Bool)
+	assert.Equal(t, int64(20), cfg.Int)
+	assert.Equal(t, 2.5, cfg.Double)
+	assert.Equal(t, jsonConfig{Field1: 12, Field2: "some_value"}, cfg.JSON)
+	assert.Equal(t, "", cfg.Other)
+
+	var invalidCfg struct {
+		Str     int    `optimizely:"var_str"`
+		Missing string `optimizely:"var_missing"`
+		Bool    bool   `optimizely:"var_bool"`
+	}
+	err = client.DecodeFeatureVariables(testFeatureKey, testUserContext, &invalidCfg)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `field "Str": variable "var_str" of type "string" cannot be stored in a field of type int`)
+		assert.Contains(t, err.Error(), `field "Missing": variable "var_missing" does not exist`)
+	}
+	assert.True(t, invalidCfg.Bool)
+}
+
+func TestDecodeFeatureVariablesWithoutFeature(t *testing.T) {
+	invalidFeatureKey := "non-existent-feature"
+	testUserContext := entities.UserContext{ID: "test_user_1"}
+
+	mockConfig := new(MockProjectConfig)
+	mockConfig.On("GetFeatureByKey", invalidFeatureKey).Return(entities.Feature{}, errors.New(""))
+	mockConfigManager := new(MockProjectConfigManager)
+	mockConfigManager.On("GetConfig").Return(mockConfig, nil)
+
+	client := OptimizelyClient{
+		ConfigManager:   mockConfigManager,
+		DecisionService: new(MockDecisionService),
+		logger:          logging.GetLogger("", ""),
+	}
+
+	var cfg struct {
+		Str string `optimizely:"var_str"`
+	}
+	err := client.DecodeFeatureVariables(invalidFeatureKey, testUserContext, &cfg)
+	assert.Equal(t, errors.New(`feature "non-existent-feature" does not exist`), err)
+
+	err = client.DecodeFeatureVariables(invalidFeatureKey, testUserContext, cfg)
+	assert.Error(t, err)
+}
+
 func TestGetAllFeatureVariablesWithoutFeature(t *testing.T) {
 	invalidFeatureKey := "non-existent-feature"
 	testUserContext := entities.UserContext{ID: "test_user_1"}
@@ -2067,6 +2228,39 @@ func (s *ClientTestSuiteAB) TestActivate() {
 	s.mockEventProcessor.AssertExpectations(s.T())
 }
 
+func (s *ClientTestSuiteAB) TestActivateWithContext() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	testUserContext := entities.UserContext{ID: "test_user_1"}
+	testExperiment := makeTestExperiment("test_exp_1")
+	s.mockConfig.On("GetExperimentByKey", "test_exp_1").Return(testExperiment, nil)
+
+	testDecisionContext := decision.ExperimentDecisionContext{
+		Experiment:    &testExperiment,
+		ProjectConfig: s.mockConfig,
+	}
+	expectedVariation := testExperiment.Variations["v2"]
+	expectedExperimentDecision := decision.ExperimentDecision{
+		Variation: &expectedVariation,
+	}
+	mockDecisionService := new(MockContextDecisionService)
+	mockDecisionService.On("GetExperimentDecisionWithContext", ctx, testDecisionContext, testUserContext).Return(expectedExperimentDecision, nil)
+	s.mockEventProcessor.On("ProcessEvent", mock.AnythingOfType("event.UserEvent"))
+
+	testClient := OptimizelyClient{
+		ConfigManager:   s.mockConfigManager,
+		DecisionService: mockDecisionService,
+		EventProcessor:  s.mockEventProcessor,
+		logger:          logging.GetLogger("", ""),
+	}
+
+	variationKey, err := testClient.ActivateWithContext(ctx, "test_exp_1", testUserContext)
+	s.NoError(err)
+	s.Equal(expectedVariation.Key, variationKey)
+	mockDecisionService.AssertExpectations(s.T())
+	mockDecisionService.AssertNotCalled(s.T(), "GetExperimentDecision", mock.Anything, mock.Anything)
+}
+
 func (s *ClientTestSuiteAB) TestActivatePanics() {
 	// ensure that we recover if the SDK panics while getting variation
 	testUserContext := entities.UserContext{}
@@ -2225,6 +2419,39 @@ func (s *ClientTestSuiteFM) TestIsFeatureEnabled() {
 	s.mockDecisionService.AssertExpectations(s.T())
 }
 
+func (s *ClientTestSuiteFM) TestIsFeatureEnabledWithContext() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	testUserContext := entities.UserContext{ID: "test_user_1"}
+	testVariation := makeTestVariation("green", true)
+	testExperiment := makeTestExperimentWithVariations("number_1", []entities.Variation{testVariation})
+	testFeature := makeTestFeatureWithExperiment("feature_1", testExperiment)
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	testDecisionContext := decision.FeatureDecisionContext{
+		Feature:       &testFeature,
+		ProjectConfig: s.mockConfig,
+	}
+	expectedFeatureDecision := decision.FeatureDecision{
+		Experiment: testExperiment,
+		Variation:  &testVariation,
+		Source:     decision.Rollout,
+	}
+	mockDecisionService := new(MockContextDecisionService)
+	mockDecisionService.On("GetFeatureDecisionWithContext", ctx, testDecisionContext, testUserContext).Return(expectedFeatureDecision, nil)
+
+	client := OptimizelyClient{
+		ConfigManager:   s.mockConfigManager,
+		DecisionService: mockDecisionService,
+		EventProcessor:  s.mockEventProcessor,
+		logger:          logging.GetLogger("", ""),
+	}
+	result, err := client.IsFeatureEnabledWithContext(ctx, testFeature.Key, testUserContext)
+	s.NoError(err)
+	s.True(result)
+	mockDecisionService.AssertExpectations(s.T())
+}
+
 func (s *ClientTestSuiteFM) TestIsFeatureEnabledWithNotification() {
 	testUserContext := entities.UserContext{ID: "test_user_1"}
 
@@ -20,10 +20,13 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/optimizely/go-sdk/pkg/config"
+	"github.com/optimizely/go-sdk/pkg/decide"
 	"github.com/optimizely/go-sdk/pkg/decision"
+	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/event"
 	"github.com/optimizely/go-sdk/pkg/logging"
 	"github.com/optimizely/go-sdk/pkg/metrics"
@@ -44,9 +47,32 @@ type OptimizelyFactory struct {
 	eventProcessor     event.Processor
 	userProfileService decision.UserProfileService
 	overrideStore      decision.ExperimentOverrideStore
+	holdouts           []entities.Holdout
 	metricsRegistry    metrics.Registry
+	decideOptions      []decide.OptimizelyDecideOptions
+	decisionHooks      []decision.Hook
+	startupMode        StartupMode
+	startupTimeout     time.Duration
+	datafileCacheDir   string
+	datafileSource     config.DatafileSource
+	revisionPolicy     *config.MonotonicRevisionPolicy
+	strictValidation   bool
 }
 
+// StartupMode defines how the client is created while the first datafile is being fetched
+type StartupMode int
+
+const (
+	// StartupSync fetches the first datafile synchronously before the client is returned (default)
+	StartupSync StartupMode = iota
+	// StartupFailOpen returns the client right away and fetches the first datafile in the background,
+	// decisions fail or return the default values until the client is ready
+	StartupFailOpen
+	// StartupBlocking fetches the first datafile in the background and waits for it until the startup timeout
+	// expires, after which the client is returned in the fail-open state
+	StartupBlocking
+)
+
 // OptionFunc is used to provide custom client configuration to the OptimizelyFactory.
 type OptionFunc func(*OptimizelyFactory)
 
@@ -57,10 +83,15 @@ func (f *OptimizelyFactory) Client(clientOptions ...OptionFunc) (*OptimizelyClie
 		opt(f)
 	}
 
-	if f.SDKKey == "" && f.Datafile == nil && f.configManager == nil {
+	if f.SDKKey == "" && f.Datafile == nil && f.configManager == nil && f.datafileSource == nil {
 		return nil, errors.New("unable to instantiate client: no project config manager, SDK key, or a Datafile provided")
 	}
 
+	defaultDecideOptions, err := decide.NewOptions(f.decideOptions...)
+	if err != nil {
+		return nil, err
+	}
+
 	var metricsRegistry metrics.Registry
 	if f.metricsRegistry != nil {
 		metricsRegistry = f.metricsRegistry
@@ -77,16 +108,33 @@ func (f *OptimizelyFactory) Client(clientOptions ...OptionFunc) (*OptimizelyClie
 
 	eg := utils.NewExecGroup(ctx, logging.GetLogger(f.SDKKey, "ExecGroup"))
 	appClient := &OptimizelyClient{execGroup: eg,
-		notificationCenter: registry.GetNotificationCenter(f.SDKKey),
-		logger:             logging.GetLogger(f.SDKKey, "OptimizelyClient")}
+		notificationCenter:   registry.GetNotificationCenter(f.SDKKey),
+		logger:               logging.GetLogger(f.SDKKey, "OptimizelyClient"),
+		defaultDecideOptions: defaultDecideOptions}
 
 	if f.configManager != nil {
 		appClient.ConfigManager = f.configManager
+	} else if f.startupMode != StartupSync {
+		appClient.ConfigManager = config.NewAsyncPollingProjectConfigManager(
+			f.SDKKey,
+			config.WithInitialDatafile(f.Datafile),
+			config.WithDatafileAccessToken(f.DatafileAccessToken),
+			config.WithMetricsRegistry(metricsRegistry),
+			config.WithDatafileCacheDir(f.datafileCacheDir),
+			config.WithDatafileSource(f.datafileSource),
+			config.WithRevisionPolicy(f.revisionPolicy),
+			config.WithStrictValidation(f.strictValidation),
+		)
 	} else {
 		appClient.ConfigManager = config.NewPollingProjectConfigManager(
 			f.SDKKey,
 			config.WithInitialDatafile(f.Datafile),
 			config.WithDatafileAccessToken(f.DatafileAccessToken),
+			config.WithMetricsRegistry(metricsRegistry),
+			config.WithDatafileCacheDir(f.datafileCacheDir),
+			config.WithDatafileSource(f.datafileSource),
+			config.WithRevisionPolicy(f.revisionPolicy),
+			config.WithStrictValidation(f.strictValidation),
 		)
 	}
 
@@ -113,20 +161,50 @@ func (f *OptimizelyFactory) Client(clientOptions ...OptionFunc) (*OptimizelyClie
 		if f.overrideStore != nil {
 			experimentServiceOptions = append(experimentServiceOptions, decision.WithOverrideStore(f.overrideStore))
 		}
+		// the same holdouts apply to the experiment and feature decisions
+		holdoutService := decision.NewHoldoutService(f.SDKKey, f.holdouts...)
+		experimentServiceOptions = append(experimentServiceOptions, decision.WithHoldoutService(holdoutService))
 		compositeExperimentService := decision.NewCompositeExperimentService(f.SDKKey, experimentServiceOptions...)
-		compositeService := decision.NewCompositeService(f.SDKKey, decision.WithCompositeExperimentService(compositeExperimentService))
+		compositeService := decision.NewCompositeService(f.SDKKey, decision.WithCompositeExperimentService(compositeExperimentService),
+			decision.WithCompositeHoldoutService(holdoutService))
 		appClient.DecisionService = compositeService
 	}
 
+	if len(f.decisionHooks) > 0 {
+		appClient.DecisionService = decision.NewHookService(appClient.DecisionService, f.decisionHooks...)
+	}
+
 	// Initialize the default services with the execution context
 	if pollingConfigManager, ok := appClient.ConfigManager.(*config.PollingProjectConfigManager); ok {
-		eg.Go(pollingConfigManager.Start)
+		if f.startupMode != StartupSync {
+			eg.Go(func(ctx context.Context) {
+				// a cached datafile is refreshed right away, as it may be outdated
+				if pollingConfigManager.ConfigSource() != config.ConfigSourceInitialDatafile {
+					pollingConfigManager.SyncConfig() // initial poll
+				}
+				pollingConfigManager.Start(ctx)
+			})
+		} else {
+			eg.Go(pollingConfigManager.Start)
+		}
+	} else if fileConfigManager, ok := appClient.ConfigManager.(*config.FileProjectConfigManager); ok {
+		eg.Go(fileConfigManager.Start)
+	} else if streamingConfigManager, ok := appClient.ConfigManager.(*config.StreamingProjectConfigManager); ok {
+		eg.Go(streamingConfigManager.Start)
 	}
 
 	if batchProcessor, ok := appClient.EventProcessor.(*event.BatchEventProcessor); ok {
 		eg.Go(batchProcessor.Start)
 	}
 
+	if f.startupMode == StartupBlocking {
+		readyCtx, cancel := context.WithTimeout(ctx, f.startupTimeout)
+		defer cancel()
+		if err := appClient.WaitForReady(readyCtx); err != nil {
+			appClient.logger.Warning(fmt.Sprintf("Client is not ready after %v, continuing without a project config: %v", f.startupTimeout, err))
+		}
+	}
+
 	return appClient, nil
 }
 
@@ -181,6 +259,15 @@ func WithExperimentOverrides(overrideStore decision.ExperimentOverrideStore) Opt
 	}
 }
 
+// WithHoldouts sets the global holdouts of the local policy, which apply along with the holdouts of the datafile to the
+// default decision service, see decision.NewLocalHoldout. The impressions of the users held out by a local holdout
+// have the holdout rule type and the ID of the holdout as experiment and campaign IDs.
+func WithHoldouts(holdouts ...entities.Holdout) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.holdouts = append(f.holdouts, holdouts...)
+	}
+}
+
 // WithBatchEventProcessor sets event processor on a client.
 func WithBatchEventProcessor(batchSize, queueSize int, flushInterval time.Duration) OptionFunc {
 	return func(f *OptimizelyFactory) {
@@ -217,6 +304,67 @@ func WithMetricsRegistry(metricsRegistry metrics.Registry) OptionFunc {
 	}
 }
 
+// WithDefaultDecideOptions sets the decide options applied to every decision made by user contexts of the client.
+func WithDefaultDecideOptions(decideOptions []decide.OptimizelyDecideOptions) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.decideOptions = decideOptions
+	}
+}
+
+// WithDecisionHooks adds hooks which run, in the given order, around every feature and experiment decision of the client.
+func WithDecisionHooks(hooks ...decision.Hook) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.decisionHooks = append(f.decisionHooks, hooks...)
+	}
+}
+
+// WithFailOpenStartup returns the client without waiting for the first datafile, see StartupFailOpen.
+func WithFailOpenStartup() OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.startupMode = StartupFailOpen
+		f.startupTimeout = 0
+	}
+}
+
+// WithBlockingStartup waits at most the given timeout for the first datafile before returning the client, see StartupBlocking.
+func WithBlockingStartup(timeout time.Duration) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.startupMode = StartupBlocking
+		f.startupTimeout = timeout
+	}
+}
+
+// WithDatafileCacheDir caches the last valid datafile in the given directory, the cached datafile is used at startup
+// when no datafile is passed to the factory.
+func WithDatafileCacheDir(dir string) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.datafileCacheDir = dir
+	}
+}
+
+// WithDatafileSource sets the source of the datafiles used by the default polling config manager, see config.DatafileSource.
+func WithDatafileSource(source config.DatafileSource) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.datafileSource = source
+	}
+}
+
+// WithRevisionPolicy sets the policy rejecting older datafile revisions in the default polling config manager, see
+// config.MonotonicRevisionPolicy.
+func WithRevisionPolicy(policy *config.MonotonicRevisionPolicy) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.revisionPolicy = policy
+	}
+}
+
+// WithStrictValidation makes the default polling config manager reject the datafiles with validation errors, see
+// datafileprojectconfig.Validate.
+func WithStrictValidation() OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.strictValidation = true
+	}
+}
+
 // StaticClient returns a client initialized with a static project config.
 func (f *OptimizelyFactory) StaticClient() (optlyClient *OptimizelyClient, err error) {
 
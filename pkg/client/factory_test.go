@@ -19,13 +19,19 @@ package client
 import (
 	"context"
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/optimizely/go-sdk/pkg/config"
+	"github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig"
+	"github.com/optimizely/go-sdk/pkg/decide"
 	"github.com/optimizely/go-sdk/pkg/decision"
+	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/event"
 	"github.com/optimizely/go-sdk/pkg/metrics"
 	"github.com/optimizely/go-sdk/pkg/utils"
@@ -177,6 +183,29 @@ func TestClientWithEventDispatcher(t *testing.T) {
 	assert.Equal(t, dispatcher, mockEventDispatcher)
 }
 
+func TestClientWithDefaultDecideOptions(t *testing.T) {
+	decideOptions := []decide.OptimizelyDecideOptions{
+		decide.DisableDecisionEvent,
+		decide.EnabledFlagsOnly,
+	}
+	factory := OptimizelyFactory{SDKKey: "1212"}
+	optimizelyClient, err := factory.Client(WithDefaultDecideOptions(decideOptions))
+	assert.NoError(t, err)
+	assert.Equal(t, &decide.Options{DisableDecisionEvent: true, EnabledFlagsOnly: true}, optimizelyClient.defaultDecideOptions)
+
+	factory = OptimizelyFactory{SDKKey: "1212"}
+	optimizelyClient, err = factory.Client()
+	assert.NoError(t, err)
+	assert.Equal(t, &decide.Options{}, optimizelyClient.defaultDecideOptions)
+}
+
+func TestClientWithInvalidDefaultDecideOptions(t *testing.T) {
+	factory := OptimizelyFactory{SDKKey: "1212"}
+	optimizelyClient, err := factory.Client(WithDefaultDecideOptions([]decide.OptimizelyDecideOptions{"INVALID"}))
+	assert.Error(t, err)
+	assert.Nil(t, optimizelyClient)
+}
+
 func TestClientMetrics(t *testing.T) {
 	factory := OptimizelyFactory{SDKKey: "1212"}
 
@@ -201,3 +230,192 @@ func TestClientWithDatafileAccessToken(t *testing.T) {
 
 	assert.Equal(t, accessToken, factory.DatafileAccessToken)
 }
+
+func TestClientWithFailOpenStartup(t *testing.T) {
+	factory := OptimizelyFactory{}
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	configManager := config.NewAsyncPollingProjectConfigManager("", config.WithRequester(mockRequester), config.WithPollingInterval(time.Hour))
+
+	optimizelyClient, err := factory.Client(WithConfigManager(configManager), WithFailOpenStartup())
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	assert.NoError(t, optimizelyClient.WaitForReady(context.Background()))
+	projectConfig, err := optimizelyClient.ConfigManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+}
+
+func TestClientWithBlockingStartup(t *testing.T) {
+	factory := OptimizelyFactory{}
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	configManager := config.NewAsyncPollingProjectConfigManager("", config.WithRequester(mockRequester), config.WithPollingInterval(time.Hour))
+
+	optimizelyClient, err := factory.Client(WithConfigManager(configManager), WithBlockingStartup(time.Second))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	select {
+	case <-optimizelyClient.Ready():
+	default:
+		t.Fatal("client should be ready once the blocking startup returns")
+	}
+}
+
+func TestClientWithBlockingStartupTimeout(t *testing.T) {
+	factory := OptimizelyFactory{}
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{}`), http.Header{}, http.StatusInternalServerError, errors.New("unavailable"))
+	configManager := config.NewAsyncPollingProjectConfigManager("", config.WithRequester(mockRequester), config.WithPollingInterval(time.Hour))
+
+	start := time.Now()
+	optimizelyClient, err := factory.Client(WithConfigManager(configManager), WithBlockingStartup(50*time.Millisecond))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	userContext := optimizelyClient.CreateUserContext("test_user", nil)
+	decision := userContext.Decide("test_flag")
+	assert.False(t, decision.Enabled)
+	assert.Equal(t, []string{decide.GetDecideMessage(decide.SDKNotReady)}, decision.Reasons)
+}
+
+func TestClientWithDatafileCacheDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datafile-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "1212.json"), []byte(`{"revision":"41","version": "4"}`), 0600))
+
+	factory := OptimizelyFactory{SDKKey: "1212"}
+	optimizelyClient, err := factory.Client(WithDatafileCacheDir(dir), WithFailOpenStartup())
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	// the client is ready with the cached datafile before the first fetch completes
+	state := optimizelyClient.ReadinessState()
+	assert.True(t, state.Ready)
+	assert.Equal(t, config.ConfigSourceCache, state.Source)
+	projectConfig, err := optimizelyClient.ConfigManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "41", projectConfig.GetRevision())
+}
+
+func TestClientWithDatafileSource(t *testing.T) {
+	factory := OptimizelyFactory{}
+	source := config.NewMemoryDatafileSource([]byte(`{"revision":"42","version": "4"}`))
+	optimizelyClient, err := factory.Client(WithDatafileSource(source))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	projectConfig, err := optimizelyClient.ConfigManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+}
+
+func TestClientWithStrictValidation(t *testing.T) {
+	factory := OptimizelyFactory{}
+	source := config.NewMemoryDatafileSource([]byte(`{"revision":"42","version": "4","featureFlags":[{"id":"f1","key":"flag_1","experimentIds":["e1"]}]}`))
+	optimizelyClient, err := factory.Client(WithDatafileSource(source), WithStrictValidation())
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	projectConfig, err := optimizelyClient.ConfigManager.GetConfig()
+	assert.Nil(t, projectConfig)
+	assert.IsType(t, datafileprojectconfig.ValidationError{}, err)
+}
+
+func TestClientWithHoldouts(t *testing.T) {
+	datafile := []byte(`{"revision": "42", "version": "4",
+		"experiments": [{"id": "e2", "key": "exp_1", "layerId": "l2", "status": "Running", "audienceIds": [],
+			"variations": [{"id": "v2", "key": "a"}], "trafficAllocation": [{"entityId": "v2", "endOfRange": 10000}]}],
+		"featureFlags": [{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": [], "variables": []}],
+		"rollouts": [{"id": "r1", "experiments": [{"id": "e1", "key": "rule_1", "layerId": "r1", "status": "Running", "audienceIds": [],
+			"variations": [{"id": "v1", "key": "on", "featureEnabled": true}], "trafficAllocation": [{"entityId": "v1", "endOfRange": 10000}]}]}]}`)
+	factory := OptimizelyFactory{}
+	source := config.NewMemoryDatafileSource(datafile)
+
+	optimizelyClient, err := factory.Client(WithDatafileSource(source), WithEventProcessor(new(MockEventProcessor)))
+	assert.NoError(t, err)
+	enabled, err := optimizelyClient.IsFeatureEnabled("flag_1", entities.UserContext{ID: "test_user"})
+	optimizelyClient.Close()
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	holdout, err := decision.NewLocalHoldout("h1", "global_holdout", 100)
+	assert.NoError(t, err)
+	eventProcessor := new(MockEventProcessor)
+	eventProcessor.On("ProcessEvent", mock.AnythingOfType("event.UserEvent"))
+	optimizelyClient, err = factory.Client(WithDatafileSource(source), WithEventProcessor(eventProcessor), WithHoldouts(holdout))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+	enabled, err = optimizelyClient.IsFeatureEnabled("flag_1", entities.UserContext{ID: "test_user"})
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+	if eventProcessor.AssertNumberOfCalls(t, "ProcessEvent", 1) {
+		userEvent := eventProcessor.Calls[0].Arguments.Get(0).(event.UserEvent)
+		assert.Equal(t, "h1", userEvent.Impression.ExperimentID)
+		assert.Equal(t, "h1", userEvent.Impression.CampaignID)
+		assert.Equal(t, "h1_off", userEvent.Impression.VariationID)
+		assert.Equal(t, event.RuleTypeHoldout, userEvent.Impression.Metadata.RuleType)
+		assert.Equal(t, "global_holdout", userEvent.Impression.Metadata.RuleKey)
+	}
+
+	// a held out user is not activated, the impression of the holdout is sent instead
+	variation, err := optimizelyClient.Activate("exp_1", entities.UserContext{ID: "test_user"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", variation)
+	if eventProcessor.AssertNumberOfCalls(t, "ProcessEvent", 2) {
+		userEvent := eventProcessor.Calls[1].Arguments.Get(0).(event.UserEvent)
+		assert.Equal(t, "h1", userEvent.Impression.ExperimentID)
+		assert.Equal(t, event.RuleTypeHoldout, userEvent.Impression.Metadata.RuleType)
+		assert.Equal(t, "", userEvent.Impression.Metadata.FlagKey)
+	}
+}
+
+func TestClientWithFileConfigManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-manager")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datafile.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"revision":"1","version": "4"}`), 0600))
+
+	factory := OptimizelyFactory{}
+	configManager := config.NewFileProjectConfigManager("", path, config.WithWatchInterval(10*time.Millisecond))
+	optimizelyClient, err := factory.Client(WithConfigManager(configManager))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	// the factory starts watching the datafile
+	modTime := time.Now().Add(time.Hour)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"revision":"2","version": "4"}`), 0600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	assert.Eventually(t, func() bool {
+		return optimizelyClient.GetOptimizelyConfig().Revision == "2"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestClientWithDecisionHooks(t *testing.T) {
+	factory := OptimizelyFactory{}
+	mockDatafile := []byte(`{"version":"4"}`)
+	configManager := config.NewStaticProjectConfigManagerWithOptions("", config.WithInitialDatafile(mockDatafile))
+	decisionService := new(MockDecisionService)
+	expectedFeatureDecision := decision.FeatureDecision{Decision: decision.Decision{Reason: "vetoed"}}
+	veto := decision.HookFuncs{
+		Feature: func(next decision.FeatureDecisionFunc) decision.FeatureDecisionFunc {
+			return func(ctx context.Context, decisionContext decision.FeatureDecisionContext, userContext entities.UserContext) (decision.FeatureDecision, error) {
+				return expectedFeatureDecision, nil
+			}
+		},
+	}
+
+	optimizelyClient, err := factory.Client(WithConfigManager(configManager), WithDecisionService(decisionService), WithDecisionHooks(veto))
+	assert.NoError(t, err)
+	assert.IsType(t, &decision.HookService{}, optimizelyClient.DecisionService)
+
+	featureDecision, err := optimizelyClient.DecisionService.GetFeatureDecision(decision.FeatureDecisionContext{}, entities.UserContext{ID: "test_user"})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedFeatureDecision, featureDecision)
+	decisionService.AssertNotCalled(t, "GetFeatureDecision", mock.Anything, mock.Anything)
+}
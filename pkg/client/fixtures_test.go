@@ -18,6 +18,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/optimizely/go-sdk/pkg/config"
@@ -35,6 +36,7 @@ import (
 type MockProjectConfig struct {
 	config.ProjectConfig
 	mock.Mock
+	sendFlagDecisions bool
 }
 
 func (c *MockProjectConfig) GetEventByKey(string) (entities.Event, error) {
@@ -77,6 +79,9 @@ func (c *MockProjectConfig) GetAnonymizeIP() bool {
 func (c *MockProjectConfig) GetBotFiltering() bool {
 	return false
 }
+func (c *MockProjectConfig) GetSendFlagDecisions() bool {
+	return c.sendFlagDecisions
+}
 
 type MockProjectConfigManager struct {
 	projectConfig config.ProjectConfig
@@ -135,6 +140,20 @@ func (m *MockDecisionService) OnDecision(callback func(note notification.Decisio
 	return id, err
 }
 
+type MockContextDecisionService struct {
+	MockDecisionService
+}
+
+func (m *MockContextDecisionService) GetFeatureDecisionWithContext(ctx context.Context, decisionContext decision.FeatureDecisionContext, userContext entities.UserContext) (decision.FeatureDecision, error) {
+	args := m.Called(ctx, decisionContext, userContext)
+	return args.Get(0).(decision.FeatureDecision), args.Error(1)
+}
+
+func (m *MockContextDecisionService) GetExperimentDecisionWithContext(ctx context.Context, decisionContext decision.ExperimentDecisionContext, userContext entities.UserContext) (decision.ExperimentDecision, error) {
+	args := m.Called(ctx, decisionContext, userContext)
+	return args.Get(0).(decision.ExperimentDecision), args.Error(1)
+}
+
 type MockEventProcessor struct {
 	event.Processor
 	mock.Mock
@@ -0,0 +1,57 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client //
+package client
+
+import (
+	"github.com/optimizely/go-sdk/pkg/optimizelyjson"
+)
+
+// OptimizelyDecision holds the result of a flag decision made for a user context
+type OptimizelyDecision struct {
+	VariationKey string                         `json:"variationKey"`
+	Enabled      bool                           `json:"enabled"`
+	Variables    *optimizelyjson.OptimizelyJSON `json:"variables"`
+	RuleKey      string                         `json:"ruleKey"`
+	FlagKey      string                         `json:"flagKey"`
+	UserContext  OptimizelyUserContext          `json:"userContext"`
+	Reasons      []string                       `json:"reasons"`
+}
+
+// NewOptimizelyDecision returns a new instance of OptimizelyDecision
+func NewOptimizelyDecision(variationKey, ruleKey, flagKey string, enabled bool, variables *optimizelyjson.OptimizelyJSON,
+	userContext OptimizelyUserContext, reasons []string) OptimizelyDecision {
+	return OptimizelyDecision{
+		VariationKey: variationKey,
+		Enabled:      enabled,
+		Variables:    variables,
+		RuleKey:      ruleKey,
+		FlagKey:      flagKey,
+		UserContext:  userContext,
+		Reasons:      reasons,
+	}
+}
+
+// NewErrorDecision returns a decision with the given error as its only reason
+func NewErrorDecision(key string, user OptimizelyUserContext, err error) OptimizelyDecision {
+	return OptimizelyDecision{
+		FlagKey:     key,
+		UserContext: user,
+		Variables:   optimizelyjson.NewOptimizelyJSONfromMap(map[string]interface{}{}),
+		Reasons:     []string{err.Error()},
+	}
+}
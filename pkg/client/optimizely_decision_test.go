@@ -0,0 +1,54 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client //
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/optimizelyjson"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptimizelyDecision(t *testing.T) {
+	userContext := newOptimizelyUserContext(nil, "test_user", nil)
+	variables := optimizelyjson.NewOptimizelyJSONfromMap(map[string]interface{}{"var_key": "value"})
+
+	decision := NewOptimizelyDecision("variation_key", "rule_key", "flag_key", true, variables, userContext, []string{"reason"})
+	assert.Equal(t, "variation_key", decision.VariationKey)
+	assert.Equal(t, "rule_key", decision.RuleKey)
+	assert.Equal(t, "flag_key", decision.FlagKey)
+	assert.True(t, decision.Enabled)
+	assert.Equal(t, variables, decision.Variables)
+	assert.Equal(t, userContext, decision.UserContext)
+	assert.Equal(t, []string{"reason"}, decision.Reasons)
+}
+
+func TestNewErrorDecision(t *testing.T) {
+	userContext := newOptimizelyUserContext(nil, "test_user", nil)
+
+	decision := NewErrorDecision("flag_key", userContext, errors.New("some error"))
+	assert.Equal(t, "", decision.VariationKey)
+	assert.Equal(t, "", decision.RuleKey)
+	assert.Equal(t, "flag_key", decision.FlagKey)
+	assert.False(t, decision.Enabled)
+	assert.Equal(t, map[string]interface{}{}, decision.Variables.ToMap())
+	assert.Equal(t, userContext, decision.UserContext)
+	assert.Equal(t, []string{"some error"}, decision.Reasons)
+}
@@ -0,0 +1,180 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client //
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/optimizely/go-sdk/pkg/decide"
+	"github.com/optimizely/go-sdk/pkg/decision"
+	"github.com/optimizely/go-sdk/pkg/entities"
+)
+
+// OptimizelyUserContext defines user contexts that the SDK will use to make decisions for
+type OptimizelyUserContext struct {
+	UserID     string                 `json:"userId"`
+	Attributes map[string]interface{} `json:"attributes"`
+
+	optimizely            *OptimizelyClient
+	forcedDecisionService *decision.ForcedDecisionService
+	mutex                 *sync.RWMutex
+}
+
+// returns an instance of the optimizely user context.
+func newOptimizelyUserContext(optimizely *OptimizelyClient, userID string, attributes map[string]interface{}) OptimizelyUserContext {
+	// store a copy of the provided attributes so it isn't affected by changes made afterwards.
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	attributesCopy := copyUserAttributes(attributes)
+	return OptimizelyUserContext{
+		UserID:     userID,
+		Attributes: attributesCopy,
+		optimizely: optimizely,
+		mutex:      new(sync.RWMutex),
+	}
+}
+
+// GetOptimizely returns the optimizely client instance that created this user context
+func (o OptimizelyUserContext) GetOptimizely() *OptimizelyClient {
+	return o.optimizely
+}
+
+// GetUserID returns the user ID for the user context
+func (o OptimizelyUserContext) GetUserID() string {
+	return o.UserID
+}
+
+// GetUserAttributes returns a copy of the user attributes
+func (o OptimizelyUserContext) GetUserAttributes() map[string]interface{} {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return copyUserAttributes(o.Attributes)
+}
+
+// SetAttribute sets an attribute for a given key.
+func (o *OptimizelyUserContext) SetAttribute(key string, value interface{}) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.Attributes == nil {
+		o.Attributes = make(map[string]interface{})
+	}
+	o.Attributes[key] = value
+}
+
+// SetForcedDecision forces the given flag, or a single rule of the flag when context.RuleKey is set, to a variation.
+// Forced decisions are checked before the user profile service and bucketing.
+func (o *OptimizelyUserContext) SetForcedDecision(context decision.OptimizelyDecisionContext, forcedDecision decision.OptimizelyForcedDecision) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.forcedDecisionService == nil {
+		o.forcedDecisionService = decision.NewForcedDecisionService(o.UserID)
+	}
+	return o.forcedDecisionService.SetForcedDecision(context, forcedDecision)
+}
+
+// GetForcedDecision returns the forced decision set for the given context
+func (o *OptimizelyUserContext) GetForcedDecision(context decision.OptimizelyDecisionContext) (decision.OptimizelyForcedDecision, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.forcedDecisionService == nil {
+		return decision.OptimizelyForcedDecision{}, errors.New("forced decision not found")
+	}
+	return o.forcedDecisionService.GetForcedDecision(context)
+}
+
+// RemoveForcedDecision removes the forced decision set for the given context, returns false if none was set
+func (o *OptimizelyUserContext) RemoveForcedDecision(context decision.OptimizelyDecisionContext) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.forcedDecisionService == nil {
+		return false
+	}
+	return o.forcedDecisionService.RemoveForcedDecision(context)
+}
+
+// RemoveAllForcedDecisions removes all the forced decisions of the user context
+func (o *OptimizelyUserContext) RemoveAllForcedDecisions() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.forcedDecisionService == nil {
+		return true
+	}
+	return o.forcedDecisionService.RemoveAllForcedDecisions()
+}
+
+// Decide returns a decision result for a given flag key.
+// The decision is made once and both the enabled state and all the flag variables are taken from it.
+// The given options are combined with the default decide options of the client.
+func (o *OptimizelyUserContext) Decide(key string, options ...decide.OptimizelyDecideOptions) OptimizelyDecision {
+	return o.DecideWithContext(context.Background(), key, options...)
+}
+
+// DecideWithContext does the same as Decide, with the decision bound to the given context.
+func (o *OptimizelyUserContext) DecideWithContext(ctx context.Context, key string, options ...decide.OptimizelyDecideOptions) OptimizelyDecision {
+	return o.optimizely.decide(ctx, o.copy(), key, options...)
+}
+
+// DecideAll returns a key-map of decision results for all active flag keys.
+func (o *OptimizelyUserContext) DecideAll(options ...decide.OptimizelyDecideOptions) map[string]OptimizelyDecision {
+	return o.DecideAllWithContext(context.Background(), options...)
+}
+
+// DecideAllWithContext does the same as DecideAll, with the decisions bound to the given context.
+func (o *OptimizelyUserContext) DecideAllWithContext(ctx context.Context, options ...decide.OptimizelyDecideOptions) map[string]OptimizelyDecision {
+	return o.optimizely.decideAll(ctx, o.copy(), options...)
+}
+
+// DecideForKeys returns a key-map of decision results for multiple flag keys.
+func (o *OptimizelyUserContext) DecideForKeys(keys []string, options ...decide.OptimizelyDecideOptions) map[string]OptimizelyDecision {
+	return o.DecideForKeysWithContext(context.Background(), keys, options...)
+}
+
+// DecideForKeysWithContext does the same as DecideForKeys, with the decisions bound to the given context.
+func (o *OptimizelyUserContext) DecideForKeysWithContext(ctx context.Context, keys []string, options ...decide.OptimizelyDecideOptions) map[string]OptimizelyDecision {
+	return o.optimizely.decideForKeys(ctx, o.copy(), keys, options...)
+}
+
+// returns a snapshot of the user context which is safe to hand out along with a decision
+func (o *OptimizelyUserContext) copy() OptimizelyUserContext {
+	userContext := newOptimizelyUserContext(o.optimizely, o.UserID, o.GetUserAttributes())
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	userContext.forcedDecisionService = o.forcedDecisionService.CreateCopy()
+	return userContext
+}
+
+// toUserContext converts the optimizely user context into the user context used by the decision services
+func (o OptimizelyUserContext) toUserContext() entities.UserContext {
+	return entities.UserContext{
+		ID:         o.UserID,
+		Attributes: o.GetUserAttributes(),
+	}
+}
+
+func copyUserAttributes(attributes map[string]interface{}) (attributesCopy map[string]interface{}) {
+	if attributes != nil {
+		attributesCopy = make(map[string]interface{})
+		for k, v := range attributes {
+			attributesCopy[k] = v
+		}
+	}
+	return attributesCopy
+}
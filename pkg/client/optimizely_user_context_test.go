@@ -0,0 +1,547 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client //
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/decide"
+	"github.com/optimizely/go-sdk/pkg/decision"
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/optimizely/go-sdk/pkg/event"
+	"github.com/optimizely/go-sdk/pkg/logging"
+	"github.com/optimizely/go-sdk/pkg/notification"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type OptimizelyUserContextTestSuite struct {
+	suite.Suite
+	mockConfig          *MockProjectConfig
+	mockConfigManager   *MockProjectConfigManager
+	mockDecisionService *MockDecisionService
+	mockEventProcessor  *MockProcessor
+	client              *OptimizelyClient
+}
+
+func (s *OptimizelyUserContextTestSuite) SetupTest() {
+	s.mockConfig = new(MockProjectConfig)
+	s.mockConfigManager = new(MockProjectConfigManager)
+	s.mockConfigManager.On("GetConfig").Return(s.mockConfig, nil)
+	s.mockDecisionService = new(MockDecisionService)
+	s.mockEventProcessor = new(MockProcessor)
+	s.client = &OptimizelyClient{
+		ConfigManager:   s.mockConfigManager,
+		DecisionService: s.mockDecisionService,
+		EventProcessor:  s.mockEventProcessor,
+		logger:          logging.GetLogger("", ""),
+	}
+}
+
+func (s *OptimizelyUserContextTestSuite) TestOptimizelyUserContextWithAttributes() {
+	attributes := map[string]interface{}{"key1": 1212, "key2": 1213}
+	userContext := s.client.CreateUserContext("test_user", attributes)
+
+	s.Equal(s.client, userContext.GetOptimizely())
+	s.Equal("test_user", userContext.GetUserID())
+	s.Equal(attributes, userContext.GetUserAttributes())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestOptimizelyUserContextNoAttributes() {
+	userContext := s.client.CreateUserContext("test_user", nil)
+
+	s.Equal("test_user", userContext.GetUserID())
+	s.Equal(map[string]interface{}{}, userContext.GetUserAttributes())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestOptimizelyUserContextCopiesAttributes() {
+	attributes := map[string]interface{}{"key1": 1212}
+	userContext := s.client.CreateUserContext("test_user", attributes)
+
+	attributes["key1"] = 1313
+	s.Equal(map[string]interface{}{"key1": 1212}, userContext.GetUserAttributes())
+
+	userAttributes := userContext.GetUserAttributes()
+	userAttributes["key1"] = 1414
+	s.Equal(map[string]interface{}{"key1": 1212}, userContext.GetUserAttributes())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestSetAttribute() {
+	userContext := s.client.CreateUserContext("test_user", nil)
+
+	userContext.SetAttribute("key1", "value1")
+	userContext.SetAttribute("key2", true)
+	userContext.SetAttribute("key1", 1.5)
+	s.Equal(map[string]interface{}{"key1": 1.5, "key2": true}, userContext.GetUserAttributes())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideFeatureTest() {
+	testVariation := makeTestVariation("green", true)
+	testVariation.Variables = map[string]entities.VariationVariable{"1": {ID: "1", Value: "20"}}
+	testExperiment := makeTestExperimentWithVariations("number_1", []entities.Variation{testVariation})
+	testFeature := makeTestFeatureWithExperiment("feature_1", testExperiment)
+	testFeature.VariableMap = map[string]entities.Variable{
+		"var_int": {ID: "1", Key: "var_int", DefaultValue: "10", Type: entities.Integer},
+		"var_str": {ID: "2", Key: "var_str", DefaultValue: "default", Type: entities.String},
+	}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	testDecisionContext := decision.FeatureDecisionContext{
+		Feature:       &testFeature,
+		ProjectConfig: s.mockConfig,
+		Reasons:       decide.NewDecisionReasons(&decide.Options{}),
+	}
+	testUserContext := entities.UserContext{ID: "test_user", Attributes: map[string]interface{}{}}
+	expectedFeatureDecision := decision.FeatureDecision{
+		Experiment: testExperiment,
+		Variation:  &testVariation,
+		Source:     decision.FeatureTest,
+	}
+	s.mockDecisionService.On("GetFeatureDecision", testDecisionContext, testUserContext).Return(expectedFeatureDecision, nil)
+	s.mockEventProcessor.On("ProcessEvent", mock.AnythingOfType("event.UserEvent")).Return(true)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key)
+
+	s.True(optimizelyDecision.Enabled)
+	s.Equal("green", optimizelyDecision.VariationKey)
+	s.Equal("number_1", optimizelyDecision.RuleKey)
+	s.Equal("feature_1", optimizelyDecision.FlagKey)
+	s.Equal(map[string]interface{}{"var_int": 20, "var_str": "default"}, optimizelyDecision.Variables.ToMap())
+	s.Equal(userContext.GetUserID(), optimizelyDecision.UserContext.GetUserID())
+	s.Equal(userContext.GetUserAttributes(), optimizelyDecision.UserContext.GetUserAttributes())
+	s.Empty(optimizelyDecision.Reasons)
+	s.Len(s.mockEventProcessor.Events, 1)
+	s.Equal(event.DecisionMetadata{FlagKey: "feature_1", RuleKey: "number_1", RuleType: event.RuleTypeFeatureTest, VariationKey: "green", Enabled: true},
+		s.mockEventProcessor.Events[0].Impression.Metadata)
+	s.mockDecisionService.AssertExpectations(s.T())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideRolloutDoesNotSendImpression() {
+	testVariation := makeTestVariation("on", true)
+	testExperiment := makeTestExperimentWithVariations("rollout_rule", []entities.Variation{testVariation})
+	testFeature := entities.Feature{Key: "feature_1", Rollout: entities.Rollout{ID: "1", Experiments: []entities.Experiment{testExperiment}}}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	expectedFeatureDecision := decision.FeatureDecision{
+		Experiment: testExperiment,
+		Variation:  &testVariation,
+		Source:     decision.Rollout,
+	}
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Return(expectedFeatureDecision, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key)
+
+	s.True(optimizelyDecision.Enabled)
+	s.Equal("on", optimizelyDecision.VariationKey)
+	s.Equal("rollout_rule", optimizelyDecision.RuleKey)
+	s.mockEventProcessor.AssertNotCalled(s.T(), "ProcessEvent", mock.Anything)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideRolloutSendsImpressionWithSendFlagDecisions() {
+	s.mockConfig.sendFlagDecisions = true
+	testVariation := makeTestVariation("on", true)
+	testExperiment := makeTestExperimentWithVariations("rollout_rule", []entities.Variation{testVariation})
+	testFeature := entities.Feature{Key: "feature_1", Rollout: entities.Rollout{ID: "1", Experiments: []entities.Experiment{testExperiment}}}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	expectedFeatureDecision := decision.FeatureDecision{
+		Experiment: testExperiment,
+		Variation:  &testVariation,
+		Source:     decision.Rollout,
+	}
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Return(expectedFeatureDecision, nil)
+	s.mockEventProcessor.On("ProcessEvent", mock.AnythingOfType("event.UserEvent")).Return(true)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key)
+
+	s.True(optimizelyDecision.Enabled)
+	s.mockEventProcessor.AssertNumberOfCalls(s.T(), "ProcessEvent", 1)
+	s.Equal(testExperiment.ID, s.mockEventProcessor.Events[0].Impression.ExperimentID)
+	s.Equal(event.DecisionMetadata{FlagKey: "feature_1", RuleKey: "rollout_rule", RuleType: event.RuleTypeRollout, VariationKey: "on", Enabled: true},
+		s.mockEventProcessor.Events[0].Impression.Metadata)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideHeldOut() {
+	testVariation := makeTestVariation("off", false)
+	testVariation.Variables = map[string]entities.VariationVariable{"1": {ID: "1", Value: "20"}}
+	testHoldout := makeTestExperimentWithVariations("global_holdout", []entities.Variation{testVariation})
+	testFeature := entities.Feature{Key: "feature_1", VariableMap: map[string]entities.Variable{
+		"var_int": {ID: "1", Key: "var_int", DefaultValue: "10", Type: entities.Integer},
+	}}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	expectedFeatureDecision := decision.FeatureDecision{
+		Decision:   decision.Decision{Reason: reasons.HeldOut},
+		Experiment: testHoldout,
+		Variation:  &testVariation,
+		Source:     decision.Holdout,
+	}
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Return(expectedFeatureDecision, nil)
+	s.mockEventProcessor.On("ProcessEvent", mock.AnythingOfType("event.UserEvent")).Return(true)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key)
+
+	s.False(optimizelyDecision.Enabled)
+	s.Equal("global_holdout", optimizelyDecision.RuleKey)
+	s.Equal(map[string]interface{}{"var_int": 10}, optimizelyDecision.Variables.ToMap())
+	s.mockEventProcessor.AssertNumberOfCalls(s.T(), "ProcessEvent", 1)
+	s.Equal(event.RuleTypeHoldout, s.mockEventProcessor.Events[0].Impression.Metadata.RuleType)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithNotification() {
+	testFeature := entities.Feature{Key: "feature_1"}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Return(decision.FeatureDecision{}, nil)
+
+	notificationCenter := notification.NewNotificationCenter()
+	var decisionNotification notification.DecisionNotification
+	_, err := notificationCenter.AddHandler(notification.Decision, func(payload interface{}) {
+		decisionNotification = payload.(notification.DecisionNotification)
+	})
+	s.NoError(err)
+	s.client.notificationCenter = notificationCenter
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key)
+
+	s.False(optimizelyDecision.Enabled)
+	s.Equal(notification.Flag, decisionNotification.Type)
+	s.Equal("test_user", decisionNotification.UserContext.ID)
+	s.Equal(map[string]interface{}{"flagKey": "feature_1", "enabled": false, "variables": map[string]interface{}{}, "variationKey": "",
+		"ruleKey": "", "reasons": []string{}, "decisionEventDispatched": false}, decisionNotification.DecisionInfo)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithAudienceTrace() {
+	testFeature := entities.Feature{Key: "feature_1"}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+	result := false
+	trace := &entities.TreeNodeTrace{AttributeName: "age", MatchType: "gt", ExpectedValue: 17, ActualValue: 15, Result: &result}
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Return(decision.FeatureDecision{}, nil).Run(func(args mock.Arguments) {
+		args.Get(0).(decision.FeatureDecisionContext).Reasons.AddAudienceTrace("rule_1", trace)
+	})
+
+	notificationCenter := notification.NewNotificationCenter()
+	var decisionNotification notification.DecisionNotification
+	_, err := notificationCenter.AddHandler(notification.Decision, func(payload interface{}) {
+		decisionNotification = payload.(notification.DecisionNotification)
+	})
+	s.NoError(err)
+	s.client.notificationCenter = notificationCenter
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key, decide.IncludeAudienceTrace)
+
+	s.Equal([]string{`Audience evaluation trace for rule "rule_1": age gt 17 (actual 15) => false`}, optimizelyDecision.Reasons)
+	s.Equal([]decide.AudienceTrace{{RuleKey: "rule_1", Trace: trace}}, decisionNotification.DecisionInfo["audienceTraces"])
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideInvalidFlagKey() {
+	s.mockConfig.On("GetFeatureByKey", "invalid").Return(entities.Feature{}, errors.New("not found"))
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide("invalid")
+
+	s.False(optimizelyDecision.Enabled)
+	s.Equal("invalid", optimizelyDecision.FlagKey)
+	s.Equal([]string{decide.GetDecideMessage(decide.FlagKeyInvalid, "invalid")}, optimizelyDecision.Reasons)
+	s.mockDecisionService.AssertNotCalled(s.T(), "GetFeatureDecision", mock.Anything, mock.Anything)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideSDKNotReady() {
+	client := &OptimizelyClient{logger: logging.GetLogger("", "")}
+
+	userContext := client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide("feature_1")
+
+	s.False(optimizelyDecision.Enabled)
+	s.Equal([]string{decide.GetDecideMessage(decide.SDKNotReady)}, optimizelyDecision.Reasons)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecidePanics() {
+	client := &OptimizelyClient{
+		ConfigManager:   s.mockConfigManager,
+		DecisionService: &PanickingDecisionService{},
+		logger:          logging.GetLogger("", ""),
+	}
+	s.mockConfig.On("GetFeatureByKey", "feature_1").Return(entities.Feature{Key: "feature_1"}, nil)
+
+	userContext := client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide("feature_1")
+
+	s.False(optimizelyDecision.Enabled)
+	s.Equal([]string{"I'm panicking"}, optimizelyDecision.Reasons)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideForKeys() {
+	testVariation := makeTestVariation("on", true)
+	testFeature1 := entities.Feature{Key: "feature_1"}
+	testFeature2 := entities.Feature{Key: "feature_2"}
+	s.mockConfig.On("GetFeatureByKey", testFeature1.Key).Return(testFeature1, nil)
+	s.mockConfig.On("GetFeatureByKey", testFeature2.Key).Return(testFeature2, nil)
+	s.mockDecisionService.On("GetFeatureDecision", s.getTestDecisionContext(&testFeature1, decide.Options{}), mock.Anything).
+		Return(decision.FeatureDecision{Variation: &testVariation, Source: decision.Rollout}, nil)
+	s.mockDecisionService.On("GetFeatureDecision", s.getTestDecisionContext(&testFeature2, decide.Options{}), mock.Anything).
+		Return(decision.FeatureDecision{}, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	decisions := userContext.DecideForKeys([]string{"feature_1", "feature_2"})
+
+	s.Len(decisions, 2)
+	s.True(decisions["feature_1"].Enabled)
+	s.False(decisions["feature_2"].Enabled)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideAll() {
+	testFeature1 := entities.Feature{Key: "feature_1"}
+	testFeature2 := entities.Feature{Key: "feature_2"}
+	s.mockConfig.On("GetFeatureList").Return([]entities.Feature{testFeature1, testFeature2})
+	s.mockConfig.On("GetFeatureByKey", testFeature1.Key).Return(testFeature1, nil)
+	s.mockConfig.On("GetFeatureByKey", testFeature2.Key).Return(testFeature2, nil)
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Return(decision.FeatureDecision{}, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	decisions := userContext.DecideAll()
+
+	s.Len(decisions, 2)
+	s.Equal("feature_1", decisions["feature_1"].FlagKey)
+	s.Equal("feature_2", decisions["feature_2"].FlagKey)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideAllSDKNotReady() {
+	client := &OptimizelyClient{logger: logging.GetLogger("", "")}
+
+	userContext := client.CreateUserContext("test_user", nil)
+	s.Len(userContext.DecideAll(), 0)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithDisableDecisionEvent() {
+	testVariation := makeTestVariation("green", true)
+	testExperiment := makeTestExperimentWithVariations("number_1", []entities.Variation{testVariation})
+	testFeature := makeTestFeatureWithExperiment("feature_1", testExperiment)
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	expectedFeatureDecision := decision.FeatureDecision{
+		Experiment: testExperiment,
+		Variation:  &testVariation,
+		Source:     decision.FeatureTest,
+	}
+	s.mockDecisionService.On("GetFeatureDecision", s.getTestDecisionContext(&testFeature, decide.Options{DisableDecisionEvent: true}), mock.Anything).
+		Return(expectedFeatureDecision, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key, decide.DisableDecisionEvent)
+
+	s.True(optimizelyDecision.Enabled)
+	s.Equal("green", optimizelyDecision.VariationKey)
+	s.mockEventProcessor.AssertNotCalled(s.T(), "ProcessEvent", mock.Anything)
+	s.mockDecisionService.AssertExpectations(s.T())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithDefaultDecideOptions() {
+	testVariation := makeTestVariation("green", true)
+	testExperiment := makeTestExperimentWithVariations("number_1", []entities.Variation{testVariation})
+	testFeature := makeTestFeatureWithExperiment("feature_1", testExperiment)
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	expectedFeatureDecision := decision.FeatureDecision{
+		Experiment: testExperiment,
+		Variation:  &testVariation,
+		Source:     decision.FeatureTest,
+	}
+	expectedOptions := decide.Options{DisableDecisionEvent: true, IgnoreUserProfileService: true}
+	s.mockDecisionService.On("GetFeatureDecision", s.getTestDecisionContext(&testFeature, expectedOptions), mock.Anything).
+		Return(expectedFeatureDecision, nil)
+
+	s.client.defaultDecideOptions = &decide.Options{DisableDecisionEvent: true}
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide(testFeature.Key, decide.IgnoreUserProfileService)
+
+	s.True(optimizelyDecision.Enabled)
+	s.mockEventProcessor.AssertNotCalled(s.T(), "ProcessEvent", mock.Anything)
+	s.mockDecisionService.AssertExpectations(s.T())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithExcludeVariables() {
+	testFeature := entities.Feature{Key: "feature_1", VariableMap: map[string]entities.Variable{
+		"var_str": {ID: "1", Key: "var_str", DefaultValue: "default", Type: entities.String},
+	}}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Return(decision.FeatureDecision{}, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	s.Equal(map[string]interface{}{"var_str": "default"}, userContext.Decide(testFeature.Key).Variables.ToMap())
+	s.Equal(map[string]interface{}{}, userContext.Decide(testFeature.Key, decide.ExcludeVariables).Variables.ToMap())
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithIncludeReasons() {
+	testFeature := entities.Feature{Key: "feature_1", VariableMap: map[string]entities.Variable{
+		"var_int": {ID: "1", Key: "var_int", DefaultValue: "invalid", Type: entities.Integer},
+	}}
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		decisionContext := args.Get(0).(decision.FeatureDecisionContext)
+		decisionContext.Reasons.AddInfo("info message")
+	}).Return(decision.FeatureDecision{}, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	invalidVariableReason := decide.GetDecideMessage(decide.VariableValueInvalid, "var_int")
+	s.Equal([]string{invalidVariableReason}, userContext.Decide(testFeature.Key).Reasons)
+	s.Equal([]string{invalidVariableReason, "info message"}, userContext.Decide(testFeature.Key, decide.IncludeReasons).Reasons)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithInvalidOption() {
+	s.mockConfig.On("GetFeatureByKey", "feature_1").Return(entities.Feature{Key: "feature_1"}, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	optimizelyDecision := userContext.Decide("feature_1", "INVALID")
+
+	s.False(optimizelyDecision.Enabled)
+	s.Equal([]string{"invalid option: INVALID"}, optimizelyDecision.Reasons)
+	s.mockDecisionService.AssertNotCalled(s.T(), "GetFeatureDecision", mock.Anything, mock.Anything)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideForKeysWithEnabledFlagsOnly() {
+	testVariation := makeTestVariation("on", true)
+	testFeature1 := entities.Feature{Key: "feature_1"}
+	testFeature2 := entities.Feature{Key: "feature_2"}
+	s.mockConfig.On("GetFeatureByKey", testFeature1.Key).Return(testFeature1, nil)
+	s.mockConfig.On("GetFeatureByKey", testFeature2.Key).Return(testFeature2, nil)
+	enabledFlagsOnly := decide.Options{EnabledFlagsOnly: true}
+	s.mockDecisionService.On("GetFeatureDecision", s.getTestDecisionContext(&testFeature1, enabledFlagsOnly), mock.Anything).
+		Return(decision.FeatureDecision{Variation: &testVariation, Source: decision.Rollout}, nil)
+	s.mockDecisionService.On("GetFeatureDecision", s.getTestDecisionContext(&testFeature2, enabledFlagsOnly), mock.Anything).
+		Return(decision.FeatureDecision{}, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	decisions := userContext.DecideForKeys([]string{"feature_1", "feature_2"}, decide.EnabledFlagsOnly)
+
+	s.Len(decisions, 1)
+	s.True(decisions["feature_1"].Enabled)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideForKeysWithInvalidOption() {
+	userContext := s.client.CreateUserContext("test_user", nil)
+	s.Len(userContext.DecideForKeys([]string{"feature_1"}, "INVALID"), 0)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestForcedDecisions() {
+	userContext := s.client.CreateUserContext("test_user", nil)
+	flagContext := decision.OptimizelyDecisionContext{FlagKey: "feature_1"}
+	ruleContext := decision.OptimizelyDecisionContext{FlagKey: "feature_1", RuleKey: "rule_1"}
+
+	_, err := userContext.GetForcedDecision(flagContext)
+	s.Error(err)
+	s.False(userContext.RemoveForcedDecision(flagContext))
+
+	s.True(userContext.SetForcedDecision(flagContext, decision.OptimizelyForcedDecision{VariationKey: "v1"}))
+	s.True(userContext.SetForcedDecision(ruleContext, decision.OptimizelyForcedDecision{VariationKey: "v2"}))
+	forcedDecision, err := userContext.GetForcedDecision(ruleContext)
+	s.NoError(err)
+	s.Equal("v2", forcedDecision.VariationKey)
+
+	s.True(userContext.RemoveForcedDecision(ruleContext))
+	_, err = userContext.GetForcedDecision(ruleContext)
+	s.Error(err)
+
+	s.True(userContext.RemoveAllForcedDecisions())
+	_, err = userContext.GetForcedDecision(flagContext)
+	s.Error(err)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithForcedDecision() {
+	testVariation := makeTestVariation("green", true)
+	testExperiment := makeTestExperimentWithVariations("number_1", []entities.Variation{testVariation})
+	testFeature := makeTestFeatureWithExperiment("feature_1", testExperiment)
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	userContext.SetForcedDecision(decision.OptimizelyDecisionContext{FlagKey: testFeature.Key}, decision.OptimizelyForcedDecision{VariationKey: "green"})
+
+	var forcedDecisionService *decision.ForcedDecisionService
+	s.mockDecisionService.On("GetFeatureDecision", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		forcedDecisionService = args.Get(0).(decision.FeatureDecisionContext).ForcedDecisionService
+	}).Return(decision.FeatureDecision{}, nil)
+	userContext.Decide(testFeature.Key)
+
+	// the decision is made with a copy of the forced decisions of the user context
+	s.NotNil(forcedDecisionService)
+	userContext.RemoveAllForcedDecisions()
+	forcedDecision, err := forcedDecisionService.GetForcedDecision(decision.OptimizelyDecisionContext{FlagKey: testFeature.Key})
+	s.NoError(err)
+	s.Equal("green", forcedDecision.VariationKey)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithFlagForcedDecisionDoesNotSendImpression() {
+	testVariation := makeTestVariation("green", true)
+	testExperiment := makeTestExperimentWithVariations("number_1", []entities.Variation{testVariation})
+	testFeature := makeTestFeatureWithExperiment("feature_1", testExperiment)
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+	s.client.DecisionService = decision.NewCompositeService("")
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	userContext.SetForcedDecision(decision.OptimizelyDecisionContext{FlagKey: testFeature.Key}, decision.OptimizelyForcedDecision{VariationKey: "green"})
+	optimizelyDecision := userContext.Decide(testFeature.Key)
+
+	// the user was never bucketed into the experiment having the forced variation
+	s.True(optimizelyDecision.Enabled)
+	s.Equal("green", optimizelyDecision.VariationKey)
+	s.Equal("", optimizelyDecision.RuleKey)
+	s.mockEventProcessor.AssertNotCalled(s.T(), "ProcessEvent", mock.Anything)
+}
+
+func (s *OptimizelyUserContextTestSuite) TestDecideWithContext() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	testVariation := makeTestVariation("green", true)
+	testExperiment := makeTestExperimentWithVariations("number_1", []entities.Variation{testVariation})
+	testFeature := makeTestFeatureWithExperiment("feature_1", testExperiment)
+	s.mockConfig.On("GetFeatureByKey", testFeature.Key).Return(testFeature, nil)
+
+	mockDecisionService := new(MockContextDecisionService)
+	mockDecisionService.On("GetFeatureDecisionWithContext", ctx, s.getTestDecisionContext(&testFeature, decide.Options{}), mock.Anything).
+		Return(decision.FeatureDecision{Experiment: testExperiment, Variation: &testVariation, Source: decision.Rollout}, nil)
+	s.client.DecisionService = mockDecisionService
+
+	userContext := s.client.CreateUserContext("test_user", nil)
+	s.True(userContext.DecideWithContext(ctx, testFeature.Key).Enabled)
+	s.Len(userContext.DecideForKeysWithContext(ctx, []string{testFeature.Key}), 1)
+	mockDecisionService.AssertExpectations(s.T())
+	mockDecisionService.AssertNotCalled(s.T(), "GetFeatureDecision", mock.Anything, mock.Anything)
+}
+
+func (s *OptimizelyUserContextTestSuite) getTestDecisionContext(feature *entities.Feature, options decide.Options) decision.FeatureDecisionContext {
+	return decision.FeatureDecisionContext{
+		Feature:       feature,
+		ProjectConfig: s.mockConfig,
+		Options:       options,
+		Reasons:       decide.NewDecisionReasons(&options),
+	}
+}
+
+func TestOptimizelyUserContextTestSuite(t *testing.T) {
+	suite.Run(t, new(OptimizelyUserContextTestSuite))
+}
@@ -0,0 +1,151 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client //
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// VariableTag is the struct tag used to map a struct field to a feature variable key
+const VariableTag = "optimizely"
+
+func validateDecodeTarget(target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errors.New("unable to decode feature variables: target must be a non-nil pointer to a struct")
+	}
+	return nil
+}
+
+// decodeVariables sets the tagged fields of the struct pointed to by target from the typed variable values
+func decodeVariables(variables map[string]entities.Variable, values map[string]interface{}, target interface{}) error {
+	if err := validateDecodeTarget(target); err != nil {
+		return err
+	}
+
+	variablesByKey := make(map[string]entities.Variable, len(variables))
+	for _, variable := range variables {
+		variablesByKey[variable.Key] = variable
+	}
+
+	errs := new(multierror.Error)
+	structValue := reflect.ValueOf(target).Elem()
+	structType := structValue.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		variableKey, ok := field.Tag.Lookup(VariableTag)
+		if !ok || variableKey == "" || variableKey == "-" {
+			continue
+		}
+
+		fieldValue := structValue.Field(i)
+		if !fieldValue.CanSet() {
+			errs = multierror.Append(errs, fmt.Errorf(`field "%s" is tagged with variable "%s" but is not exported`, field.Name, variableKey))
+			continue
+		}
+
+		variable, ok := variablesByKey[variableKey]
+		if !ok {
+			errs = multierror.Append(errs, fmt.Errorf(`field "%s": variable "%s" does not exist`, field.Name, variableKey))
+			continue
+		}
+
+		if err := setVariableField(fieldValue, variable, values[variableKey]); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf(`field "%s": %v`, field.Name, err))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func setVariableField(field reflect.Value, variable entities.Variable, value interface{}) error {
+	typeMismatch := fmt.Errorf(`variable "%s" of type "%s" cannot be stored in a field of type %s`, variable.Key, variable.Type, field.Type())
+
+	if field.Kind() == reflect.Interface && variable.Type != entities.JSON {
+		if value == nil || !reflect.TypeOf(value).AssignableTo(field.Type()) {
+			return typeMismatch
+		}
+		field.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	switch variable.Type {
+	case entities.Boolean:
+		boolValue, ok := value.(bool)
+		if !ok || field.Kind() != reflect.Bool {
+			return typeMismatch
+		}
+		field.SetBool(boolValue)
+	case entities.Integer:
+		intValue, ok := value.(int)
+		if !ok {
+			return typeMismatch
+		}
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if field.OverflowInt(int64(intValue)) {
+				return fmt.Errorf(`value %d of variable "%s" overflows a field of type %s`, intValue, variable.Key, field.Type())
+			}
+			field.SetInt(int64(intValue))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if intValue < 0 || field.OverflowUint(uint64(intValue)) {
+				return fmt.Errorf(`value %d of variable "%s" overflows a field of type %s`, intValue, variable.Key, field.Type())
+			}
+			field.SetUint(uint64(intValue))
+		default:
+			return typeMismatch
+		}
+	case entities.Double:
+		floatValue, ok := value.(float64)
+		if !ok {
+			return typeMismatch
+		}
+		switch field.Kind() {
+		case reflect.Float32, reflect.Float64:
+			field.SetFloat(floatValue)
+		default:
+			return typeMismatch
+		}
+	case entities.JSON:
+		// re-encoding the parsed value lets encoding/json handle nested structs, maps and slices
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		decoded := reflect.New(field.Type())
+		if err := json.Unmarshal(raw, decoded.Interface()); err != nil {
+			return fmt.Errorf(`variable "%s" cannot be decoded into a field of type %s: %v`, variable.Key, field.Type(), err)
+		}
+		field.Set(decoded.Elem())
+	default:
+		stringValue, ok := value.(string)
+		if !ok || field.Kind() != reflect.String {
+			return typeMismatch
+		}
+		field.SetString(stringValue)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var decoderTestVariables = map[string]entities.Variable{
+	"str":    {Key: "str", Type: entities.String},
+	"bool":   {Key: "bool", Type: entities.Boolean},
+	"int":    {Key: "int", Type: entities.Integer},
+	"double": {Key: "double", Type: entities.Double},
+	"json":   {Key: "json", Type: entities.JSON},
+}
+
+var decoderTestValues = map[string]interface{}{
+	"str":    "value",
+	"bool":   true,
+	"int":    300,
+	"double": 1.5,
+	"json":   map[string]interface{}{"nested": map[string]interface{}{"list": []interface{}{1.0, 2.0}}},
+}
+
+func TestDecodeVariables(t *testing.T) {
+	type nested struct {
+		List []int `json:"list"`
+	}
+	var target struct {
+		Str       string                 `optimizely:"str"`
+		Bool      bool                   `optimizely:"bool"`
+		Int       int                    `optimizely:"int"`
+		Uint      uint16                 `optimizely:"int"`
+		Double    float32                `optimizely:"double"`
+		JSON      map[string]nested      `optimizely:"json"`
+		RawJSON   map[string]interface{} `optimizely:"json"`
+		Interface interface{}            `optimizely:"str"`
+		Ignored   string                 `optimizely:"-"`
+	}
+
+	err := decodeVariables(decoderTestVariables, decoderTestValues, &target)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", target.Str)
+	assert.True(t, target.Bool)
+	assert.Equal(t, 300, target.Int)
+	assert.Equal(t, uint16(300), target.Uint)
+	assert.Equal(t, float32(1.5), target.Double)
+	assert.Equal(t, map[string]nested{"nested": {List: []int{1, 2}}}, target.JSON)
+	assert.Equal(t, decoderTestValues["json"], target.RawJSON)
+	assert.Equal(t, "value", target.Interface)
+}
+
+func TestDecodeVariablesReportsInvalidFields(t *testing.T) {
+	var target struct {
+		Bool     string   `optimizely:"bool"`
+		Int      int8     `optimizely:"int"`
+		Double   int      `optimizely:"double"`
+		JSON     []string `optimizely:"json"`
+		Typo     string   `optimizely:"strr"`
+		Str      string   `optimizely:"str"`
+		internal string   `optimizely:"str"`
+	}
+
+	err := decodeVariables(decoderTestVariables, decoderTestValues, &target)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `field "Bool": variable "bool" of type "boolean" cannot be stored in a field of type string`)
+		assert.Contains(t, err.Error(), `field "Int": value 300 of variable "int" overflows a field of type int8`)
+		assert.Contains(t, err.Error(), `field "Double": variable "double" of type "double" cannot be stored in a field of type int`)
+		assert.Contains(t, err.Error(), `field "JSON": variable "json" cannot be decoded into a field of type []string`)
+		assert.Contains(t, err.Error(), `field "Typo": variable "strr" does not exist`)
+		assert.Contains(t, err.Error(), `field "internal" is tagged with variable "str" but is not exported`)
+	}
+	assert.Equal(t, "value", target.Str)
+	assert.Equal(t, "", target.internal)
+}
+
+func TestDecodeVariablesInvalidTarget(t *testing.T) {
+	var target struct{}
+	var nilTarget *struct{}
+	str := ""
+
+	assert.Error(t, decodeVariables(decoderTestVariables, decoderTestValues, target))
+	assert.Error(t, decodeVariables(decoderTestVariables, decoderTestValues, nilTarget))
+	assert.Error(t, decodeVariables(decoderTestVariables, decoderTestValues, &str))
+	assert.Error(t, decodeVariables(decoderTestVariables, decoderTestValues, nil))
+}
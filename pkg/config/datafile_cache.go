@@ -0,0 +1,112 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ConfigSource describes where the current project config comes from
+type ConfigSource string
+
+const (
+	// ConfigSourceNone is used when there is no project config yet
+	ConfigSourceNone ConfigSource = ""
+	// ConfigSourceInitialDatafile is used when the project config comes from the datafile given at creation
+	ConfigSourceInitialDatafile ConfigSource = "initial_datafile"
+	// ConfigSourceCache is used when the project config comes from the datafile cached on disk by a previous run
+	ConfigSourceCache ConfigSource = "cache"
+	// ConfigSourceRemote is used when the project config comes from, or was confirmed by, a datafile fetch
+	ConfigSourceRemote ConfigSource = "remote"
+	// ConfigSourceFile is used when the project config comes from a datafile watched on disk
+	ConfigSourceFile ConfigSource = "file"
+)
+
+// cachedDatafilePath returns the path of the datafile cached for the given SDK key
+func cachedDatafilePath(dir, sdkKey string) string {
+	return filepath.Join(dir, url.PathEscape(sdkKey)+".json")
+}
+
+// cachedSignaturePath returns the path of the base64 encoded signature of the datafile cached for the given SDK key
+func cachedSignaturePath(dir, sdkKey string) string {
+	return cachedDatafilePath(dir, sdkKey) + DefaultSignatureSidecar
+}
+
+// readCachedSignature returns the base64 encoded signature of the datafile cached for the given SDK key
+func readCachedSignature(dir, sdkKey string) (string, error) {
+	signature, err := ioutil.ReadFile(cachedSignaturePath(dir, sdkKey))
+	return string(signature), err
+}
+
+// readCachedDatafile returns the datafile cached for the given SDK key and the time it was written
+func readCachedDatafile(dir, sdkKey string) ([]byte, time.Time, error) {
+	path := cachedDatafilePath(dir, sdkKey)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	datafile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return datafile, info.ModTime(), nil
+}
+
+// writeCachedDatafile caches the datafile of the given SDK key, the file is replaced atomically so that readers never
+// see a partially written datafile
+func writeCachedDatafile(dir, sdkKey string, datafile []byte) error {
+	return writeCacheFile(dir, cachedDatafilePath(dir, sdkKey), datafile)
+}
+
+// writeCachedSignature caches the base64 encoded signature of the datafile of the given SDK key
+func writeCachedSignature(dir, sdkKey, signature string) error {
+	return writeCacheFile(dir, cachedSignaturePath(dir, sdkKey), []byte(signature))
+}
+
+// writeCacheFile atomically replaces the file at the given path of the cache directory
+func writeCacheFile(dir, path string, data []byte) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(dir, ".datafile-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// no-op once the file has been renamed
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if _, err = tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
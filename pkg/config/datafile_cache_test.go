@@ -0,0 +1,185 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCacheDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "datafile-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestWriteAndReadCachedDatafile(t *testing.T) {
+	dir := newCacheDir(t)
+	defer os.RemoveAll(dir)
+
+	_, _, err := readCachedDatafile(dir, "sdk/key")
+	assert.True(t, os.IsNotExist(err))
+
+	cacheDir := filepath.Join(dir, "nested")
+	assert.NoError(t, writeCachedDatafile(cacheDir, "sdk/key", []byte(`{"revision":"1"}`)))
+	assert.NoError(t, writeCachedDatafile(cacheDir, "sdk/key", []byte(`{"revision":"2"}`)))
+
+	datafile, modTime, err := readCachedDatafile(cacheDir, "sdk/key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"revision":"2"}`), datafile)
+	assert.False(t, modTime.IsZero())
+
+	// only the cached datafile is left, the SDK key is escaped in the file name
+	files, err := ioutil.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "sdk%2Fkey.json", files[0].Name())
+	}
+}
+
+func TestPollingProjectConfigManagerCachesDatafile(t *testing.T) {
+	dir := newCacheDir(t)
+	defer os.RemoveAll(dir)
+
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, http.Header{}, http.StatusOK, nil)
+
+	configManager := NewPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithDatafileCacheDir(dir))
+	assert.Equal(t, ConfigSourceRemote, configManager.ConfigSource())
+
+	datafile, _, err := readCachedDatafile(dir, "test_sdk_key")
+	assert.NoError(t, err)
+	assert.Equal(t, mockDatafile, datafile)
+}
+
+func TestPollingProjectConfigManagerDoesNotCacheInvalidDatafile(t *testing.T) {
+	dir := newCacheDir(t)
+	defer os.RemoveAll(dir)
+
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`INVALID`), http.Header{}, http.StatusOK, nil)
+
+	configManager := NewPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithDatafileCacheDir(dir))
+	assert.Equal(t, ConfigSourceNone, configManager.ConfigSource())
+
+	_, _, err := readCachedDatafile(dir, "test_sdk_key")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPollingProjectConfigManagerBootsFromCache(t *testing.T) {
+	dir := newCacheDir(t)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, writeCachedDatafile(dir, "test_sdk_key", []byte(`{"revision":"41","version": "4"}`)))
+
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unavailable"))
+
+	configManager := NewPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithDatafileCacheDir(dir))
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "41", projectConfig.GetRevision())
+	assert.Equal(t, ConfigSourceCache, configManager.ConfigSource())
+	select {
+	case <-configManager.Ready():
+	default:
+		assert.Fail(t, "the manager should be ready")
+	}
+
+	// a successful fetch replaces the cached config
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester.ExpectedCalls = nil
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, http.Header{}, http.StatusOK, nil)
+	configManager.SyncConfig()
+	projectConfig, err = configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+	assert.Equal(t, ConfigSourceRemote, configManager.ConfigSource())
+}
+
+func TestInitialDatafileTakesPrecedenceOverCache(t *testing.T) {
+	dir := newCacheDir(t)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, writeCachedDatafile(dir, "test_sdk_key", []byte(`{"revision":"41","version": "4"}`)))
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCacheDir(dir),
+		WithInitialDatafile([]byte(`{"revision":"40","version": "4"}`)))
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "40", projectConfig.GetRevision())
+	assert.Equal(t, ConfigSourceInitialDatafile, configManager.ConfigSource())
+}
+
+func TestInvalidCachedDatafileIsIgnored(t *testing.T) {
+	dir := newCacheDir(t)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, writeCachedDatafile(dir, "test_sdk_key", []byte(`INVALID`)))
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCacheDir(dir))
+	_, err := configManager.GetConfig()
+	assert.Error(t, err)
+	assert.Equal(t, ConfigSourceNone, configManager.ConfigSource())
+}
+
+func TestCachedDatafileSignatureIsVerified(t *testing.T) {
+	dir := newCacheDir(t)
+	defer os.RemoveAll(dir)
+	key := []byte("secret")
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	headers := http.Header{}
+	headers.Set(DatafileSignatureHeader, hmacSignature(key, mockDatafile))
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, headers, http.StatusOK, nil)
+
+	// the signature is cached along with the datafile
+	NewPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithDatafileCacheDir(dir),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)))
+	signature, err := readCachedSignature(dir, "test_sdk_key")
+	assert.NoError(t, err)
+	assert.Equal(t, hmacSignature(key, mockDatafile), signature)
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCacheDir(dir),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)))
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+	assert.Equal(t, ConfigSourceCache, configManager.ConfigSource())
+
+	// a cached datafile which does not match its signature is ignored
+	assert.NoError(t, writeCachedDatafile(dir, "test_sdk_key", []byte(`{"revision":"43","version": "4"}`)))
+	configManager = NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCacheDir(dir),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)))
+	_, err = configManager.GetConfig()
+	assert.Equal(t, ErrInvalidSignature, err)
+	assert.Equal(t, ConfigSourceNone, configManager.ConfigSource())
+
+	assert.NoError(t, os.Remove(cachedSignaturePath(dir, "test_sdk_key")))
+	configManager = NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCacheDir(dir),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)))
+	_, err = configManager.GetConfig()
+	assert.Equal(t, ErrMissingSignature, err)
+}
@@ -0,0 +1,283 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/optimizely/go-sdk/pkg/logging"
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DatafileRequest describes the datafile to fetch. LastModified and ETag are the validators returned along with the
+// datafile currently in use, sources may use them to avoid serving an unchanged datafile again.
+type DatafileRequest struct {
+	SDKKey       string
+	LastModified string
+	ETag         string
+}
+
+// DatafileResponse holds a datafile served by a DatafileSource
+type DatafileResponse struct {
+	Datafile     []byte
+	NotModified  bool
+	LastModified string
+	ETag         string
+	// Header holds the response header of the sources served over HTTP
+	Header http.Header
+	// Source is the name of the source which served the datafile
+	Source string
+}
+
+// DatafileSource is used by the config managers to fetch datafiles
+type DatafileSource interface {
+	Name() string
+	Fetch(request DatafileRequest) (DatafileResponse, error)
+}
+
+// HTTPDatafileSource fetches datafiles from a URL, by default the Optimizely CDN
+type HTTPDatafileSource struct {
+	requester   utils.Requester
+	urlTemplate string
+}
+
+// NewHTTPDatafileSource returns a source fetching datafiles with the given requester. The URL template is formatted
+// with the SDK key, DatafileURLTemplate is used when it is empty.
+func NewHTTPDatafileSource(requester utils.Requester, urlTemplate string) *HTTPDatafileSource {
+	if urlTemplate == "" {
+		urlTemplate = DatafileURLTemplate
+	}
+	return &HTTPDatafileSource{
+		requester:   requester,
+		urlTemplate: urlTemplate,
+	}
+}
+
+// Name returns the name of the source
+func (s *HTTPDatafileSource) Name() string {
+	return "http"
+}
+
+// Fetch fetches the datafile, a 304 response is returned as not modified
+func (s *HTTPDatafileSource) Fetch(request DatafileRequest) (DatafileResponse, error) {
+	url := fmt.Sprintf(s.urlTemplate, request.SDKKey)
+	var headers []utils.Header
+	if request.LastModified != "" {
+		headers = append(headers, utils.Header{Name: ModifiedSince, Value: request.LastModified})
+	}
+	if request.ETag != "" {
+		headers = append(headers, utils.Header{Name: IfNoneMatch, Value: request.ETag})
+	}
+
+	datafile, respHeaders, code, err := s.requester.Get(url, headers...)
+	if err != nil {
+		if code == http.StatusForbidden {
+			return DatafileResponse{}, Err403Forbidden
+		}
+		return DatafileResponse{}, err
+	}
+
+	response := DatafileResponse{Source: s.Name(), NotModified: code == http.StatusNotModified, Header: respHeaders}
+	if !response.NotModified {
+		response.Datafile = datafile
+	}
+	if respHeaders != nil {
+		response.LastModified = respHeaders.Get(LastModified)
+		response.ETag = respHeaders.Get(ETag)
+	}
+	return response, nil
+}
+
+// FetchSignature fetches the signature of the datafile from the datafile URL followed by the given suffix
+func (s *HTTPDatafileSource) FetchSignature(request DatafileRequest, suffix string) ([]byte, error) {
+	signature, _, _, err := s.requester.Get(fmt.Sprintf(s.urlTemplate, request.SDKKey) + suffix)
+	return signature, err
+}
+
+// FileDatafileSource reads the datafile from a local file
+type FileDatafileSource struct {
+	path string
+}
+
+// NewFileDatafileSource returns a source reading the datafile at the given path
+func NewFileDatafileSource(path string) *FileDatafileSource {
+	return &FileDatafileSource{path: path}
+}
+
+// Name returns the name of the source
+func (s *FileDatafileSource) Name() string {
+	return "file:" + s.path
+}
+
+// Fetch reads the datafile, the modification time of the file is used as validator so an unchanged file is returned
+// as not modified
+func (s *FileDatafileSource) Fetch(request DatafileRequest) (DatafileResponse, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return DatafileResponse{}, err
+	}
+
+	response := DatafileResponse{Source: s.Name(), LastModified: info.ModTime().UTC().Format(http.TimeFormat)}
+	if request.LastModified == response.LastModified {
+		response.NotModified = true
+		return response, nil
+	}
+
+	if response.Datafile, err = ioutil.ReadFile(s.path); err != nil {
+		return DatafileResponse{}, err
+	}
+	return response, nil
+}
+
+// FetchSignature reads the signature of the datafile from the datafile path followed by the given suffix
+func (s *FileDatafileSource) FetchSignature(request DatafileRequest, suffix string) ([]byte, error) {
+	return ioutil.ReadFile(s.path + suffix)
+}
+
+// MemoryDatafileSource serves a datafile held in memory, such as an embedded one
+type MemoryDatafileSource struct {
+	datafile []byte
+}
+
+// NewMemoryDatafileSource returns a source serving the given datafile
+func NewMemoryDatafileSource(datafile []byte) *MemoryDatafileSource {
+	return &MemoryDatafileSource{datafile: datafile}
+}
+
+// Name returns the name of the source
+func (s *MemoryDatafileSource) Name() string {
+	return "memory"
+}
+
+// Fetch returns the datafile
+func (s *MemoryDatafileSource) Fetch(request DatafileRequest) (DatafileResponse, error) {
+	if len(s.datafile) == 0 {
+		return DatafileResponse{}, errors.New("no datafile in memory")
+	}
+	return DatafileResponse{Source: s.Name(), Datafile: s.datafile}, nil
+}
+
+// DatafileSourceError is the error returned by one of the sources of a DatafileSourceChain
+type DatafileSourceError struct {
+	Source string
+	Err    error
+}
+
+func (e *DatafileSourceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Source, e.Err)
+}
+
+// isForbidden returns whether the given error is Err403Forbidden, or a DatafileSourceChain error in which one of the
+// sources returned Err403Forbidden
+func isForbidden(err error) bool {
+	switch e := err.(type) {
+	case *multierror.Error:
+		for _, sourceErr := range e.Errors {
+			if isForbidden(sourceErr) {
+				return true
+			}
+		}
+		return false
+	case *DatafileSourceError:
+		return isForbidden(e.Err)
+	}
+	return err == Err403Forbidden
+}
+
+// DatafileSourceChain tries its sources in order and serves the datafile of the first one which succeeds
+type DatafileSourceChain struct {
+	sources    []DatafileSource
+	lastSource DatafileSource
+	lock       sync.Mutex
+	logger     logging.OptimizelyLogProducer
+}
+
+// NewDatafileSourceChain returns a chain of the given sources, in order of preference
+func NewDatafileSourceChain(sources ...DatafileSource) *DatafileSourceChain {
+	return &DatafileSourceChain{
+		sources: sources,
+		logger:  logging.GetLogger("", "DatafileSourceChain"),
+	}
+}
+
+// Name returns the name of the chain
+func (c *DatafileSourceChain) Name() string {
+	return "chain"
+}
+
+// Fetch returns the response of the first source which succeeds, an error combining the DatafileSourceError of every
+// source is returned when they all fail. The validators of the request are only passed on to the source which served the last
+// datafile, as they have no meaning for the other sources.
+func (c *DatafileSourceChain) Fetch(request DatafileRequest) (DatafileResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var errs *multierror.Error
+	for _, source := range c.sources {
+		sourceRequest := request
+		if source != c.lastSource {
+			sourceRequest.LastModified = ""
+			sourceRequest.ETag = ""
+		}
+
+		response, err := source.Fetch(sourceRequest)
+		if err != nil {
+			c.logger.Debug(fmt.Sprintf("Datafile source %s failed: %v", source.Name(), err))
+			errs = multierror.Append(errs, &DatafileSourceError{Source: source.Name(), Err: err})
+			continue
+		}
+		if response.Source == "" {
+			response.Source = source.Name()
+		}
+		c.lastSource = source
+		return response, nil
+	}
+
+	if errs == nil {
+		return DatafileResponse{}, errors.New("no datafile source")
+	}
+	return DatafileResponse{}, errs
+}
+
+// FetchSignature fetches the signature of the datafile from the source which served the last datafile
+func (c *DatafileSourceChain) FetchSignature(request DatafileRequest, suffix string) ([]byte, error) {
+	c.lock.Lock()
+	lastSource := c.lastSource
+	c.lock.Unlock()
+	if signatureSource, ok := lastSource.(SignatureSource); ok {
+		return signatureSource.FetchSignature(request, suffix)
+	}
+	return nil, ErrMissingSignature
+}
+
+// LastSource returns the name of the source which served the last datafile, empty if none did
+func (c *DatafileSourceChain) LastSource() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.lastSource == nil {
+		return ""
+	}
+	return c.lastSource.Name()
+}
@@ -0,0 +1,192 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+type failingDatafileSource struct {
+	name string
+}
+
+func (s failingDatafileSource) Name() string {
+	return s.name
+}
+
+func (s failingDatafileSource) Fetch(request DatafileRequest) (DatafileResponse, error) {
+	return DatafileResponse{}, errUnavailable
+}
+
+type recordingDatafileSource struct {
+	DatafileSource
+	requests []DatafileRequest
+}
+
+func (s *recordingDatafileSource) Fetch(request DatafileRequest) (DatafileResponse, error) {
+	s.requests = append(s.requests, request)
+	return s.DatafileSource.Fetch(request)
+}
+
+func TestHTTPDatafileSource(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester := new(MockRequester)
+	responseHeaders := http.Header{}
+	responseHeaders.Set(LastModified, "yesterday")
+	responseHeaders.Set(ETag, `"42"`)
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, responseHeaders, http.StatusOK, nil)
+	mockRequester.On("Get", []utils.Header{{Name: ModifiedSince, Value: "yesterday"}, {Name: IfNoneMatch, Value: `"42"`}}).Return([]byte{}, http.Header{}, http.StatusNotModified, nil)
+
+	source := NewHTTPDatafileSource(mockRequester, "")
+	response, err := source.Fetch(DatafileRequest{SDKKey: "test_sdk_key"})
+	assert.NoError(t, err)
+	assert.Equal(t, DatafileResponse{Datafile: mockDatafile, LastModified: "yesterday", ETag: `"42"`, Header: responseHeaders, Source: "http"}, response)
+
+	response, err = source.Fetch(DatafileRequest{SDKKey: "test_sdk_key", LastModified: "yesterday", ETag: `"42"`})
+	assert.NoError(t, err)
+	assert.True(t, response.NotModified)
+	assert.Nil(t, response.Datafile)
+	mockRequester.AssertExpectations(t)
+}
+
+func TestHTTPDatafileSourceForbidden(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusForbidden, errors.New("403 Forbidden"))
+
+	_, err := NewHTTPDatafileSource(mockRequester, "").Fetch(DatafileRequest{SDKKey: "test_sdk_key"})
+	assert.Equal(t, Err403Forbidden, err)
+}
+
+func TestFileDatafileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datafile-source")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datafile.json")
+
+	source := NewFileDatafileSource(path)
+	assert.Equal(t, "file:"+path, source.Name())
+	_, err = source.Fetch(DatafileRequest{})
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"revision":"42"}`), 0600))
+	response, err := source.Fetch(DatafileRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"revision":"42"}`), response.Datafile)
+	assert.NotEmpty(t, response.LastModified)
+
+	// the file is not read again while it is unchanged
+	response, err = source.Fetch(DatafileRequest{LastModified: response.LastModified})
+	assert.NoError(t, err)
+	assert.True(t, response.NotModified)
+
+	modTime := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	response, err = source.Fetch(DatafileRequest{LastModified: response.LastModified})
+	assert.NoError(t, err)
+	assert.False(t, response.NotModified)
+	assert.Equal(t, []byte(`{"revision":"42"}`), response.Datafile)
+}
+
+func TestMemoryDatafileSource(t *testing.T) {
+	response, err := NewMemoryDatafileSource([]byte(`{"revision":"42"}`)).Fetch(DatafileRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, DatafileResponse{Datafile: []byte(`{"revision":"42"}`), Source: "memory"}, response)
+
+	_, err = NewMemoryDatafileSource(nil).Fetch(DatafileRequest{})
+	assert.Error(t, err)
+}
+
+func TestDatafileSourceChain(t *testing.T) {
+	primary := &recordingDatafileSource{DatafileSource: failingDatafileSource{name: "primary"}}
+	fallback := &recordingDatafileSource{DatafileSource: NewMemoryDatafileSource([]byte(`{"revision":"42"}`))}
+	chain := NewDatafileSourceChain(primary, fallback)
+	assert.Equal(t, "", chain.LastSource())
+
+	response, err := chain.Fetch(DatafileRequest{SDKKey: "test_sdk_key", ETag: "stale"})
+	assert.NoError(t, err)
+	assert.Equal(t, "memory", response.Source)
+	assert.Equal(t, "memory", chain.LastSource())
+
+	// validators are only passed to the source which served the last datafile
+	_, err = chain.Fetch(DatafileRequest{SDKKey: "test_sdk_key", ETag: "current"})
+	assert.NoError(t, err)
+	assert.Equal(t, []DatafileRequest{{SDKKey: "test_sdk_key"}, {SDKKey: "test_sdk_key"}}, primary.requests)
+	assert.Equal(t, []DatafileRequest{{SDKKey: "test_sdk_key"}, {SDKKey: "test_sdk_key", ETag: "current"}}, fallback.requests)
+}
+
+func TestDatafileSourceChainFailure(t *testing.T) {
+	chain := NewDatafileSourceChain(failingDatafileSource{name: "primary"}, failingDatafileSource{name: "secondary"})
+	_, err := chain.Fetch(DatafileRequest{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "primary: unavailable")
+		assert.Contains(t, err.Error(), "secondary: unavailable")
+	}
+	if merr, ok := err.(*multierror.Error); assert.True(t, ok) && assert.Len(t, merr.Errors, 2) {
+		assert.Equal(t, &DatafileSourceError{Source: "primary", Err: errUnavailable}, merr.Errors[0])
+	}
+
+	_, err = NewDatafileSourceChain().Fetch(DatafileRequest{})
+	assert.Error(t, err)
+}
+
+func TestPollingProjectConfigManagerWithForbiddenDatafileSource(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusForbidden, errors.New("403 Forbidden"))
+	chain := NewDatafileSourceChain(NewHTTPDatafileSource(mockRequester, ""), failingDatafileSource{name: "secondary"})
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileSource(chain))
+	assert.Equal(t, Err403Forbidden, configManager.syncConfig())
+
+	configManager = NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileSource(failingDatafileSource{name: "primary"}))
+	assert.EqualError(t, configManager.syncConfig(), "unable to fetch fresh datafile: unavailable")
+}
+
+func TestPollingProjectConfigManagerWithDatafileSource(t *testing.T) {
+	chain := NewDatafileSourceChain(failingDatafileSource{name: "primary"}, NewMemoryDatafileSource([]byte(`{"revision":"42","version": "4"}`)))
+	configManager := NewPollingProjectConfigManager("test_sdk_key", WithDatafileSource(chain))
+
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+	assert.Equal(t, "memory", chain.LastSource())
+
+	configManager = NewPollingProjectConfigManager("test_sdk_key", WithDatafileSource(failingDatafileSource{name: "primary"}))
+	_, err = configManager.GetConfig()
+	assert.Error(t, err)
+}
+
+func TestStaticProjectConfigManagerWithDatafileSource(t *testing.T) {
+	configManager := NewStaticProjectConfigManagerWithOptions("", WithDatafileSource(NewMemoryDatafileSource([]byte(`{"revision":"42","version": "4"}`))))
+	if assert.NotNil(t, configManager) {
+		projectConfig, err := configManager.GetConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, "42", projectConfig.GetRevision())
+	}
+}
@@ -45,8 +45,13 @@ type DatafileProjectConfig struct {
 	featureMap           map[string]entities.Feature
 	groupMap             map[string]entities.Group
 	rolloutMap           map[string]entities.Rollout
+	integrations         []entities.Integration
+	holdouts             []entities.Holdout
+	sdkKey               string
+	environmentKey       string
 	anonymizeIP          bool
 	botFiltering         bool
+	sendFlagDecisions    bool
 }
 
 // GetDatafile returns a string representation of the environment's datafile
@@ -84,6 +89,31 @@ func (c DatafileProjectConfig) GetBotFiltering() bool {
 	return c.botFiltering
 }
 
+// GetSDKKey returns the SDK key of the environment of the datafile
+func (c DatafileProjectConfig) GetSDKKey() string {
+	return c.sdkKey
+}
+
+// GetEnvironmentKey returns the key of the environment of the datafile
+func (c DatafileProjectConfig) GetEnvironmentKey() string {
+	return c.environmentKey
+}
+
+// GetSendFlagDecisions returns whether the decisions of rollouts produce impression events
+func (c DatafileProjectConfig) GetSendFlagDecisions() bool {
+	return c.sendFlagDecisions
+}
+
+// GetIntegrationList returns the integrations of the project
+func (c DatafileProjectConfig) GetIntegrationList() []entities.Integration {
+	return c.integrations
+}
+
+// GetHoldoutList returns the global holdouts of the project
+func (c DatafileProjectConfig) GetHoldoutList() []entities.Holdout {
+	return c.holdouts
+}
+
 // GetEventByKey returns the event with the given key
 func (c DatafileProjectConfig) GetEventByKey(eventKey string) (entities.Event, error) {
 	if event, ok := c.eventMap[eventKey]; ok {
@@ -128,6 +158,22 @@ func (c DatafileProjectConfig) GetAttributeByKey(key string) (entities.Attribute
 	return entities.Attribute{}, fmt.Errorf(`attribute with key "%s" not found`, key)
 }
 
+// GetAttributeList returns an array of all the attributes
+func (c DatafileProjectConfig) GetAttributeList() (attributeList []entities.Attribute) {
+	for _, attribute := range c.attributeMap {
+		attributeList = append(attributeList, attribute)
+	}
+	return attributeList
+}
+
+// GetEventList returns an array of all the events
+func (c DatafileProjectConfig) GetEventList() (eventList []entities.Event) {
+	for _, event := range c.eventMap {
+		eventList = append(eventList, event)
+	}
+	return eventList
+}
+
 // GetFeatureList returns an array of all the features
 func (c DatafileProjectConfig) GetFeatureList() (featureList []entities.Feature) {
 	for _, feature := range c.featureMap {
@@ -212,11 +258,16 @@ func NewDatafileProjectConfig(jsonDatafile []byte, logger logging.OptimizelyLogP
 		experimentKeyToIDMap: experimentKeyMap,
 		experimentMap:        experimentMap,
 		groupMap:             groupMap,
+		holdouts:             mappers.MapHoldouts(datafile.Holdouts),
+		environmentKey:       datafile.EnvironmentKey,
 		eventMap:             eventMap,
 		featureMap:           featureMap,
+		integrations:         mappers.MapIntegrations(datafile.Integrations),
 		projectID:            datafile.ProjectID,
 		revision:             datafile.Revision,
 		rolloutMap:           rolloutMap,
+		sdkKey:               datafile.SDKKey,
+		sendFlagDecisions:    datafile.SendFlagDecisions,
 	}
 
 	logger.Info("Datafile is valid.")
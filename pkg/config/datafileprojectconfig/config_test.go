@@ -107,6 +107,65 @@ func TestGetBotFiltering(t *testing.T) {
 	assert.Equal(t, botFiltering, config.GetBotFiltering())
 }
 
+func TestGetSDKKey(t *testing.T) {
+	sdkKey := "sdkKey"
+	config := &DatafileProjectConfig{
+		sdkKey: sdkKey,
+	}
+
+	assert.Equal(t, sdkKey, config.GetSDKKey())
+}
+
+func TestGetEnvironmentKey(t *testing.T) {
+	environmentKey := "production"
+	config := &DatafileProjectConfig{
+		environmentKey: environmentKey,
+	}
+
+	assert.Equal(t, environmentKey, config.GetEnvironmentKey())
+}
+
+func TestGetSendFlagDecisions(t *testing.T) {
+	config := &DatafileProjectConfig{
+		sendFlagDecisions: true,
+	}
+
+	assert.True(t, config.GetSendFlagDecisions())
+}
+
+func TestGetIntegrationList(t *testing.T) {
+	integrations := []entities.Integration{{Key: "odp", Host: "https://api.example.com", PublicKey: "public_key"}}
+	config := &DatafileProjectConfig{
+		integrations: integrations,
+	}
+
+	assert.Equal(t, integrations, config.GetIntegrationList())
+}
+
+func TestGetHoldoutList(t *testing.T) {
+	holdouts := []entities.Holdout{{ID: "holdout_1", Key: "global_holdout"}}
+	config := &DatafileProjectConfig{
+		holdouts: holdouts,
+	}
+
+	assert.Equal(t, holdouts, config.GetHoldoutList())
+}
+
+func TestNewDatafileProjectConfigWithNewerFields(t *testing.T) {
+	jsonDatafileStr := `{"revision": "1", "version": "4", "sdkKey": "sdk_key", "environmentKey": "production",
+		"sendFlagDecisions": true, "integrations": [{"key": "odp", "host": "https://api.example.com", "publicKey": "public_key"}],
+		"holdouts": [{"id": "holdout_1", "key": "global_holdout", "status": "Running", "trafficAllocation": [{"entityId": "off_1", "endOfRange": 500}]}]}`
+	projectConfig, err := NewDatafileProjectConfig([]byte(jsonDatafileStr), logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	assert.Equal(t, "sdk_key", projectConfig.GetSDKKey())
+	assert.Equal(t, "production", projectConfig.GetEnvironmentKey())
+	assert.True(t, projectConfig.GetSendFlagDecisions())
+	assert.Equal(t, []entities.Integration{{Key: "odp", Host: "https://api.example.com", PublicKey: "public_key"}}, projectConfig.GetIntegrationList())
+	if assert.Len(t, projectConfig.GetHoldoutList(), 1) {
+		assert.Equal(t, "global_holdout", projectConfig.GetHoldoutList()[0].Key)
+	}
+}
+
 func TestGetEventByKey(t *testing.T) {
 	key := "key"
 	event := entities.Event{
@@ -272,6 +331,36 @@ func TestGetFeatureList(t *testing.T) {
 	assert.Equal(t, feature, features[0])
 }
 
+func TestGetAttributeList(t *testing.T) {
+	attribute := entities.Attribute{
+		ID:  "id",
+		Key: "key",
+	}
+	config := &DatafileProjectConfig{
+		attributeMap: map[string]entities.Attribute{"id": attribute},
+	}
+
+	attributes := config.GetAttributeList()
+
+	assert.Equal(t, 1, len(attributes))
+	assert.Equal(t, attribute, attributes[0])
+}
+
+func TestGetEventList(t *testing.T) {
+	event := entities.Event{
+		ID:  "id",
+		Key: "key",
+	}
+	config := &DatafileProjectConfig{
+		eventMap: map[string]entities.Event{"key": event},
+	}
+
+	events := config.GetEventList()
+
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, event, events[0])
+}
+
 func TestGetExperimentList(t *testing.T) {
 	id := "id"
 	key := "key"
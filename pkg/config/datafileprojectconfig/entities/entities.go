@@ -104,21 +104,44 @@ type Rollout struct {
 	Experiments []Experiment `json:"experiments"`
 }
 
+// Integration represents an integration with a third party service from the Optimizely datafile
+type Integration struct {
+	Key       string `json:"key"`
+	Host      string `json:"host"`
+	PublicKey string `json:"publicKey"`
+}
+
+// Holdout represents a global holdout from the Optimizely datafile
+type Holdout struct {
+	ID                 string              `json:"id"`
+	Key                string              `json:"key"`
+	Status             string              `json:"status"`
+	Variations         []Variation         `json:"variations"`
+	TrafficAllocation  []TrafficAllocation `json:"trafficAllocation"`
+	AudienceIds        []string            `json:"audienceIds"`
+	AudienceConditions interface{}         `json:"audienceConditions"`
+}
+
 // Datafile represents the datafile we get from Optimizely
 type Datafile struct {
-	Attributes     []Attribute   `json:"attributes"`
-	Audiences      []Audience    `json:"audiences"`
-	Experiments    []Experiment  `json:"experiments"`
-	Groups         []Group       `json:"groups"`
-	FeatureFlags   []FeatureFlag `json:"featureFlags"`
-	Events         []Event       `json:"events"`
-	Rollouts       []Rollout     `json:"rollouts"`
-	TypedAudiences []Audience    `json:"typedAudiences"`
-	Variables      []string      `json:"variables"`
-	AccountID      string        `json:"accountId"`
-	ProjectID      string        `json:"projectId"`
-	Revision       string        `json:"revision"`
-	Version        string        `json:"version"`
-	AnonymizeIP    bool          `json:"anonymizeIP"`
-	BotFiltering   bool          `json:"botFiltering"`
+	Attributes        []Attribute   `json:"attributes"`
+	Audiences         []Audience    `json:"audiences"`
+	Experiments       []Experiment  `json:"experiments"`
+	Groups            []Group       `json:"groups"`
+	FeatureFlags      []FeatureFlag `json:"featureFlags"`
+	Events            []Event       `json:"events"`
+	Rollouts          []Rollout     `json:"rollouts"`
+	TypedAudiences    []Audience    `json:"typedAudiences"`
+	Variables         []string      `json:"variables"`
+	AccountID         string        `json:"accountId"`
+	ProjectID         string        `json:"projectId"`
+	Revision          string        `json:"revision"`
+	Version           string        `json:"version"`
+	AnonymizeIP       bool          `json:"anonymizeIP"`
+	BotFiltering      bool          `json:"botFiltering"`
+	SDKKey            string        `json:"sdkKey"`
+	EnvironmentKey    string        `json:"environmentKey"`
+	SendFlagDecisions bool          `json:"sendFlagDecisions"`
+	Integrations      []Integration `json:"integrations"`
+	Holdouts          []Holdout     `json:"holdouts"`
 }
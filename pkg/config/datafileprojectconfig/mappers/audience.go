@@ -38,8 +38,26 @@ func MapAudiences(audiences []datafileEntities.Audience) map[string]entities.Aud
 				ID:            audience.ID,
 				Name:          audience.Name,
 				ConditionTree: conditionTree,
+				Conditions:    conditionsString(audience.Conditions),
 			}
 		}
 	}
 	return audienceMap
 }
+
+// conditionsString returns the conditions of an audience as a JSON string, the conditions of typed audiences are
+// JSON arrays instead of strings
+func conditionsString(conditions interface{}) string {
+	switch value := conditions.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		conditionsJSON, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(conditionsJSON)
+	}
+}
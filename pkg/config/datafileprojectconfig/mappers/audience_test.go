@@ -47,3 +47,14 @@ func TestMapAudiences(t *testing.T) {
 
 	assert.Equal(t, audienceMap, expectedAudienceMap)
 }
+
+func TestMapAudiencesConditions(t *testing.T) {
+
+	audienceList := []datafileEntities.Audience{{ID: "1", Name: "one", Conditions: `["or"]`},
+		{ID: "2", Name: "two", Conditions: []interface{}{"and"}}}
+
+	audienceMap := MapAudiences(audienceList)
+
+	assert.Equal(t, `["or"]`, audienceMap["1"].Conditions)
+	assert.Equal(t, `["and"]`, audienceMap["2"].Conditions)
+}
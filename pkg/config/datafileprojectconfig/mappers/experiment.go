@@ -90,6 +90,7 @@ func mapExperiment(rawExperiment datafileEntities.Experiment) entities.Experimen
 		AudienceConditionTree: audienceConditionTree,
 		Whitelist:             rawExperiment.ForcedVariations,
 		IsFeatureExperiment:   false,
+		Status:                entities.ExperimentStatus(rawExperiment.Status),
 	}
 
 	for _, variation := range rawExperiment.Variations {
@@ -30,6 +30,7 @@ func TestMapExperiments(t *testing.T) {
 		"audienceIds": ["31111"],
 		"id": "11111",
 		"key": "test_experiment_11111",
+		"status": "Paused",
 		"variations": [
 			{
 				"id": "21111",
@@ -74,6 +75,7 @@ func TestMapExperiments(t *testing.T) {
 			ID:          "11111",
 			GroupID:     "15",
 			Key:         "test_experiment_11111",
+			Status:      entities.ExperimentStatusPaused,
 			Variations: map[string]entities.Variation{
 				"21111": {
 					ID:             "21111",
@@ -0,0 +1,51 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package mappers ...
+package mappers
+
+import (
+	datafileEntities "github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig/entities"
+	"github.com/optimizely/go-sdk/pkg/entities"
+)
+
+// MapHoldouts maps the raw datafile holdout entities to SDK Holdout entities
+func MapHoldouts(rawHoldouts []datafileEntities.Holdout) []entities.Holdout {
+	holdoutList := make([]entities.Holdout, len(rawHoldouts))
+	for i, rawHoldout := range rawHoldouts {
+		// holdouts are bucketed and targeted the same way as experiments
+		experiment := mapExperiment(datafileEntities.Experiment{
+			ID:                 rawHoldout.ID,
+			Key:                rawHoldout.Key,
+			Status:             rawHoldout.Status,
+			Variations:         rawHoldout.Variations,
+			TrafficAllocation:  rawHoldout.TrafficAllocation,
+			AudienceIds:        rawHoldout.AudienceIds,
+			AudienceConditions: rawHoldout.AudienceConditions,
+		})
+		holdoutList[i] = entities.Holdout{
+			ID:                    experiment.ID,
+			Key:                   experiment.Key,
+			Status:                experiment.Status,
+			AudienceIds:           experiment.AudienceIds,
+			AudienceConditionTree: experiment.AudienceConditionTree,
+			Variations:            experiment.Variations,
+			TrafficAllocation:     experiment.TrafficAllocation,
+		}
+	}
+
+	return holdoutList
+}
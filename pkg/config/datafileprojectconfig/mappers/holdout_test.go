@@ -0,0 +1,57 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package mappers
+
+import (
+	"testing"
+
+	datafileEntities "github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig/entities"
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapHoldouts(t *testing.T) {
+	const testHoldoutsString = `[
+		{
+			"id": "holdout_1",
+			"key": "global_holdout",
+			"status": "Running",
+			"variations": [{"id": "off_1", "key": "off", "featureEnabled": false}],
+			"trafficAllocation": [{"entityId": "off_1", "endOfRange": 500}],
+			"audienceIds": []
+		}
+	]`
+
+	var rawHoldouts []datafileEntities.Holdout
+	json.Unmarshal([]byte(testHoldoutsString), &rawHoldouts)
+
+	holdouts := MapHoldouts(rawHoldouts)
+	expectedHoldouts := []entities.Holdout{
+		{
+			ID:          "holdout_1",
+			Key:         "global_holdout",
+			Status:      entities.ExperimentStatusRunning,
+			AudienceIds: []string{},
+			Variations: map[string]entities.Variation{
+				"off_1": {ID: "off_1", Key: "off", Variables: map[string]entities.VariationVariable{}},
+			},
+			TrafficAllocation: []entities.Range{{EntityID: "off_1", EndOfRange: 500}},
+		},
+	}
+
+	assert.Equal(t, expectedHoldouts, holdouts)
+}
@@ -0,0 +1,43 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package mappers
+
+import (
+	"testing"
+
+	datafileEntities "github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig/entities"
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapIntegrations(t *testing.T) {
+	const testIntegrationsString = `[
+		{"key": "odp", "host": "https://api.example.com", "publicKey": "W4WzcEs-ABgXorzY7h1LCQ"},
+		{"key": "segment"}
+	]`
+
+	var rawIntegrations []datafileEntities.Integration
+	json.Unmarshal([]byte(testIntegrationsString), &rawIntegrations)
+
+	integrations := MapIntegrations(rawIntegrations)
+	expectedIntegrations := []entities.Integration{
+		{Key: "odp", Host: "https://api.example.com", PublicKey: "W4WzcEs-ABgXorzY7h1LCQ"},
+		{Key: "segment"},
+	}
+
+	assert.Equal(t, expectedIntegrations, integrations)
+}
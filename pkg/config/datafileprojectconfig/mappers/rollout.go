@@ -36,6 +36,7 @@ func mapRollout(datafileRollout datafileEntities.Rollout) entities.Rollout {
 	rolloutExperiments := make([]entities.Experiment, len(datafileRollout.Experiments))
 	for i, datafileExperiment := range datafileRollout.Experiments {
 		experiment := mapExperiment(datafileExperiment)
+		experiment.RolloutID = datafileRollout.ID
 		rolloutExperiments[i] = experiment
 	}
 
@@ -0,0 +1,362 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package datafileprojectconfig //
+package datafileprojectconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	datafileEntities "github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig/entities"
+	"github.com/optimizely/go-sdk/pkg/entities"
+)
+
+// maxEndOfRange is the end of the last traffic allocation range covering all the traffic
+const maxEndOfRange = 10000
+
+// Severity is the severity of a validation finding
+type Severity string
+
+const (
+	// SeverityError is the severity of the findings which make decisions wrong or unpredictable
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of the findings which don't affect decisions
+	SeverityWarning Severity = "warning"
+)
+
+// Codes of the validation findings
+const (
+	FindingUnsupportedVersion     = "unsupported_version"
+	FindingDuplicateKey           = "duplicate_key"
+	FindingMissingAudience        = "missing_audience"
+	FindingMissingExperiment      = "missing_experiment"
+	FindingMissingRollout         = "missing_rollout"
+	FindingMissingVariation       = "missing_variation"
+	FindingTrafficOutOfRange      = "traffic_out_of_range"
+	FindingTrafficOverlap         = "traffic_overlap"
+	FindingInvalidVariableDefault = "invalid_variable_default"
+	FindingInvalidVariableValue   = "invalid_variable_value"
+	FindingUnknownVariable        = "unknown_variable"
+	FindingUnknownEventExperiment = "unknown_event_experiment"
+)
+
+// Finding is an issue found in a datafile. Path locates the faulty entity in the datafile, such as
+// "experiments[0].trafficAllocation[1]".
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+// ValidationReport lists the findings of the validation of a datafile
+type ValidationReport struct {
+	Revision string    `json:"revision"`
+	Findings []Finding `json:"findings"`
+}
+
+// HasErrors returns true if any finding is an error
+func (r ValidationReport) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Errors returns the findings with the error severity
+func (r ValidationReport) Errors() []Finding {
+	return r.withSeverity(SeverityError)
+}
+
+// Warnings returns the findings with the warning severity
+func (r ValidationReport) Warnings() []Finding {
+	return r.withSeverity(SeverityWarning)
+}
+
+func (r ValidationReport) withSeverity(severity Severity) []Finding {
+	var findings []Finding
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// ValidationError is the error of a datafile rejected because its validation found errors
+type ValidationError struct {
+	Report ValidationReport
+}
+
+func (e ValidationError) Error() string {
+	errs := e.Report.Errors()
+	if len(errs) == 0 {
+		return "datafile is valid"
+	}
+	return fmt.Sprintf("datafile has %d validation error(s), first at %s: %s", len(errs), errs[0].Path, errs[0].Message)
+}
+
+// ValidateDatafile parses the raw json datafile and validates it, an error is only returned when it can't be parsed
+func ValidateDatafile(jsonDatafile []byte) (ValidationReport, error) {
+	datafile, err := Parse(jsonDatafile)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	return Validate(datafile), nil
+}
+
+// Validate checks the references between the entities of the datafile, the traffic allocations and the variable
+// values, which the parsing of the datafile doesn't
+func Validate(datafile *datafileEntities.Datafile) ValidationReport {
+	v := &validator{
+		report:        ValidationReport{Revision: datafile.Revision},
+		audiences:     map[string]bool{},
+		experiments:   map[string]bool{},
+		rollouts:      map[string]bool{},
+		variableTypes: map[string]entities.VariableType{},
+	}
+	v.validate(datafile)
+	return v.report
+}
+
+type validator struct {
+	report        ValidationReport
+	audiences     map[string]bool
+	experiments   map[string]bool
+	rollouts      map[string]bool
+	variableTypes map[string]entities.VariableType
+}
+
+func (v *validator) add(severity Severity, code, path, format string, args ...interface{}) {
+	v.report.Findings = append(v.report.Findings, Finding{
+		Severity: severity,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validate(datafile *datafileEntities.Datafile) {
+	if _, ok := datafileVersions[datafile.Version]; !ok {
+		v.add(SeverityError, FindingUnsupportedVersion, "version", "version %q is not supported", datafile.Version)
+	}
+
+	for _, audience := range datafile.Audiences {
+		v.audiences[audience.ID] = true
+	}
+	for _, audience := range datafile.TypedAudiences {
+		v.audiences[audience.ID] = true
+	}
+	for _, experiment := range datafile.Experiments {
+		v.experiments[experiment.ID] = true
+	}
+	for _, group := range datafile.Groups {
+		for _, experiment := range group.Experiments {
+			v.experiments[experiment.ID] = true
+		}
+	}
+	for _, rollout := range datafile.Rollouts {
+		v.rollouts[rollout.ID] = true
+	}
+
+	featureKeys := map[string]bool{}
+	for i, featureFlag := range datafile.FeatureFlags {
+		path := fmt.Sprintf("featureFlags[%d]", i)
+		if featureKeys[featureFlag.Key] {
+			v.add(SeverityWarning, FindingDuplicateKey, path, "feature flag key %q is used more than once", featureFlag.Key)
+		}
+		featureKeys[featureFlag.Key] = true
+		v.validateFeatureFlag(path, featureFlag)
+	}
+
+	experimentKeys := map[string]bool{}
+	validateExperiments := func(path string, experiments []datafileEntities.Experiment) {
+		for i, experiment := range experiments {
+			experimentPath := fmt.Sprintf("%s[%d]", path, i)
+			if experimentKeys[experiment.Key] {
+				v.add(SeverityWarning, FindingDuplicateKey, experimentPath, "experiment key %q is used more than once", experiment.Key)
+			}
+			experimentKeys[experiment.Key] = true
+			v.validateExperiment(experimentPath, experiment)
+		}
+	}
+	validateExperiments("experiments", datafile.Experiments)
+	for i, group := range datafile.Groups {
+		path := fmt.Sprintf("groups[%d]", i)
+		experimentIDs := map[string]bool{}
+		for _, experiment := range group.Experiments {
+			experimentIDs[experiment.ID] = true
+		}
+		v.validateTrafficAllocation(path+".trafficAllocation", group.TrafficAllocation, experimentIDs, FindingMissingExperiment, "experiment")
+		validateExperiments(path+".experiments", group.Experiments)
+	}
+	for i, rollout := range datafile.Rollouts {
+		for j, rule := range rollout.Experiments {
+			v.validateExperiment(fmt.Sprintf("rollouts[%d].experiments[%d]", i, j), rule)
+		}
+	}
+
+	for i, event := range datafile.Events {
+		for j, experimentID := range event.ExperimentIds {
+			if !v.experiments[experimentID] {
+				v.add(SeverityWarning, FindingUnknownEventExperiment, fmt.Sprintf("events[%d].experimentIds[%d]", i, j),
+					"event %q references the unknown experiment %q", event.Key, experimentID)
+			}
+		}
+	}
+}
+
+func (v *validator) validateFeatureFlag(path string, featureFlag datafileEntities.FeatureFlag) {
+	if featureFlag.RolloutID != "" && !v.rollouts[featureFlag.RolloutID] {
+		v.add(SeverityError, FindingMissingRollout, path+".rolloutId", "feature flag %q references the missing rollout %q",
+			featureFlag.Key, featureFlag.RolloutID)
+	}
+	for i, experimentID := range featureFlag.ExperimentIDs {
+		if !v.experiments[experimentID] {
+			v.add(SeverityError, FindingMissingExperiment, fmt.Sprintf("%s.experimentIds[%d]", path, i),
+				"feature flag %q references the missing experiment %q", featureFlag.Key, experimentID)
+		}
+	}
+	for i, variable := range featureFlag.Variables {
+		resolvedType := resolveVariableType(variable.Type, variable.SubType)
+		v.variableTypes[variable.ID] = resolvedType
+		if err := checkVariableValue(resolvedType, variable.DefaultValue); err != nil {
+			v.add(SeverityError, FindingInvalidVariableDefault, fmt.Sprintf("%s.variables[%d].defaultValue", path, i),
+				"default value %q of variable %q is not a valid %s: %v", variable.DefaultValue, variable.Key, resolvedType, err)
+		}
+	}
+}
+
+func (v *validator) validateExperiment(path string, experiment datafileEntities.Experiment) {
+	for i, audienceID := range experiment.AudienceIds {
+		if !v.audiences[audienceID] {
+			v.add(SeverityError, FindingMissingAudience, fmt.Sprintf("%s.audienceIds[%d]", path, i),
+				"experiment %q references the missing audience %q", experiment.Key, audienceID)
+		}
+	}
+	for _, audienceID := range conditionAudienceIDs(experiment.AudienceConditions) {
+		if !v.audiences[audienceID] {
+			v.add(SeverityError, FindingMissingAudience, path+".audienceConditions",
+				"experiment %q references the missing audience %q", experiment.Key, audienceID)
+		}
+	}
+
+	variationIDs := map[string]bool{}
+	for i, variation := range experiment.Variations {
+		variationIDs[variation.ID] = true
+		for j, variable := range variation.Variables {
+			variablePath := fmt.Sprintf("%s.variations[%d].variables[%d]", path, i, j)
+			declaredType, ok := v.variableTypes[variable.ID]
+			if !ok {
+				v.add(SeverityWarning, FindingUnknownVariable, variablePath, "variation %q of experiment %q sets the unknown variable %q",
+					variation.Key, experiment.Key, variable.ID)
+				continue
+			}
+			if err := checkVariableValue(declaredType, variable.Value); err != nil {
+				v.add(SeverityError, FindingInvalidVariableValue, variablePath, "value %q of variable %q in variation %q is not a valid %s: %v",
+					variable.Value, variable.ID, variation.Key, declaredType, err)
+			}
+		}
+	}
+	userIDs := make([]string, 0, len(experiment.ForcedVariations))
+	for userID := range experiment.ForcedVariations {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	for _, userID := range userIDs {
+		variationKey := experiment.ForcedVariations[userID]
+		found := false
+		for _, variation := range experiment.Variations {
+			if variation.Key == variationKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(SeverityWarning, FindingMissingVariation, path+".forcedVariations",
+				"experiment %q forces the missing variation %q on user %q", experiment.Key, variationKey, userID)
+		}
+	}
+	v.validateTrafficAllocation(path+".trafficAllocation", experiment.TrafficAllocation, variationIDs, FindingMissingVariation, "variation")
+}
+
+// validateTrafficAllocation checks the ranges are within the traffic and don't overlap, the ranges are contiguous so
+// each one must not end before the previous one. Empty ranges, ending where the previous one ends, are valid: they
+// give no traffic to an entity.
+func (v *validator) validateTrafficAllocation(path string, trafficAllocation []datafileEntities.TrafficAllocation, entityIDs map[string]bool,
+	missingCode, entityType string) {
+	previousEnd := 0
+	for i, allocation := range trafficAllocation {
+		allocationPath := fmt.Sprintf("%s[%d]", path, i)
+		if allocation.EndOfRange < 0 || allocation.EndOfRange > maxEndOfRange {
+			v.add(SeverityError, FindingTrafficOutOfRange, allocationPath, "end of range %d is not between 0 and %d",
+				allocation.EndOfRange, maxEndOfRange)
+		} else if i > 0 && allocation.EndOfRange < previousEnd {
+			v.add(SeverityError, FindingTrafficOverlap, allocationPath, "range ending at %d overlaps the previous range ending at %d",
+				allocation.EndOfRange, previousEnd)
+		}
+		previousEnd = allocation.EndOfRange
+		// an empty entity ID holds back traffic
+		if allocation.EntityID != "" && !entityIDs[allocation.EntityID] {
+			v.add(SeverityError, missingCode, allocationPath, "traffic is allocated to the missing %s %q",
+				entityType, allocation.EntityID)
+		}
+	}
+}
+
+// conditionAudienceIDs returns the audience IDs of audience conditions such as ["and", "1", ["or", "2", "3"]]
+func conditionAudienceIDs(conditions interface{}) []string {
+	var audienceIDs []string
+	switch value := conditions.(type) {
+	case string:
+		switch value {
+		case "and", "or", "not":
+		default:
+			audienceIDs = append(audienceIDs, value)
+		}
+	case []interface{}:
+		for _, condition := range value {
+			audienceIDs = append(audienceIDs, conditionAudienceIDs(condition)...)
+		}
+	}
+	return audienceIDs
+}
+
+// resolveVariableType returns the type the values of a variable are parsed as, JSON variables may be declared as
+// strings with a JSON sub type
+func resolveVariableType(variableType, subType entities.VariableType) entities.VariableType {
+	if variableType == entities.String && subType == entities.JSON {
+		return entities.JSON
+	}
+	return variableType
+}
+
+func checkVariableValue(variableType entities.VariableType, value string) (err error) {
+	switch variableType {
+	case entities.Boolean:
+		_, err = strconv.ParseBool(value)
+	case entities.Integer:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case entities.Double:
+		_, err = strconv.ParseFloat(value, 64)
+	case entities.JSON:
+		var v interface{}
+		if json.Unmarshal([]byte(value), &v) != nil {
+			err = errors.New("invalid JSON")
+		}
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package datafileprojectconfig //
+package datafileprojectconfig
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValidDatafile(t *testing.T) {
+	datafile, err := ioutil.ReadFile("test/100_entities.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	report, err := ValidateDatafile(datafile)
+	assert.NoError(t, err)
+	assert.False(t, report.HasErrors(), "%v", report.Errors())
+}
+
+func TestValidateUnparsableDatafile(t *testing.T) {
+	_, err := ValidateDatafile([]byte(`{"revision":`))
+	assert.Error(t, err)
+}
+
+func TestValidateDatafile(t *testing.T) {
+	datafile := `{
+		"revision": "7",
+		"version": "4",
+		"audiences": [{"id": "a1", "name": "a1", "conditions": "[\"or\"]"}],
+		"experiments": [
+			{"id": "e1", "key": "exp_1", "audienceIds": ["a1", "a2"], "audienceConditions": ["and", "a1", ["not", "a3"]],
+			 "variations": [{"id": "v1", "key": "on", "variables": [{"id": "var1", "value": "yes"}, {"id": "var9", "value": "1"}]}],
+			 "forcedVariations": {"user_1": "off"},
+			 "trafficAllocation": [{"entityId": "v1", "endOfRange": 6000}, {"entityId": "v1", "endOfRange": 5000},
+			                       {"entityId": "v2", "endOfRange": 12000}]}
+		],
+		"groups": [
+			{"id": "g1", "trafficAllocation": [{"entityId": "e9", "endOfRange": 10000}], "experiments": []}
+		],
+		"rollouts": [],
+		"featureFlags": [
+			{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": ["e1", "e2"],
+			 "variables": [{"id": "var1", "key": "enabled", "type": "boolean", "defaultValue": "true"},
+			               {"id": "var2", "key": "count", "type": "integer", "defaultValue": "1.5"},
+			               {"id": "var3", "key": "payload", "type": "string", "subType": "json", "defaultValue": "{"}]}
+		],
+		"events": [{"id": "ev1", "key": "purchase", "experimentIds": ["e1", "e3"]}]
+	}`
+
+	report, err := ValidateDatafile([]byte(datafile))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "7", report.Revision)
+	assert.Equal(t, []Finding{
+		{SeverityError, FindingMissingRollout, "featureFlags[0].rolloutId", `feature flag "flag_1" references the missing rollout "r1"`},
+		{SeverityError, FindingMissingExperiment, "featureFlags[0].experimentIds[1]", `feature flag "flag_1" references the missing experiment "e2"`},
+		{SeverityError, FindingInvalidVariableDefault, "featureFlags[0].variables[1].defaultValue", `default value "1.5" of variable "count" is not a valid integer: strconv.ParseInt: parsing "1.5": invalid syntax`},
+		{SeverityError, FindingInvalidVariableDefault, "featureFlags[0].variables[2].defaultValue", `default value "{" of variable "payload" is not a valid json: invalid JSON`},
+		{SeverityError, FindingMissingAudience, "experiments[0].audienceIds[1]", `experiment "exp_1" references the missing audience "a2"`},
+		{SeverityError, FindingMissingAudience, "experiments[0].audienceConditions", `experiment "exp_1" references the missing audience "a3"`},
+		{SeverityError, FindingInvalidVariableValue, "experiments[0].variations[0].variables[0]", `value "yes" of variable "var1" in variation "on" is not a valid boolean: strconv.ParseBool: parsing "yes": invalid syntax`},
+		{SeverityWarning, FindingUnknownVariable, "experiments[0].variations[0].variables[1]", `variation "on" of experiment "exp_1" sets the unknown variable "var9"`},
+		{SeverityWarning, FindingMissingVariation, "experiments[0].forcedVariations", `experiment "exp_1" forces the missing variation "off" on user "user_1"`},
+		{SeverityError, FindingTrafficOverlap, "experiments[0].trafficAllocation[1]", "range ending at 5000 overlaps the previous range ending at 6000"},
+		{SeverityError, FindingTrafficOutOfRange, "experiments[0].trafficAllocation[2]", "end of range 12000 is not between 0 and 10000"},
+		{SeverityError, FindingMissingVariation, "experiments[0].trafficAllocation[2]", `traffic is allocated to the missing variation "v2"`},
+		{SeverityError, FindingMissingExperiment, "groups[0].trafficAllocation[0]", `traffic is allocated to the missing experiment "e9"`},
+		{SeverityWarning, FindingUnknownEventExperiment, "events[0].experimentIds[1]", `event "purchase" references the unknown experiment "e3"`},
+	}, report.Findings)
+	assert.True(t, report.HasErrors())
+	assert.Len(t, report.Warnings(), 3)
+
+	validationError := ValidationError{Report: report}
+	assert.Equal(t, `datafile has 11 validation error(s), first at featureFlags[0].rolloutId: feature flag "flag_1" references the missing rollout "r1"`,
+		validationError.Error())
+}
+
+func TestValidateEmptyTrafficAllocationRanges(t *testing.T) {
+	datafile := `{
+		"revision": "7",
+		"version": "4",
+		"experiments": [
+			{"id": "e1", "key": "exp_1",
+			 "variations": [{"id": "v1", "key": "on"}, {"id": "v2", "key": "off"}],
+			 "trafficAllocation": [{"entityId": "v1", "endOfRange": 0}, {"entityId": "v2", "endOfRange": 0},
+			                       {"entityId": "v1", "endOfRange": 5000}, {"entityId": "v2", "endOfRange": 5000}]}
+		]
+	}`
+
+	report, err := ValidateDatafile([]byte(datafile))
+	assert.NoError(t, err)
+	assert.Empty(t, report.Findings)
+}
+
+func TestValidateUnsupportedVersion(t *testing.T) {
+	report, err := ValidateDatafile([]byte(`{"revision": "1", "version": "2"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Finding{{SeverityError, FindingUnsupportedVersion, "version", `version "2" is not supported`}}, report.Findings)
+}
@@ -0,0 +1,117 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/optimizely/go-sdk/pkg/notification"
+)
+
+// DiffProjectConfigs returns the changes between the previous and the current project config, a nil config is
+// considered empty
+func DiffProjectConfigs(previous, current ProjectConfig) notification.ProjectConfigDiff {
+	previousFlags, previousVariables := flagsAndVariables(previous)
+	currentFlags, currentVariables := flagsAndVariables(current)
+	previousExperiments, previousRules := experimentsAndRules(previous)
+	currentExperiments, currentRules := experimentsAndRules(current)
+
+	diff := notification.ProjectConfigDiff{
+		Flags:       diffEntities(previousFlags, currentFlags),
+		Experiments: diffEntities(previousExperiments, currentExperiments),
+		Audiences:   diffEntities(audiences(previous), audiences(current)),
+		Variables:   diffEntities(previousVariables, currentVariables),
+	}
+
+	for key, rule := range currentRules {
+		if previousRule, ok := previousRules[key]; ok && !reflect.DeepEqual(previousRule.TrafficAllocation, rule.TrafficAllocation) {
+			diff.TrafficAllocations = append(diff.TrafficAllocations, key)
+		}
+	}
+	sort.Strings(diff.TrafficAllocations)
+	return diff
+}
+
+func flagsAndVariables(projectConfig ProjectConfig) (flags, variables map[string]interface{}) {
+	flags = map[string]interface{}{}
+	variables = map[string]interface{}{}
+	if projectConfig == nil {
+		return flags, variables
+	}
+	for _, feature := range projectConfig.GetFeatureList() {
+		flags[feature.Key] = feature
+		for _, variable := range feature.VariableMap {
+			variables[feature.Key+"."+variable.Key] = variable
+		}
+	}
+	return flags, variables
+}
+
+// experimentsAndRules returns the experiments, and the experiments along with the rollout rules, keyed by their key
+func experimentsAndRules(projectConfig ProjectConfig) (experiments map[string]interface{}, rules map[string]entities.Experiment) {
+	experiments = map[string]interface{}{}
+	rules = map[string]entities.Experiment{}
+	if projectConfig == nil {
+		return experiments, rules
+	}
+	for _, experiment := range projectConfig.GetExperimentList() {
+		experiments[experiment.Key] = experiment
+		rules[experiment.Key] = experiment
+	}
+	for _, feature := range projectConfig.GetFeatureList() {
+		for _, rule := range feature.Rollout.Experiments {
+			rules[rule.Key] = rule
+		}
+	}
+	return experiments, rules
+}
+
+func audiences(projectConfig ProjectConfig) map[string]interface{} {
+	audienceMap := map[string]interface{}{}
+	if projectConfig == nil {
+		return audienceMap
+	}
+	for id, audience := range projectConfig.GetAudienceMap() {
+		audienceMap[id] = audience
+	}
+	return audienceMap
+}
+
+func diffEntities(previous, current map[string]interface{}) notification.EntityChanges {
+	changes := notification.EntityChanges{}
+	for key, entity := range current {
+		previousEntity, ok := previous[key]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, key)
+		case !reflect.DeepEqual(previousEntity, entity):
+			changes.Changed = append(changes.Changed, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			changes.Removed = append(changes.Removed, key)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+	return changes
+}
@@ -0,0 +1,134 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig"
+	"github.com/optimizely/go-sdk/pkg/notification"
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const diffDatafile = `{
+	"revision": "1",
+	"version": "4",
+	"audiences": [
+		{"id": "a1", "name": "chrome", "conditions": "[\"and\"]"},
+		{"id": "a2", "name": "firefox", "conditions": "[\"or\"]"}
+	],
+	"experiments": [
+		{"id": "e1", "key": "exp_1", "layerId": "l1", "status": "Running", "audienceIds": ["a1"],
+		 "variations": [{"id": "v1", "key": "on", "featureEnabled": true}],
+		 "trafficAllocation": [{"entityId": "v1", "endOfRange": 5000}]},
+		{"id": "e2", "key": "exp_2", "layerId": "l2", "status": "Running", "audienceIds": [],
+		 "variations": [{"id": "v2", "key": "control"}],
+		 "trafficAllocation": [{"entityId": "v2", "endOfRange": 10000}]}
+	],
+	"rollouts": [
+		{"id": "r1", "experiments": [
+			{"id": "rule1", "key": "rule_1", "layerId": "r1", "status": "Running", "audienceIds": [],
+			 "variations": [{"id": "rv1", "key": "rollout_on", "featureEnabled": true}],
+			 "trafficAllocation": [{"entityId": "rv1", "endOfRange": 1000}]}
+		]}
+	],
+	"featureFlags": [
+		{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": ["e1"],
+		 "variables": [{"id": "var1", "key": "color", "type": "string", "defaultValue": "red"},
+		               {"id": "var2", "key": "size", "type": "integer", "defaultValue": "1"}]},
+		{"id": "f2", "key": "flag_2", "rolloutId": "", "experimentIds": [], "variables": []}
+	]
+}`
+
+func newDiffProjectConfig(t *testing.T, replacements ...string) ProjectConfig {
+	datafile := strings.NewReplacer(replacements...).Replace(diffDatafile)
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig([]byte(datafile), logger)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return projectConfig
+}
+
+func TestDiffProjectConfigsWithoutChanges(t *testing.T) {
+	diff := DiffProjectConfigs(newDiffProjectConfig(t), newDiffProjectConfig(t, `"revision": "1"`, `"revision": "2"`))
+	assert.True(t, diff.IsEmpty())
+}
+
+func TestDiffProjectConfigsFromNothing(t *testing.T) {
+	diff := DiffProjectConfigs(nil, newDiffProjectConfig(t))
+	assert.Equal(t, []string{"flag_1", "flag_2"}, diff.Flags.Added)
+	assert.Equal(t, []string{"exp_1", "exp_2"}, diff.Experiments.Added)
+	assert.Equal(t, []string{"a1", "a2"}, diff.Audiences.Added)
+	assert.Equal(t, []string{"flag_1.color", "flag_1.size"}, diff.Variables.Added)
+	assert.Empty(t, diff.TrafficAllocations)
+
+	assert.True(t, DiffProjectConfigs(nil, nil).IsEmpty())
+}
+
+func TestDiffProjectConfigs(t *testing.T) {
+	previous := newDiffProjectConfig(t)
+	current := newDiffProjectConfig(t,
+		// flag_2 is removed and flag_3 added
+		`"key": "flag_2"`, `"key": "flag_3"`,
+		// the default value of color changes
+		`"defaultValue": "red"`, `"defaultValue": "blue"`,
+		// the traffic of exp_1 and of the rollout rule changes
+		`"endOfRange": 5000`, `"endOfRange": 7500`,
+		`"endOfRange": 1000}`, `"endOfRange": 2000}`,
+		// the audience a2 changes
+		`"name": "firefox"`, `"name": "safari"`,
+	)
+
+	diff := DiffProjectConfigs(previous, current)
+	assert.Equal(t, notification.EntityChanges{
+		Added:   []string{"flag_3"},
+		Removed: []string{"flag_2"},
+		Changed: []string{"flag_1"},
+	}, diff.Flags)
+	assert.Equal(t, notification.EntityChanges{Changed: []string{"exp_1"}}, diff.Experiments)
+	assert.Equal(t, notification.EntityChanges{Changed: []string{"a2"}}, diff.Audiences)
+	assert.Equal(t, notification.EntityChanges{Changed: []string{"flag_1.color"}}, diff.Variables)
+	assert.Equal(t, []string{"exp_1", "rule_1"}, diff.TrafficAllocations)
+	assert.False(t, diff.IsEmpty())
+}
+
+func TestDiffInProjectConfigUpdateNotification(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(strings.Replace(diffDatafile, `"revision": "1"`, `"revision": "2"`, 1)), http.Header{}, http.StatusOK, nil)
+	configManager := NewPollingProjectConfigManager("diff_sdk_key", WithRequester(mockRequester),
+		WithInitialDatafile([]byte(`{"revision":"1","version": "4"}`)))
+
+	var notifications []notification.ProjectConfigUpdateNotification
+	id, _ := configManager.OnProjectConfigUpdate(func(n notification.ProjectConfigUpdateNotification) {
+		notifications = append(notifications, n)
+	})
+	defer func() {
+		assert.NoError(t, configManager.RemoveOnProjectConfigUpdate(id))
+	}()
+
+	configManager.SyncConfig()
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, "2", notifications[0].Revision)
+		assert.Equal(t, "1", notifications[0].OldRevision)
+		assert.Equal(t, []string{"flag_1", "flag_2"}, notifications[0].Diff.Flags.Added)
+		assert.Equal(t, []string{"exp_1", "exp_2"}, notifications[0].Diff.Experiments.Added)
+	}
+}
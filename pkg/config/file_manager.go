@@ -0,0 +1,395 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig"
+	"github.com/optimizely/go-sdk/pkg/logging"
+	"github.com/optimizely/go-sdk/pkg/metrics"
+	"github.com/optimizely/go-sdk/pkg/notification"
+	"github.com/optimizely/go-sdk/pkg/registry"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultWatchInterval sets the default interval at which the datafile is checked for changes
+const DefaultWatchInterval = time.Second
+
+// FileProjectConfigManager maintains the project config of a datafile on disk, the datafile is parsed again whenever
+// its modification time or size changes.
+type FileProjectConfigManager struct {
+	path               string
+	sdkKey             string
+	watchInterval      time.Duration
+	revisionPolicy     *MonotonicRevisionPolicy
+	strictValidation   bool
+	signatureVerifier  SignatureVerifier
+	signatureSidecar   string
+	notificationCenter notification.Center
+	logger             logging.OptimizelyLogProducer
+
+	rejectedCounter         metrics.Counter
+	rejectedRevisionCounter metrics.Counter
+
+	modTime          time.Time
+	size             int64
+	signatureModTime time.Time
+	signatureSize    int64
+
+	configLock       sync.RWMutex
+	err              error
+	projectConfig    ProjectConfig
+	optimizelyConfig *OptimizelyConfig
+
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// FileOptionFunc is used to provide custom configuration to the FileProjectConfigManager.
+type FileOptionFunc func(*FileProjectConfigManager)
+
+// WithWatchInterval is an optional function, sets the interval at which the datafile is checked for changes
+func WithWatchInterval(interval time.Duration) FileOptionFunc {
+	return func(cm *FileProjectConfigManager) {
+		cm.watchInterval = interval
+	}
+}
+
+// WithFileRevisionPolicy is an optional function, sets the policy rejecting the datafiles with an older revision than
+// the current one
+func WithFileRevisionPolicy(policy *MonotonicRevisionPolicy) FileOptionFunc {
+	return func(cm *FileProjectConfigManager) {
+		cm.revisionPolicy = policy
+	}
+}
+
+// WithFileStrictValidation is an optional function, when strict the datafiles with validation errors are rejected, see
+// datafileprojectconfig.Validate
+func WithFileStrictValidation(strict bool) FileOptionFunc {
+	return func(cm *FileProjectConfigManager) {
+		cm.strictValidation = strict
+	}
+}
+
+// WithFileSignatureVerifier is an optional function, sets the verifier of the datafile. Datafiles without a valid
+// signature are rejected, the base64 encoded signature is read from the sidecar file next to the datafile.
+func WithFileSignatureVerifier(verifier SignatureVerifier) FileOptionFunc {
+	return func(cm *FileProjectConfigManager) {
+		cm.signatureVerifier = verifier
+	}
+}
+
+// WithFileSignatureSidecar is an optional function, sets the suffix of the sidecar file holding the signature of the
+// datafile, DefaultSignatureSidecar by default
+func WithFileSignatureSidecar(suffix string) FileOptionFunc {
+	return func(cm *FileProjectConfigManager) {
+		cm.signatureSidecar = suffix
+	}
+}
+
+// WithFileMetricsRegistry is an optional function, sets the registry of the rejected datafile metrics
+func WithFileMetricsRegistry(metricsRegistry metrics.Registry) FileOptionFunc {
+	return func(cm *FileProjectConfigManager) {
+		cm.rejectedCounter = metricsRegistry.GetCounter(metrics.ConfigManagerRejectedDatafile)
+		cm.rejectedRevisionCounter = metricsRegistry.GetCounter(metrics.ConfigManagerRejectedRevision)
+	}
+}
+
+// NewFileProjectConfigManager returns a manager of the datafile at the given path, the datafile is loaded right away
+func NewFileProjectConfigManager(sdkKey, path string, options ...FileOptionFunc) *FileProjectConfigManager {
+	fileProjectConfigManager := &FileProjectConfigManager{
+		path:               path,
+		sdkKey:             sdkKey,
+		watchInterval:      DefaultWatchInterval,
+		notificationCenter: registry.GetNotificationCenter(sdkKey),
+		logger:             logging.GetLogger(sdkKey, "FileProjectConfigManager"),
+		signatureSidecar:   DefaultSignatureSidecar,
+		ready:              make(chan struct{}),
+	}
+	WithFileMetricsRegistry(metrics.NewNoopRegistry())(fileProjectConfigManager)
+
+	for _, opt := range options {
+		opt(fileProjectConfigManager)
+	}
+
+	fileProjectConfigManager.SyncConfig()
+	return fileProjectConfigManager
+}
+
+// SyncConfig parses the datafile again when it, or the sidecar file of its signature, changed since it was last read.
+// Like for the polling manager, a datafile with the same revision as the current project config is not an update.
+func (cm *FileProjectConfigManager) SyncConfig() {
+	info, err := os.Stat(cm.path)
+	if err != nil {
+		cm.logger.Warning(fmt.Sprintf("Unable to read the datafile %s: %v", cm.path, err))
+		cm.configLock.Lock()
+		cm.err = err
+		cm.configLock.Unlock()
+		return
+	}
+	var signatureModTime time.Time
+	var signatureSize int64
+	if cm.signatureVerifier != nil {
+		// the signature may be written after the datafile, a missing sidecar is reported when the datafile is verified
+		if signatureInfo, e := os.Stat(cm.path + cm.signatureSidecar); e == nil {
+			signatureModTime = signatureInfo.ModTime()
+			signatureSize = signatureInfo.Size()
+		}
+	}
+
+	cm.configLock.Lock()
+	if info.ModTime().Equal(cm.modTime) && info.Size() == cm.size &&
+		signatureModTime.Equal(cm.signatureModTime) && signatureSize == cm.signatureSize {
+		cm.configLock.Unlock()
+		return
+	}
+	// a datafile which can't be parsed or verified is not read again until it changes
+	cm.modTime = info.ModTime()
+	cm.size = info.Size()
+	cm.signatureModTime = signatureModTime
+	cm.signatureSize = signatureSize
+	cm.configLock.Unlock()
+
+	datafile, err := ioutil.ReadFile(cm.path)
+	if err != nil {
+		cm.logger.Warning(fmt.Sprintf("Unable to read the datafile %s: %v", cm.path, err))
+		cm.setError(err)
+		return
+	}
+
+	if err = cm.verifySignature(datafile); err != nil {
+		cm.rejectDatafile(err)
+		return
+	}
+
+	if cm.strictValidation {
+		if err = validateDatafile(datafile, cm.logger); err != nil {
+			cm.logger.Warning(fmt.Sprintf("Datafile %s is invalid, keeping the current project config: %v", cm.path, err))
+			cm.rejectDatafile(err)
+			return
+		}
+	}
+
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "DatafileProjectConfig"))
+	if err != nil {
+		cm.logger.Warning(fmt.Sprintf("Unable to parse the datafile %s, keeping the current project config: %v", cm.path, err))
+		cm.setError(errors.New("unable to parse datafile"))
+		return
+	}
+
+	cm.configLock.Lock()
+	previousConfig := cm.projectConfig
+	var previousRevision string
+	if previousConfig != nil {
+		previousRevision = previousConfig.GetRevision()
+	}
+	if cm.revisionPolicy != nil && previousRevision != "" {
+		if err = cm.revisionPolicy.Check(cm.sdkKey, previousRevision, projectConfig.GetRevision()); err != nil {
+			cm.configLock.Unlock()
+			cm.logger.Warning(fmt.Sprintf("Datafile %s with revision %s is older than the current revision %s, keeping the current project config",
+				cm.path, projectConfig.GetRevision(), previousRevision))
+			cm.rejectedRevisionCounter.Add(1)
+			cm.rejectDatafile(err)
+			return
+		}
+	}
+	if previousConfig != nil && projectConfig.GetRevision() == previousRevision {
+		cm.err = nil
+		cm.configLock.Unlock()
+		cm.logger.Debug(fmt.Sprintf("No datafile updates in %s. Current revision number: %s", cm.path, previousRevision))
+		return
+	}
+	cm.projectConfig = projectConfig
+	cm.err = nil
+	if cm.optimizelyConfig != nil {
+		cm.optimizelyConfig = NewOptimizelyConfig(projectConfig)
+	}
+	cm.readyOnce.Do(func() {
+		close(cm.ready)
+	})
+	cm.configLock.Unlock()
+
+	cm.logger.Debug(fmt.Sprintf("Datafile %s loaded with revision: %s. Old revision: %s", cm.path, projectConfig.GetRevision(), previousRevision))
+	cm.sendConfigUpdateNotification(previousConfig, projectConfig)
+}
+
+// Start checks the datafile for changes at the watch interval until the context is done
+func (cm *FileProjectConfigManager) Start(ctx context.Context) {
+	if cm.watchInterval <= 0 {
+		cm.logger.Info("File Config Manager Disabled")
+		return
+	}
+	cm.logger.Debug(fmt.Sprintf("Watching datafile %s", cm.path))
+	t := time.NewTicker(cm.watchInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			cm.SyncConfig()
+		case <-ctx.Done():
+			cm.logger.Debug("File Config Manager Stopped")
+			return
+		}
+	}
+}
+
+// GetConfig returns the project config, the last valid one when the datafile can no longer be parsed
+func (cm *FileProjectConfigManager) GetConfig() (ProjectConfig, error) {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	if cm.projectConfig == nil {
+		return nil, cm.err
+	}
+	return cm.projectConfig, nil
+}
+
+// GetOptimizelyConfig returns the optimizely project config
+func (cm *FileProjectConfigManager) GetOptimizelyConfig() *OptimizelyConfig {
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	if cm.optimizelyConfig != nil {
+		return cm.optimizelyConfig
+	}
+	cm.optimizelyConfig = NewOptimizelyConfig(cm.projectConfig)
+	return cm.optimizelyConfig
+}
+
+// Ready returns a channel which is closed once the first valid project config has been set
+func (cm *FileProjectConfigManager) Ready() <-chan struct{} {
+	return cm.ready
+}
+
+// ConfigSource returns where the current project config comes from
+func (cm *FileProjectConfigManager) ConfigSource() ConfigSource {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	if cm.projectConfig == nil {
+		return ConfigSourceNone
+	}
+	return ConfigSourceFile
+}
+
+// OnProjectConfigUpdate registers a handler for ProjectConfigUpdate notifications
+func (cm *FileProjectConfigManager) OnProjectConfigUpdate(callback func(notification.ProjectConfigUpdateNotification)) (int, error) {
+	handler := func(payload interface{}) {
+		if projectConfigUpdateNotification, ok := payload.(notification.ProjectConfigUpdateNotification); ok {
+			callback(projectConfigUpdateNotification)
+		} else {
+			cm.logger.Warning(fmt.Sprintf("Unable to convert notification payload %v into ProjectConfigUpdateNotification", payload))
+		}
+	}
+	id, err := cm.notificationCenter.AddHandler(notification.ProjectConfigUpdate, handler)
+	if err != nil {
+		cm.logger.Warning("Problem with adding notification handler")
+		return 0, err
+	}
+	return id, nil
+}
+
+// RemoveOnProjectConfigUpdate removes handler for ProjectConfigUpdate notification with given id
+func (cm *FileProjectConfigManager) RemoveOnProjectConfigUpdate(id int) error {
+	if err := cm.notificationCenter.RemoveHandler(id, notification.ProjectConfigUpdate); err != nil {
+		cm.logger.Warning("Problem with removing notification handler")
+		return err
+	}
+	return nil
+}
+
+// OnProjectConfigRejected registers a handler for ProjectConfigRejected notifications
+func (cm *FileProjectConfigManager) OnProjectConfigRejected(callback func(notification.ProjectConfigRejectedNotification)) (int, error) {
+	handler := func(payload interface{}) {
+		if projectConfigRejectedNotification, ok := payload.(notification.ProjectConfigRejectedNotification); ok {
+			callback(projectConfigRejectedNotification)
+		} else {
+			cm.logger.Warning(fmt.Sprintf("Unable to convert notification payload %v into ProjectConfigRejectedNotification", payload))
+		}
+	}
+	id, err := cm.notificationCenter.AddHandler(notification.ProjectConfigRejected, handler)
+	if err != nil {
+		cm.logger.Warning("Problem with adding notification handler")
+		return 0, err
+	}
+	return id, nil
+}
+
+// RemoveOnProjectConfigRejected removes handler for ProjectConfigRejected notification with given id
+func (cm *FileProjectConfigManager) RemoveOnProjectConfigRejected(id int) error {
+	if err := cm.notificationCenter.RemoveHandler(id, notification.ProjectConfigRejected); err != nil {
+		cm.logger.Warning("Problem with removing notification handler")
+		return err
+	}
+	return nil
+}
+
+// verifySignature verifies the datafile against the signature of its sidecar file when a signature verifier is set
+func (cm *FileProjectConfigManager) verifySignature(datafile []byte) error {
+	if cm.signatureVerifier == nil {
+		return nil
+	}
+	sidecar, err := NewFileDatafileSource(cm.path).FetchSignature(DatafileRequest{SDKKey: cm.sdkKey}, cm.signatureSidecar)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrMissingSignature
+		}
+		return fmt.Errorf("unable to read the datafile signature: %v", err)
+	}
+	return verifyDatafile(cm.signatureVerifier, datafile, string(sidecar))
+}
+
+// rejectDatafile keeps the current project config and reports the rejection of a datafile
+func (cm *FileProjectConfigManager) rejectDatafile(reason error) {
+	cm.rejectedCounter.Add(1)
+	cm.configLock.Lock()
+	cm.err = reason
+	var revision string
+	if cm.projectConfig != nil {
+		revision = cm.projectConfig.GetRevision()
+	}
+	cm.configLock.Unlock()
+
+	sendConfigRejectedNotification(cm.notificationCenter, cm.logger, revision, reason)
+}
+
+func (cm *FileProjectConfigManager) setError(err error) {
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	cm.err = err
+}
+
+func (cm *FileProjectConfigManager) sendConfigUpdateNotification(previousConfig, projectConfig ProjectConfig) {
+	if cm.notificationCenter != nil {
+		projectConfigUpdateNotification := notification.ProjectConfigUpdateNotification{
+			Type:     notification.ProjectConfigUpdate,
+			Revision: projectConfig.GetRevision(),
+			Diff:     DiffProjectConfigs(previousConfig, projectConfig),
+		}
+		if previousConfig != nil {
+			projectConfigUpdateNotification.OldRevision = previousConfig.GetRevision()
+		}
+		if err := cm.notificationCenter.Send(notification.ProjectConfigUpdate, projectConfigUpdateNotification); err != nil {
+			cm.logger.Warning("Problem with sending notification")
+		}
+	}
+}
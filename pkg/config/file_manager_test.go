@@ -0,0 +1,224 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig"
+	"github.com/optimizely/go-sdk/pkg/metrics"
+	"github.com/optimizely/go-sdk/pkg/notification"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FileProjectConfigManagerTestSuite struct {
+	suite.Suite
+	dir     string
+	path    string
+	modTime time.Time
+}
+
+func (s *FileProjectConfigManagerTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "file-manager")
+	s.Require().NoError(err)
+	s.dir = dir
+	s.path = filepath.Join(dir, "datafile.json")
+	s.modTime = time.Now().Add(-time.Hour)
+}
+
+func (s *FileProjectConfigManagerTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+// writeDatafile writes the datafile with a new modification time, so that changes are seen even on file systems with
+// a coarse time resolution
+func (s *FileProjectConfigManagerTestSuite) writeDatafile(datafile string) {
+	s.Require().NoError(ioutil.WriteFile(s.path, []byte(datafile), 0600))
+	s.modTime = s.modTime.Add(time.Second)
+	s.Require().NoError(os.Chtimes(s.path, s.modTime, s.modTime))
+}
+
+// writeSignature writes the signature sidecar file of the datafile with a new modification time
+func (s *FileProjectConfigManagerTestSuite) writeSignature(key []byte, datafile string) {
+	path := s.path + DefaultSignatureSidecar
+	s.Require().NoError(ioutil.WriteFile(path, []byte(hmacSignature(key, []byte(datafile))), 0600))
+	s.modTime = s.modTime.Add(time.Second)
+	s.Require().NoError(os.Chtimes(path, s.modTime, s.modTime))
+}
+
+func (s *FileProjectConfigManagerTestSuite) TestMissingFile() {
+	configManager := NewFileProjectConfigManager("file_sdk_key", s.path)
+	_, err := configManager.GetConfig()
+	s.Error(err)
+	s.Equal(ConfigSourceNone, configManager.ConfigSource())
+
+	s.writeDatafile(`{"revision":"1","version": "4"}`)
+	configManager.SyncConfig()
+	projectConfig, err := configManager.GetConfig()
+	s.NoError(err)
+	s.Equal("1", projectConfig.GetRevision())
+	s.Equal(ConfigSourceFile, configManager.ConfigSource())
+}
+
+func (s *FileProjectConfigManagerTestSuite) TestReloadOnChange() {
+	s.writeDatafile(`{"revision":"1","version": "4"}`)
+	configManager := NewFileProjectConfigManager("file_sdk_key", s.path)
+	select {
+	case <-configManager.Ready():
+	default:
+		s.Fail("the manager should be ready")
+	}
+
+	var revisions, oldRevisions []string
+	id, err := configManager.OnProjectConfigUpdate(func(n notification.ProjectConfigUpdateNotification) {
+		revisions = append(revisions, n.Revision)
+		oldRevisions = append(oldRevisions, n.OldRevision)
+	})
+	s.NoError(err)
+	defer func() {
+		s.NoError(configManager.RemoveOnProjectConfigUpdate(id))
+	}()
+
+	// an unchanged file is not parsed again
+	configManager.SyncConfig()
+	s.Empty(revisions)
+
+	s.writeDatafile(`{"revision":"2","version": "4"}`)
+	configManager.SyncConfig()
+	s.Equal([]string{"2"}, revisions)
+	s.Equal([]string{"1"}, oldRevisions)
+	s.Equal("2", configManager.GetOptimizelyConfig().Revision)
+
+	// the previous config is kept when the new file fails to parse
+	s.writeDatafile(`{"revision":"3",`)
+	configManager.SyncConfig()
+	s.Equal([]string{"2"}, revisions)
+	projectConfig, err := configManager.GetConfig()
+	s.NoError(err)
+	s.Equal("2", projectConfig.GetRevision())
+
+	s.writeDatafile(`{"revision":"3","version": "4"}`)
+	configManager.SyncConfig()
+	s.Equal([]string{"2", "3"}, revisions)
+	s.Equal("3", configManager.GetOptimizelyConfig().Revision)
+
+	// a rewritten file with the same revision is not an update
+	s.writeDatafile(`{"revision":"3","version": "4","botFiltering": true}`)
+	configManager.SyncConfig()
+	s.Equal([]string{"2", "3"}, revisions)
+	projectConfig, err = configManager.GetConfig()
+	s.NoError(err)
+	s.False(projectConfig.GetBotFiltering())
+}
+
+func (s *FileProjectConfigManagerTestSuite) TestStart() {
+	s.writeDatafile(`{"revision":"1","version": "4"}`)
+	configManager := NewFileProjectConfigManager("file_sdk_key", s.path, WithWatchInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		configManager.Start(ctx)
+	}()
+
+	s.writeDatafile(`{"revision":"2","version": "4"}`)
+	s.Eventually(func() bool {
+		projectConfig, err := configManager.GetConfig()
+		return err == nil && projectConfig.GetRevision() == "2"
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
+
+func (s *FileProjectConfigManagerTestSuite) TestStrictValidation() {
+	s.writeDatafile(`{"revision":"1","version": "4"}`)
+	configManager := NewFileProjectConfigManager("file_sdk_key", s.path, WithFileStrictValidation(true))
+
+	s.writeDatafile(`{"revision":"2","version": "4","experiments":[{"id":"e1","key":"exp_1","trafficAllocation":[{"entityId":"","endOfRange":20000}]}]}`)
+	configManager.SyncConfig()
+	projectConfig, err := configManager.GetConfig()
+	s.NoError(err)
+	s.Equal("1", projectConfig.GetRevision())
+	s.IsType(datafileprojectconfig.ValidationError{}, configManager.err)
+
+	s.writeDatafile(`{"revision":"3","version": "4"}`)
+	configManager.SyncConfig()
+	projectConfig, err = configManager.GetConfig()
+	s.NoError(err)
+	s.Equal("3", projectConfig.GetRevision())
+	s.NoError(configManager.err)
+}
+
+func (s *FileProjectConfigManagerTestSuite) TestSignatureVerification() {
+	key := []byte("secret")
+	datafile := `{"revision":"1","version": "4"}`
+	s.writeDatafile(datafile)
+	s.writeSignature(key, datafile)
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewFileProjectConfigManager("file_signature_sdk_key", s.path,
+		WithFileSignatureVerifier(NewHMACSHA256Verifier(key)), WithFileMetricsRegistry(metricsRegistry))
+	projectConfig, err := configManager.GetConfig()
+	s.Require().NoError(err)
+	s.Equal("1", projectConfig.GetRevision())
+
+	var rejections []notification.ProjectConfigRejectedNotification
+	id, err := configManager.OnProjectConfigRejected(func(n notification.ProjectConfigRejectedNotification) {
+		rejections = append(rejections, n)
+	})
+	s.NoError(err)
+	defer func() {
+		s.NoError(configManager.RemoveOnProjectConfigRejected(id))
+	}()
+
+	// a datafile which does not match its signature is rejected and the current config is kept
+	s.writeDatafile(`{"revision":"2","version": "4"}`)
+	configManager.SyncConfig()
+	projectConfig, err = configManager.GetConfig()
+	s.NoError(err)
+	s.Equal("1", projectConfig.GetRevision())
+	s.Equal([]notification.ProjectConfigRejectedNotification{
+		{Type: notification.ProjectConfigRejected, Revision: "1", Reason: ErrInvalidSignature},
+	}, rejections)
+	s.Equal(1.0, metricsRegistry.counters[metrics.ConfigManagerRejectedDatafile].value)
+
+	// the datafile is read again once its signature is written
+	s.writeSignature(key, `{"revision":"2","version": "4"}`)
+	configManager.SyncConfig()
+	projectConfig, err = configManager.GetConfig()
+	s.NoError(err)
+	s.Equal("2", projectConfig.GetRevision())
+
+	s.Require().NoError(os.Remove(s.path + DefaultSignatureSidecar))
+	s.writeDatafile(`{"revision":"3","version": "4"}`)
+	configManager.SyncConfig()
+	s.Equal(ErrMissingSignature, configManager.err)
+	s.Len(rejections, 2)
+}
+
+func TestFileProjectConfigManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(FileProjectConfigManagerTestSuite))
+}
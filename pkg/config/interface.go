@@ -29,10 +29,12 @@ type ProjectConfig interface {
 	GetAnonymizeIP() bool
 	GetAttributeID(id string) string // returns "" if there is no id
 	GetAttributeByKey(key string) (entities.Attribute, error)
+	GetAttributeList() []entities.Attribute
 	GetAudienceByID(string) (entities.Audience, error)
 	GetAudienceMap() map[string]entities.Audience
 	GetBotFiltering() bool
 	GetEventByKey(string) (entities.Event, error)
+	GetEventList() []entities.Event
 	GetExperimentByKey(string) (entities.Experiment, error)
 	GetFeatureByKey(string) (entities.Feature, error)
 	GetVariableByKey(featureKey string, variableKey string) (entities.Variable, error)
@@ -41,6 +43,11 @@ type ProjectConfig interface {
 	GetGroupByID(string) (entities.Group, error)
 	GetProjectID() string
 	GetRevision() string
+	GetSDKKey() string
+	GetEnvironmentKey() string
+	GetSendFlagDecisions() bool
+	GetIntegrationList() []entities.Integration
+	GetHoldoutList() []entities.Holdout
 }
 
 // ProjectConfigManager maintains an instance of the ProjectConfig
@@ -50,3 +57,13 @@ type ProjectConfigManager interface {
 	RemoveOnProjectConfigUpdate(id int) error
 	OnProjectConfigUpdate(callback func(notification.ProjectConfigUpdateNotification)) (int, error)
 }
+
+// ReadinessNotifier is implemented by config managers which can signal when the first project config is available
+type ReadinessNotifier interface {
+	Ready() <-chan struct{}
+}
+
+// SourceReporter is implemented by config managers which can tell where their current project config comes from
+type SourceReporter interface {
+	ConfigSource() ConfigSource
+}
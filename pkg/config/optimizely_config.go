@@ -18,15 +18,26 @@
 package config
 
 import (
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/optimizely/go-sdk/pkg/entities"
 )
 
 // OptimizelyConfig is a snapshot of the experiments and features in the project config
 type OptimizelyConfig struct {
-	Revision       string                          `json:"revision"`
-	ExperimentsMap map[string]OptimizelyExperiment `json:"experimentsMap"`
-	FeaturesMap    map[string]OptimizelyFeature    `json:"featuresMap"`
-	datafile       string
+	Revision          string                          `json:"revision"`
+	SDKKey            string                          `json:"sdkKey"`
+	EnvironmentKey    string                          `json:"environmentKey"`
+	SendFlagDecisions bool                            `json:"sendFlagDecisions"`
+	Integrations      []OptimizelyIntegration         `json:"integrations"`
+	Attributes        []OptimizelyAttribute           `json:"attributes"`
+	Audiences         []OptimizelyAudience            `json:"audiences"`
+	Events            []OptimizelyEvent               `json:"events"`
+	ExperimentsMap    map[string]OptimizelyExperiment `json:"experimentsMap"`
+	FeaturesMap       map[string]OptimizelyFeature    `json:"featuresMap"`
+	datafile          string
 }
 
 // GetDatafile returns a string representation of the environment's datafile
@@ -34,19 +45,51 @@ func (c OptimizelyConfig) GetDatafile() string {
 	return c.datafile
 }
 
-// OptimizelyExperiment has experiment info
+// OptimizelyIntegration has integration info
+type OptimizelyIntegration struct {
+	Key       string `json:"key"`
+	Host      string `json:"host"`
+	PublicKey string `json:"publicKey"`
+}
+
+// OptimizelyAttribute has attribute info
+type OptimizelyAttribute struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// OptimizelyAudience has audience info, Conditions is a JSON string
+type OptimizelyAudience struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Conditions string `json:"conditions"`
+}
+
+// OptimizelyEvent has event info
+type OptimizelyEvent struct {
+	ID            string   `json:"id"`
+	Key           string   `json:"key"`
+	ExperimentIds []string `json:"experimentIds"`
+}
+
+// OptimizelyExperiment has experiment info, Audiences is a readable expression of the audiences of the experiment such
+// as "us" AND ("beta" OR "staff")
 type OptimizelyExperiment struct {
 	ID            string                         `json:"id"`
 	Key           string                         `json:"key"`
+	Audiences     string                         `json:"audiences"`
 	VariationsMap map[string]OptimizelyVariation `json:"variationsMap"`
 }
 
-// OptimizelyFeature has feature info
+// OptimizelyFeature has feature info, ExperimentRules and DeliveryRules list the experiments and the rollout rules of
+// the feature in the order they are evaluated
 type OptimizelyFeature struct {
-	ID             string                          `json:"id"`
-	Key            string                          `json:"key"`
-	ExperimentsMap map[string]OptimizelyExperiment `json:"experimentsMap"`
-	VariablesMap   map[string]OptimizelyVariable   `json:"variablesMap"`
+	ID              string                          `json:"id"`
+	Key             string                          `json:"key"`
+	ExperimentRules []OptimizelyExperiment          `json:"experimentRules"`
+	DeliveryRules   []OptimizelyExperiment          `json:"deliveryRules"`
+	ExperimentsMap  map[string]OptimizelyExperiment `json:"experimentsMap"`
+	VariablesMap    map[string]OptimizelyVariable   `json:"variablesMap"`
 }
 
 // OptimizelyVariation has variation info
@@ -91,41 +134,95 @@ func getExperimentVariablesMap(features []entities.Feature) (experimentVariableM
 	return experimentVariableMap
 }
 
-func getExperimentMap(features []entities.Feature, experiments []entities.Experiment, variableByIDMap map[string]entities.Variable) (optlyExperimentMap map[string]OptimizelyExperiment) {
+func getExperimentMap(features []entities.Feature, experiments []entities.Experiment, variableByIDMap map[string]entities.Variable,
+	audienceMap map[string]entities.Audience) (optlyExperimentMap map[string]OptimizelyExperiment) {
 
 	optlyExperimentMap = map[string]OptimizelyExperiment{}
 	experimentVariablesMap := getExperimentVariablesMap(features)
 
 	for _, experiment := range experiments {
-		var optlyVariationsMap = map[string]OptimizelyVariation{}
-		for _, variation := range experiment.Variations {
-			var optlyVariablesMap = map[string]OptimizelyVariable{}
+		optlyExperimentMap[experiment.Key] = getOptimizelyExperiment(experiment, experimentVariablesMap[experiment.Key], variableByIDMap, audienceMap)
+	}
+	return optlyExperimentMap
+}
 
-			if variableMap, ok := experimentVariablesMap[experiment.Key]; ok {
-				for index, element := range variableMap { // copy by value
-					optlyVariablesMap[index] = element
-				}
-			}
+// getOptimizelyExperiment returns the experiment or rollout rule info, the variables of its variations default to the
+// given feature variables
+func getOptimizelyExperiment(experiment entities.Experiment, featureVariablesMap map[string]OptimizelyVariable,
+	variableByIDMap map[string]entities.Variable, audienceMap map[string]entities.Audience) OptimizelyExperiment {
+
+	var optlyVariationsMap = map[string]OptimizelyVariation{}
+	for _, variation := range experiment.Variations {
+		var optlyVariablesMap = map[string]OptimizelyVariable{}
+
+		for index, element := range featureVariablesMap { // copy by value
+			optlyVariablesMap[index] = element
+		}
 
-			for _, variable := range variation.Variables {
-				if experiment.IsFeatureExperiment && variation.FeatureEnabled {
-					if convertedVariable, ok := variableByIDMap[variable.ID]; ok {
-						optlyVariable := OptimizelyVariable{Key: convertedVariable.Key, ID: convertedVariable.ID,
-							Type: string(convertedVariable.Type), Value: variable.Value}
-						optlyVariablesMap[convertedVariable.Key] = optlyVariable
-					}
+		for _, variable := range variation.Variables {
+			if (experiment.IsFeatureExperiment || experiment.IsRolloutRule()) && variation.FeatureEnabled {
+				if convertedVariable, ok := variableByIDMap[variable.ID]; ok {
+					optlyVariable := OptimizelyVariable{Key: convertedVariable.Key, ID: convertedVariable.ID,
+						Type: string(convertedVariable.Type), Value: variable.Value}
+					optlyVariablesMap[convertedVariable.Key] = optlyVariable
 				}
 			}
-			optVariation := OptimizelyVariation{ID: variation.ID, Key: variation.Key, VariablesMap: optlyVariablesMap, FeatureEnabled: variation.FeatureEnabled}
-			optlyVariationsMap[variation.Key] = optVariation
 		}
-		optlyExperiment := OptimizelyExperiment{ID: experiment.ID, Key: experiment.Key, VariationsMap: optlyVariationsMap}
-		optlyExperimentMap[experiment.Key] = optlyExperiment
+		optVariation := OptimizelyVariation{ID: variation.ID, Key: variation.Key, VariablesMap: optlyVariablesMap, FeatureEnabled: variation.FeatureEnabled}
+		optlyVariationsMap[variation.Key] = optVariation
+	}
+	return OptimizelyExperiment{ID: experiment.ID, Key: experiment.Key, VariationsMap: optlyVariationsMap,
+		Audiences: getAudienceExpression(experiment.AudienceConditionTree, audienceMap)}
+}
+
+// getAudienceExpression returns a readable expression of an audience condition tree, such as "us" AND ("beta" OR "staff"),
+// the audiences are named after their ID when they are not found
+func getAudienceExpression(conditionTree *entities.TreeNode, audienceMap map[string]entities.Audience) string {
+	expression, _ := audienceExpression(conditionTree, audienceMap)
+	return expression
+}
+
+// audienceExpression returns the expression of the node and whether it combines several operands, in which case it
+// is wrapped in parentheses when nested
+func audienceExpression(node *entities.TreeNode, audienceMap map[string]entities.Audience) (expression string, compound bool) {
+	if node == nil {
+		return "", false
+	}
+	if audienceID, ok := node.Item.(string); ok {
+		name := audienceID
+		if audience, ok := audienceMap[audienceID]; ok && audience.Name != "" {
+			name = audience.Name
+		}
+		return strconv.Quote(name), false
+	}
+
+	var operands []string
+	for _, child := range node.Nodes {
+		operand, childCompound := audienceExpression(child, audienceMap)
+		if operand == "" {
+			continue
+		}
+		if childCompound {
+			operand = "(" + operand + ")"
+		}
+		operands = append(operands, operand)
+	}
+	switch {
+	case len(operands) == 0:
+		return "", false
+	case node.Operator == "not":
+		return "NOT " + operands[0], false
+	case len(operands) == 1:
+		return operands[0], false
+	case node.Operator == "and":
+		return strings.Join(operands, " AND "), true
+	default:
+		return strings.Join(operands, " OR "), true
 	}
-	return optlyExperimentMap
 }
 
-func getFeatureMap(features []entities.Feature, experimentsMap map[string]OptimizelyExperiment) (optlyFeatureMap map[string]OptimizelyFeature) {
+func getFeatureMap(features []entities.Feature, experimentsMap map[string]OptimizelyExperiment, variableByIDMap map[string]entities.Variable,
+	audienceMap map[string]entities.Audience) (optlyFeatureMap map[string]OptimizelyFeature) {
 
 	optlyFeatureMap = map[string]OptimizelyFeature{}
 
@@ -139,17 +236,52 @@ func getFeatureMap(features []entities.Feature, experimentsMap map[string]Optimi
 		}
 
 		var optlyExperimentMap = map[string]OptimizelyExperiment{}
+		experimentRules := make([]OptimizelyExperiment, 0, len(feature.FeatureExperiments))
 		for _, experiment := range feature.FeatureExperiments {
 			optlyExperimentMap[experiment.Key] = experimentsMap[experiment.Key]
+			experimentRules = append(experimentRules, experimentsMap[experiment.Key])
+		}
+
+		deliveryRules := make([]OptimizelyExperiment, 0, len(feature.Rollout.Experiments))
+		for _, rule := range feature.Rollout.Experiments {
+			deliveryRules = append(deliveryRules, getOptimizelyExperiment(rule, optlyFeatureVariablesMap, variableByIDMap, audienceMap))
 		}
 
-		optlyFeature := OptimizelyFeature{ID: feature.ID, Key: feature.Key, ExperimentsMap: optlyExperimentMap, VariablesMap: optlyFeatureVariablesMap}
+		optlyFeature := OptimizelyFeature{ID: feature.ID, Key: feature.Key, ExperimentsMap: optlyExperimentMap, VariablesMap: optlyFeatureVariablesMap,
+			ExperimentRules: experimentRules, DeliveryRules: deliveryRules}
 		optlyFeatureMap[feature.Key] = optlyFeature
 
 	}
 	return optlyFeatureMap
 }
 
+func getAttributes(attributes []entities.Attribute) []OptimizelyAttribute {
+	optlyAttributes := make([]OptimizelyAttribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		optlyAttributes = append(optlyAttributes, OptimizelyAttribute{ID: attribute.ID, Key: attribute.Key})
+	}
+	sort.Slice(optlyAttributes, func(i, j int) bool { return optlyAttributes[i].ID < optlyAttributes[j].ID })
+	return optlyAttributes
+}
+
+func getAudiences(audienceMap map[string]entities.Audience) []OptimizelyAudience {
+	optlyAudiences := make([]OptimizelyAudience, 0, len(audienceMap))
+	for _, audience := range audienceMap {
+		optlyAudiences = append(optlyAudiences, OptimizelyAudience{ID: audience.ID, Name: audience.Name, Conditions: audience.Conditions})
+	}
+	sort.Slice(optlyAudiences, func(i, j int) bool { return optlyAudiences[i].ID < optlyAudiences[j].ID })
+	return optlyAudiences
+}
+
+func getEvents(events []entities.Event) []OptimizelyEvent {
+	optlyEvents := make([]OptimizelyEvent, 0, len(events))
+	for _, event := range events {
+		optlyEvents = append(optlyEvents, OptimizelyEvent{ID: event.ID, Key: event.Key, ExperimentIds: event.ExperimentIds})
+	}
+	sort.Slice(optlyEvents, func(i, j int) bool { return optlyEvents[i].ID < optlyEvents[j].ID })
+	return optlyEvents
+}
+
 // NewOptimizelyConfig constructs OptimizelyConfig object
 func NewOptimizelyConfig(projConfig ProjectConfig) *OptimizelyConfig {
 
@@ -163,10 +295,21 @@ func NewOptimizelyConfig(projConfig ProjectConfig) *OptimizelyConfig {
 	optimizelyConfig := &OptimizelyConfig{}
 
 	variableByIDMap := getVariableByIDMap(featuresList)
+	audienceMap := projConfig.GetAudienceMap()
 
-	optimizelyConfig.ExperimentsMap = getExperimentMap(featuresList, experimentsList, variableByIDMap)
-	optimizelyConfig.FeaturesMap = getFeatureMap(featuresList, optimizelyConfig.ExperimentsMap)
+	optimizelyConfig.ExperimentsMap = getExperimentMap(featuresList, experimentsList, variableByIDMap, audienceMap)
+	optimizelyConfig.FeaturesMap = getFeatureMap(featuresList, optimizelyConfig.ExperimentsMap, variableByIDMap, audienceMap)
+	optimizelyConfig.Attributes = getAttributes(projConfig.GetAttributeList())
+	optimizelyConfig.Audiences = getAudiences(audienceMap)
+	optimizelyConfig.Events = getEvents(projConfig.GetEventList())
 	optimizelyConfig.Revision = revision
+	optimizelyConfig.SDKKey = projConfig.GetSDKKey()
+	optimizelyConfig.EnvironmentKey = projConfig.GetEnvironmentKey()
+	optimizelyConfig.SendFlagDecisions = projConfig.GetSendFlagDecisions()
+	for _, integration := range projConfig.GetIntegrationList() {
+		optimizelyConfig.Integrations = append(optimizelyConfig.Integrations,
+			OptimizelyIntegration{Key: integration.Key, Host: integration.Host, PublicKey: integration.PublicKey})
+	}
 	optimizelyConfig.datafile = projConfig.GetDatafile()
 
 	return optimizelyConfig
@@ -21,6 +21,8 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/optimizely/go-sdk/pkg/entities"
+
 	"github.com/stretchr/testify/suite"
 )
 
@@ -82,6 +84,76 @@ func (s *OptimizelyConfigTestSuite) TestOptlyConfigGetDatafile() {
 	s.Equal(string(datafile), optimizelyConfig.GetDatafile())
 }
 
+func (s *OptimizelyConfigTestSuite) TestOptlyConfigRulesAndAudiences() {
+	datafile := []byte(`{
+		"version": "4",
+		"revision": "1",
+		"attributes": [{"id": "at2", "key": "country"}, {"id": "at1", "key": "browser"}],
+		"events": [{"id": "ev1", "key": "purchase", "experimentIds": ["e1"]}],
+		"audiences": [
+			{"id": "a_us", "name": "us", "conditions": "[\"or\"]"},
+			{"id": "a_beta", "name": "beta", "conditions": "[\"or\"]"},
+			{"id": "a_staff", "name": "staff", "conditions": "[\"or\"]"}
+		],
+		"experiments": [
+			{"id": "e1", "key": "exp_1", "layerId": "l1", "status": "Running",
+			 "audienceIds": ["a_us", "a_beta", "a_staff"], "audienceConditions": ["and", "a_us", ["or", "a_beta", "a_staff"]],
+			 "variations": [{"id": "v1", "key": "on", "featureEnabled": true, "variables": [{"id": "var1", "value": "blue"}]}],
+			 "trafficAllocation": [{"entityId": "v1", "endOfRange": 10000}]}
+		],
+		"rollouts": [
+			{"id": "r1", "experiments": [
+				{"id": "rule1", "key": "rule_1", "layerId": "r1", "status": "Running", "audienceIds": ["a_staff"],
+				 "variations": [{"id": "rv1", "key": "rollout_on", "featureEnabled": true, "variables": [{"id": "var1", "value": "green"}]}],
+				 "trafficAllocation": [{"entityId": "rv1", "endOfRange": 10000}]},
+				{"id": "rule2", "key": "everyone_else", "layerId": "r1", "status": "Running", "audienceIds": [],
+				 "variations": [{"id": "rv2", "key": "rollout_off", "featureEnabled": false}],
+				 "trafficAllocation": [{"entityId": "rv2", "endOfRange": 10000}]}
+			]}
+		],
+		"featureFlags": [
+			{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": ["e1"],
+			 "variables": [{"id": "var1", "key": "color", "type": "string", "defaultValue": "red"}]}
+		]
+	}`)
+	projectMgr := NewStaticProjectConfigManagerWithOptions("", WithInitialDatafile(datafile))
+	optimizelyConfig := NewOptimizelyConfig(projectMgr.projectConfig)
+
+	s.Equal([]OptimizelyAttribute{{ID: "at1", Key: "browser"}, {ID: "at2", Key: "country"}}, optimizelyConfig.Attributes)
+	s.Equal([]OptimizelyEvent{{ID: "ev1", Key: "purchase", ExperimentIds: []string{"e1"}}}, optimizelyConfig.Events)
+	s.Equal([]OptimizelyAudience{
+		{ID: "a_beta", Name: "beta", Conditions: `["or"]`},
+		{ID: "a_staff", Name: "staff", Conditions: `["or"]`},
+		{ID: "a_us", Name: "us", Conditions: `["or"]`},
+	}, optimizelyConfig.Audiences)
+
+	s.Equal(`"us" AND ("beta" OR "staff")`, optimizelyConfig.ExperimentsMap["exp_1"].Audiences)
+
+	feature := optimizelyConfig.FeaturesMap["flag_1"]
+	s.Equal([]OptimizelyExperiment{optimizelyConfig.ExperimentsMap["exp_1"]}, feature.ExperimentRules)
+	s.Require().Len(feature.DeliveryRules, 2)
+	s.Equal("rule_1", feature.DeliveryRules[0].Key)
+	s.Equal(`"staff"`, feature.DeliveryRules[0].Audiences)
+	s.Equal("green", feature.DeliveryRules[0].VariationsMap["rollout_on"].VariablesMap["color"].Value)
+	s.Equal("everyone_else", feature.DeliveryRules[1].Key)
+	s.Equal("", feature.DeliveryRules[1].Audiences)
+	s.Equal("red", feature.DeliveryRules[1].VariationsMap["rollout_off"].VariablesMap["color"].Value)
+}
+
+func (s *OptimizelyConfigTestSuite) TestGetAudienceExpression() {
+	audienceMap := map[string]entities.Audience{"1": {ID: "1", Name: "us"}, "2": {ID: "2", Name: "beta"}}
+	leaf := func(id string) *entities.TreeNode { return &entities.TreeNode{Item: id} }
+
+	s.Equal("", getAudienceExpression(nil, audienceMap))
+	s.Equal(`"us"`, getAudienceExpression(&entities.TreeNode{Operator: "or", Nodes: []*entities.TreeNode{leaf("1")}}, audienceMap))
+	s.Equal(`"us" OR "beta" OR "3"`, getAudienceExpression(&entities.TreeNode{Operator: "or",
+		Nodes: []*entities.TreeNode{leaf("1"), leaf("2"), leaf("3")}}, audienceMap))
+	s.Equal(`NOT ("us" AND "beta")`, getAudienceExpression(&entities.TreeNode{Operator: "not", Nodes: []*entities.TreeNode{
+		{Operator: "and", Nodes: []*entities.TreeNode{leaf("1"), leaf("2")}}}}, audienceMap))
+	s.Equal(`"us" AND NOT "beta"`, getAudienceExpression(&entities.TreeNode{Operator: "and", Nodes: []*entities.TreeNode{
+		leaf("1"), {Operator: "not", Nodes: []*entities.TreeNode{leaf("2")}}}}, audienceMap))
+}
+
 func TestOptimizelyConfigTestSuite(t *testing.T) {
 	suite.Run(t, new(OptimizelyConfigTestSuite))
 }
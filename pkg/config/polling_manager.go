@@ -20,12 +20,14 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"math/rand"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig"
 	"github.com/optimizely/go-sdk/pkg/logging"
+	"github.com/optimizely/go-sdk/pkg/metrics"
 	"github.com/optimizely/go-sdk/pkg/notification"
 	"github.com/optimizely/go-sdk/pkg/registry"
 	"github.com/optimizely/go-sdk/pkg/utils"
@@ -36,12 +38,21 @@ import (
 // DefaultPollingInterval sets default interval for polling manager
 const DefaultPollingInterval = 5 * time.Minute // default to 5 minutes for polling
 
+// DefaultMaxBackoff sets the default maximum delay between attempts after failed datafile fetches
+const DefaultMaxBackoff = 30 * time.Minute
+
 // ModifiedSince header key for request
 const ModifiedSince = "If-Modified-Since"
 
 // LastModified header key for response
 const LastModified = "Last-Modified"
 
+// IfNoneMatch header key for request
+const IfNoneMatch = "If-None-Match"
+
+// ETag header key for response
+const ETag = "ETag"
+
 // DatafileURLTemplate is used to construct the endpoint for retrieving regular datafile from the CDN
 const DatafileURLTemplate = "https://cdn.optimizely.com/datafiles/%s.json"
 
@@ -56,18 +67,50 @@ var Err403Forbidden = errors.New("unable to fetch fresh datafile (consider reche
 type PollingProjectConfigManager struct {
 	datafileURLTemplate string
 	initDatafile        []byte
+	initSignature       string
 	lastModified        string
+	etag                string
+	datafileSize        int
 	notificationCenter  notification.Center
 	pollingInterval     time.Duration
 	requester           utils.Requester
+	datafileSource      DatafileSource
 	sdkKey              string
 	logger              logging.OptimizelyLogProducer
 	datafileAccessToken string
 
+	fetchCounter            metrics.Counter
+	notModifiedCounter      metrics.Counter
+	failedFetchCounter      metrics.Counter
+	rejectedCounter         metrics.Counter
+	rejectedRevisionCounter metrics.Counter
+	stalenessGauge          metrics.Gauge
+	fetchLatencyGauge       metrics.Gauge
+	bytesSavedCounter       metrics.Counter
+
+	signatureVerifier SignatureVerifier
+	signatureHeader   string
+	signatureSuffix   string
+	revisionPolicy    *MonotonicRevisionPolicy
+	strictValidation  bool
+
+	maxBackoff         time.Duration
+	startupJitter      time.Duration
+	stalenessThreshold time.Duration
+	lastRefresh        time.Time
+	cacheDir           string
+	configSource       ConfigSource
+	staleNotified      bool
+	now                func() time.Time
+	jitter             func(max time.Duration) time.Duration
+
 	configLock       sync.RWMutex
 	err              error
 	projectConfig    ProjectConfig
 	optimizelyConfig *OptimizelyConfig
+
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
 // OptionFunc is used to provide custom configuration to the PollingProjectConfigManager.
@@ -101,6 +144,58 @@ func WithInitialDatafile(datafile []byte) OptionFunc {
 	}
 }
 
+// WithInitialDatafileSignature is an optional function, sets the base64 encoded signature of the initial datafile which
+// is verified when a signature verifier is set
+func WithInitialDatafileSignature(signature string) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.initSignature = signature
+	}
+}
+
+// WithMetricsRegistry is an optional function, sets the registry of the datafile fetch metrics
+func WithMetricsRegistry(metricsRegistry metrics.Registry) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.fetchCounter = metricsRegistry.GetCounter(metrics.ConfigManagerFetch)
+		p.notModifiedCounter = metricsRegistry.GetCounter(metrics.ConfigManagerNotModified)
+		p.failedFetchCounter = metricsRegistry.GetCounter(metrics.ConfigManagerFailedFetch)
+		p.rejectedCounter = metricsRegistry.GetCounter(metrics.ConfigManagerRejectedDatafile)
+		p.rejectedRevisionCounter = metricsRegistry.GetCounter(metrics.ConfigManagerRejectedRevision)
+		p.stalenessGauge = metricsRegistry.GetGauge(metrics.ConfigManagerStaleness)
+		p.fetchLatencyGauge = metricsRegistry.GetGauge(metrics.ConfigManagerFetchLatency)
+		p.bytesSavedCounter = metricsRegistry.GetCounter(metrics.ConfigManagerBytesSaved)
+	}
+}
+
+// WithMaxBackoff is an optional function, sets the maximum delay between attempts after failed fetches
+func WithMaxBackoff(maxBackoff time.Duration) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.maxBackoff = maxBackoff
+	}
+}
+
+// WithStartupJitter is an optional function, delays the first poll by a random duration up to the passed jitter
+func WithStartupJitter(jitter time.Duration) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.startupJitter = jitter
+	}
+}
+
+// WithStalenessThreshold is an optional function, sets how long the project config can go without a refresh before
+// a ProjectConfigStale notification is sent
+func WithStalenessThreshold(threshold time.Duration) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.stalenessThreshold = threshold
+	}
+}
+
+// WithDatafileCacheDir is an optional function, sets the directory in which the last valid datafile is cached. The
+// cached datafile is used as initial datafile when none is passed.
+func WithDatafileCacheDir(dir string) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.cacheDir = dir
+	}
+}
+
 // WithDatafileAccessToken is an optional function, sets a passed datafile access token
 func WithDatafileAccessToken(datafileAccessToken string) OptionFunc {
 	return func(p *PollingProjectConfigManager) {
@@ -108,88 +203,357 @@ func WithDatafileAccessToken(datafileAccessToken string) OptionFunc {
 	}
 }
 
+// WithSignatureVerifier is an optional function, sets the verifier of the fetched datafiles. Datafiles without a valid
+// signature are rejected, by default the signature is read from the DatafileSignatureHeader response header.
+func WithSignatureVerifier(verifier SignatureVerifier) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.signatureVerifier = verifier
+	}
+}
+
+// WithSignatureHeader is an optional function, sets the response header holding the base64 encoded datafile signature
+func WithSignatureHeader(header string) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.signatureHeader = header
+	}
+}
+
+// WithSignatureSidecar is an optional function, reads the base64 encoded datafile signature from a sidecar file at the
+// location of the datafile followed by the given suffix, such as ".sig", instead of a response header
+func WithSignatureSidecar(suffix string) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.signatureSuffix = suffix
+	}
+}
+
+// WithRevisionPolicy is an optional function, sets the policy rejecting the datafiles with an older revision than the
+// current one
+func WithRevisionPolicy(policy *MonotonicRevisionPolicy) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.revisionPolicy = policy
+	}
+}
+
+// WithStrictValidation is an optional function, when strict the datafiles are validated and the ones with validation
+// errors are rejected, see datafileprojectconfig.Validate
+func WithStrictValidation(strict bool) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.strictValidation = strict
+	}
+}
+
+// WithDatafileSource is an optional function, sets the source of the datafiles. The datafiles are fetched from the
+// datafile URL template with the requester when no source is passed.
+func WithDatafileSource(source DatafileSource) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.datafileSource = source
+	}
+}
+
+// getDatafileSource returns the datafile source of the manager
+func (cm *PollingProjectConfigManager) getDatafileSource() DatafileSource {
+	if cm.datafileSource != nil {
+		return cm.datafileSource
+	}
+	return NewHTTPDatafileSource(cm.requester, cm.datafileURLTemplate)
+}
+
 // SyncConfig downloads datafile and updates projectConfig
 func (cm *PollingProjectConfigManager) SyncConfig() {
-	var e error
-	var code int
-	var respHeaders http.Header
-	var datafile []byte
+	_ = cm.syncConfig()
+}
 
-	closeMutex := func(e error) {
+// syncConfig downloads the datafile and updates projectConfig, it returns an error when the datafile could not be
+// fetched or parsed
+func (cm *PollingProjectConfigManager) syncConfig() error {
+	closeMutex := func(e error) error {
 		cm.err = e
+		if e == nil {
+			cm.setRefreshed()
+		}
 		cm.configLock.Unlock()
+		return e
 	}
 
-	url := fmt.Sprintf(cm.datafileURLTemplate, cm.sdkKey)
-	if cm.lastModified != "" {
-		lastModifiedHeader := utils.Header{Name: ModifiedSince, Value: cm.lastModified}
-		datafile, respHeaders, code, e = cm.requester.Get(url, lastModifiedHeader)
-	} else {
-		datafile, respHeaders, code, e = cm.requester.Get(url)
-	}
+	cm.configLock.RLock()
+	request := DatafileRequest{SDKKey: cm.sdkKey, LastModified: cm.lastModified, ETag: cm.etag}
+	datafileSize := cm.datafileSize
+	cm.configLock.RUnlock()
+	start := cm.now()
+	response, e := cm.getDatafileSource().Fetch(request)
+	cm.fetchLatencyGauge.Set(cm.now().Sub(start).Seconds())
 
 	if e != nil {
 		msg := "unable to fetch fresh datafile"
 		cm.logger.Warning(msg)
+		cm.failedFetchCounter.Add(1)
 		cm.configLock.Lock()
 
-		if code == http.StatusForbidden {
-			closeMutex(Err403Forbidden)
-			return
+		if isForbidden(e) {
+			return closeMutex(Err403Forbidden)
 		}
 
-		closeMutex(errors.New(fmt.Sprintf("%s, reason (http status code): %s", msg, e.Error())))
-		return
+		return closeMutex(errors.New(fmt.Sprintf("%s: %s", msg, e.Error())))
 	}
 
-	if code == http.StatusNotModified {
+	if response.NotModified {
 		cm.logger.Debug("The datafile was not modified and won't be downloaded again")
-		return
+		cm.notModifiedCounter.Add(1)
+		cm.bytesSavedCounter.Add(float64(datafileSize))
+		cm.configLock.Lock()
+		cm.setRefreshed()
+		cm.configLock.Unlock()
+		return nil
+	}
+	cm.fetchCounter.Add(1)
+	cm.logger.Debug(fmt.Sprintf("Datafile served by %s", response.Source))
+
+	signature, err := cm.verifySignature(request, response)
+	if err != nil {
+		return cm.rejectDatafile(err)
+	}
+
+	if err = cm.applyDatafile(response.Datafile, signature); err != nil {
+		return err
+	}
+
+	// Save last-modified date and entity tag of the datafile once it is accepted, so that a rejected datafile is
+	// downloaded again on the next poll instead of being reported as not modified
+	cm.configLock.Lock()
+	if response.LastModified != "" {
+		cm.lastModified = response.LastModified
+	}
+	if response.ETag != "" {
+		cm.etag = response.ETag
+	}
+	cm.datafileSize = len(response.Datafile)
+	cm.configLock.Unlock()
+	return nil
+}
+
+// verifySignature verifies the signature of the datafile when a signature verifier is set, it returns the verified
+// base64 encoded signature
+func (cm *PollingProjectConfigManager) verifySignature(request DatafileRequest, response DatafileResponse) (string, error) {
+	if cm.signatureVerifier == nil {
+		return "", nil
+	}
+
+	var encoded string
+	if cm.signatureSuffix != "" {
+		signatureSource, ok := cm.getDatafileSource().(SignatureSource)
+		if !ok {
+			return "", ErrMissingSignature
+		}
+		sidecar, err := signatureSource.FetchSignature(request, cm.signatureSuffix)
+		if err != nil {
+			return "", fmt.Errorf("unable to fetch the datafile signature: %v", err)
+		}
+		encoded = string(sidecar)
+	} else if response.Header != nil {
+		encoded = response.Header.Get(cm.signatureHeader)
 	}
+	return encoded, verifyDatafile(cm.signatureVerifier, response.Datafile, encoded)
+}
 
-	// Save last-modified date from response header
+// rejectDatafile keeps the current project config and reports the rejection of a datafile
+func (cm *PollingProjectConfigManager) rejectDatafile(reason error) error {
+	cm.rejectedCounter.Add(1)
 	cm.configLock.Lock()
-	lastModified := respHeaders.Get(LastModified)
-	if lastModified != "" {
-		cm.lastModified = lastModified
+	cm.err = reason
+	var revision string
+	if cm.projectConfig != nil {
+		revision = cm.projectConfig.GetRevision()
+	}
+	cm.configLock.Unlock()
+
+	sendConfigRejectedNotification(cm.notificationCenter, cm.logger, revision, reason)
+	return reason
+}
+
+// sendConfigRejectedNotification reports the rejection of a datafile while the project config with the given revision
+// is kept
+func sendConfigRejectedNotification(notificationCenter notification.Center, logger logging.OptimizelyLogProducer, revision string, reason error) {
+	logger.Error(fmt.Sprintf("Datafile rejected, keeping the current project config with revision %q", revision), reason)
+	if notificationCenter != nil {
+		projectConfigRejectedNotification := notification.ProjectConfigRejectedNotification{
+			Type:     notification.ProjectConfigRejected,
+			Revision: revision,
+			Reason:   reason,
+		}
+		if err := notificationCenter.Send(notification.ProjectConfigRejected, projectConfigRejectedNotification); err != nil {
+			logger.Warning("Problem with sending notification")
+		}
+	}
+}
+
+// validateDatafile logs the findings of the validation of the datafile and returns a
+// datafileprojectconfig.ValidationError when there are errors. Datafiles which can't be parsed are left to the parsing.
+func validateDatafile(datafile []byte, logger logging.OptimizelyLogProducer) error {
+	report, err := datafileprojectconfig.ValidateDatafile(datafile)
+	if err != nil {
+		return nil
+	}
+	for _, finding := range report.Findings {
+		logger.Warning(fmt.Sprintf("Datafile validation %s %s at %s: %s", finding.Severity, finding.Code, finding.Path, finding.Message))
+	}
+	if report.HasErrors() {
+		return datafileprojectconfig.ValidationError{Report: report}
+	}
+	return nil
+}
+
+// applyDatafile parses the given remote datafile and sets its project config when its revision differs from the
+// current one, it returns an error when the datafile could not be parsed. The base64 encoded signature of a verified
+// datafile is cached along with it.
+func (cm *PollingProjectConfigManager) applyDatafile(datafile []byte, signature string) error {
+	closeMutex := func(e error) error {
+		cm.err = e
+		if e == nil {
+			cm.setRefreshed()
+		}
+		cm.configLock.Unlock()
+		return e
+	}
+
+	if cm.strictValidation {
+		if err := validateDatafile(datafile, cm.logger); err != nil {
+			return cm.rejectDatafile(err)
+		}
 	}
 
 	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "NewDatafileProjectConfig"))
+	cm.configLock.Lock()
 	if err != nil {
 		cm.logger.Warning("failed to create project config")
-		closeMutex(errors.New("unable to parse datafile"))
-		return
+		return closeMutex(errors.New("unable to parse datafile"))
 	}
 
+	previousConfig := cm.projectConfig
 	var previousRevision string
-	if cm.projectConfig != nil {
-		previousRevision = cm.projectConfig.GetRevision()
+	if previousConfig != nil {
+		previousRevision = previousConfig.GetRevision()
+	}
+	if cm.revisionPolicy != nil && previousRevision != "" {
+		if err = cm.revisionPolicy.Check(cm.sdkKey, previousRevision, projectConfig.GetRevision()); err != nil {
+			cm.configLock.Unlock()
+			cm.logger.Warning(fmt.Sprintf("Datafile with revision %s is older than the current revision %s", projectConfig.GetRevision(), previousRevision))
+			cm.rejectedRevisionCounter.Add(1)
+			return cm.rejectDatafile(err)
+		}
 	}
 	if projectConfig.GetRevision() == previousRevision {
 		cm.logger.Debug(fmt.Sprintf("No datafile updates. Current revision number: %s", cm.projectConfig.GetRevision()))
+		cm.configSource = ConfigSourceRemote
 		closeMutex(nil)
-		return
+		cm.cacheDatafile(datafile, signature)
+		return nil
 	}
 	err = cm.setConfig(projectConfig)
+	if err == nil {
+		cm.configSource = ConfigSourceRemote
+	}
 	closeMutex(err)
 	if err == nil {
 		cm.logger.Debug(fmt.Sprintf("New datafile set with revision: %s. Old revision: %s", projectConfig.GetRevision(), previousRevision))
-		cm.sendConfigUpdateNotification()
+		cm.sendConfigUpdateNotification(previousConfig, projectConfig)
+		cm.cacheDatafile(datafile, signature)
+	}
+	return err
+}
+
+// cacheDatafile writes the given valid datafile to the cache directory, when there is one. With a signature verifier,
+// the datafile is only cached along with its signature so that it can be verified again when it is loaded.
+func (cm *PollingProjectConfigManager) cacheDatafile(datafile []byte, signature string) {
+	if cm.cacheDir == "" {
+		return
+	}
+	if cm.signatureVerifier != nil {
+		if err := writeCachedSignature(cm.cacheDir, cm.sdkKey, signature); err != nil {
+			cm.logger.Warning(fmt.Sprintf("Unable to cache the datafile signature in %s: %v", cm.cacheDir, err))
+			return
+		}
+	}
+	if err := writeCachedDatafile(cm.cacheDir, cm.sdkKey, datafile); err != nil {
+		cm.logger.Warning(fmt.Sprintf("Unable to cache the datafile in %s: %v", cm.cacheDir, err))
+	}
+}
+
+// loadCachedDatafile sets the datafile cached by a previous run as initial datafile, it returns false when there is
+// no valid cached datafile. With a signature verifier, the cached datafile must match its cached signature.
+func (cm *PollingProjectConfigManager) loadCachedDatafile() bool {
+	if cm.cacheDir == "" {
+		return false
+	}
+	datafile, modTime, err := readCachedDatafile(cm.cacheDir, cm.sdkKey)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			cm.logger.Warning(fmt.Sprintf("Unable to read the cached datafile from %s: %v", cm.cacheDir, err))
+		}
+		return false
+	}
+
+	var signature string
+	if cm.signatureVerifier != nil {
+		if signature, err = readCachedSignature(cm.cacheDir, cm.sdkKey); err != nil && !os.IsNotExist(err) {
+			cm.logger.Warning(fmt.Sprintf("Unable to read the cached datafile signature from %s: %v", cm.cacheDir, err))
+		}
+	}
+
+	cm.setInitialDatafile(datafile, signature, ConfigSourceCache)
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	if cm.projectConfig == nil {
+		cm.logger.Warning(fmt.Sprintf("Unable to use the cached datafile from %s: %v", cm.cacheDir, cm.err))
+		return false
 	}
+	// the staleness of a cached datafile starts when it was written
+	cm.lastRefresh = modTime
+	cm.logger.Info(fmt.Sprintf("Loaded cached datafile with revision %s from %s", cm.projectConfig.GetRevision(), cm.cacheDir))
+	return true
 }
 
-// Start starts the polling
+// ConfigSource returns where the current project config comes from
+func (cm *PollingProjectConfigManager) ConfigSource() ConfigSource {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	return cm.configSource
+}
+
+// Start starts the polling. Failed fetches are retried with an exponential backoff, the first poll is delayed by a
+// random startup jitter when one is configured. The staleness of the project config is checked after each poll and at
+// the staleness threshold, so that it is reported even when the polls take longer than the threshold.
 func (cm *PollingProjectConfigManager) Start(ctx context.Context) {
 	if cm.pollingInterval <= 0 {
 		cm.logger.Info("Polling Config Manager Disabled")
 		return
 	}
 	cm.logger.Debug("Polling Config Manager Initiated")
-	t := time.NewTicker(cm.pollingInterval)
+
+	var stalenessTicks <-chan time.Time
+	if cm.stalenessThreshold > 0 {
+		stalenessTicker := time.NewTicker(cm.stalenessThreshold)
+		defer stalenessTicker.Stop()
+		stalenessTicks = stalenessTicker.C
+	}
+
+	failures := 0
+	t := time.NewTimer(cm.pollingInterval + cm.jitter(cm.startupJitter))
+	defer t.Stop()
 	for {
 		select {
 		case <-t.C:
-			cm.SyncConfig()
+			delay := cm.pollingInterval
+			if err := cm.syncConfig(); err != nil {
+				failures++
+				delay = cm.backoffDelay(failures)
+				cm.logger.Debug(fmt.Sprintf("Datafile fetch failed %d time(s) in a row, next attempt in %v", failures, delay))
+			} else {
+				failures = 0
+			}
+			t.Reset(delay)
+			cm.checkStaleness()
+		case <-stalenessTicks:
+			cm.checkStaleness()
 		case <-ctx.Done():
 			cm.logger.Debug("Polling Config Manager Stopped")
 			return
@@ -197,6 +561,83 @@ func (cm *PollingProjectConfigManager) Start(ctx context.Context) {
 	}
 }
 
+// backoffDelay returns the delay before the next attempt after the given number of consecutive failures: the polling
+// interval doubled for each failure up to the maximum backoff, of which a random half is subtracted
+func (cm *PollingProjectConfigManager) backoffDelay(failures int) time.Duration {
+	delay := cm.pollingInterval
+	for i := 0; i < failures && delay < cm.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > cm.maxBackoff {
+		delay = cm.maxBackoff
+	}
+	if delay < cm.pollingInterval {
+		delay = cm.pollingInterval
+	}
+	return delay/2 + cm.jitter(delay/2)
+}
+
+// Staleness returns how long the current project config has gone without being confirmed or refreshed by a fetch
+func (cm *PollingProjectConfigManager) Staleness() time.Duration {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	if cm.lastRefresh.IsZero() {
+		return 0
+	}
+	return cm.now().Sub(cm.lastRefresh)
+}
+
+// setRefreshed records a successful fetch, it must be called with the config lock held
+func (cm *PollingProjectConfigManager) setRefreshed() {
+	cm.lastRefresh = cm.now()
+	cm.staleNotified = false
+}
+
+func (cm *PollingProjectConfigManager) checkStaleness() {
+	cm.configLock.Lock()
+	if cm.stalenessThreshold <= 0 || cm.projectConfig == nil || cm.lastRefresh.IsZero() {
+		cm.configLock.Unlock()
+		return
+	}
+	staleness := cm.now().Sub(cm.lastRefresh)
+	cm.stalenessGauge.Set(staleness.Seconds())
+	if staleness < cm.stalenessThreshold || cm.staleNotified {
+		cm.configLock.Unlock()
+		return
+	}
+	cm.staleNotified = true
+	projectConfigStaleNotification := notification.ProjectConfigStaleNotification{
+		Type:        notification.ProjectConfigStale,
+		Revision:    cm.projectConfig.GetRevision(),
+		LastRefresh: cm.lastRefresh,
+		Staleness:   staleness,
+	}
+	cm.configLock.Unlock()
+
+	cm.logger.Warning(fmt.Sprintf("The project config has not been refreshed for %v, current revision: %s", staleness, projectConfigStaleNotification.Revision))
+	if cm.notificationCenter != nil {
+		if err := cm.notificationCenter.Send(notification.ProjectConfigStale, projectConfigStaleNotification); err != nil {
+			cm.logger.Warning("Problem with sending notification")
+		}
+	}
+}
+
+// jitterRand generates the random jitters, it is seeded since the functions of math/rand are not before go1.20
+var jitterRand = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// randomJitter returns a random duration in [0, max)
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	jitterRand.Lock()
+	defer jitterRand.Unlock()
+	return time.Duration(jitterRand.Int63n(int64(max)))
+}
+
 func (cm *PollingProjectConfigManager) setAuthHeaderIfDatafileAccessTokenPresent() {
 	if cm.datafileAccessToken != "" {
 		headers := []utils.Header{{Name: "Content-Type", Value: "application/json"}, {Name: "Accept", Value: "application/json"}}
@@ -212,7 +653,13 @@ func newConfigManager(sdkKey string, logger logging.OptimizelyLogProducer, confi
 		requester:          utils.NewHTTPRequester(logging.GetLogger(sdkKey, "HTTPRequester")),
 		sdkKey:             sdkKey,
 		logger:             logger,
+		ready:              make(chan struct{}),
+		maxBackoff:         DefaultMaxBackoff,
+		signatureHeader:    DatafileSignatureHeader,
+		now:                time.Now,
+		jitter:             randomJitter,
 	}
+	WithMetricsRegistry(metrics.NewNoopRegistry())(&pollingProjectConfigManager)
 
 	for _, opt := range configOptions {
 		opt(&pollingProjectConfigManager)
@@ -235,8 +682,9 @@ func NewPollingProjectConfigManager(sdkKey string, pollingMangerOptions ...Optio
 	pollingProjectConfigManager := newConfigManager(sdkKey, logging.GetLogger(sdkKey, "PollingProjectConfigManager"), pollingMangerOptions...)
 
 	if len(pollingProjectConfigManager.initDatafile) > 0 {
-		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile)
+		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile, pollingProjectConfigManager.initSignature, ConfigSourceInitialDatafile)
 	} else {
+		pollingProjectConfigManager.loadCachedDatafile()
 		pollingProjectConfigManager.SyncConfig() // initial poll
 	}
 	return pollingProjectConfigManager
@@ -247,7 +695,9 @@ func NewAsyncPollingProjectConfigManager(sdkKey string, pollingMangerOptions ...
 
 	pollingProjectConfigManager := newConfigManager(sdkKey, logging.GetLogger(sdkKey, "PollingProjectConfigManager"), pollingMangerOptions...)
 	if len(pollingProjectConfigManager.initDatafile) > 0 {
-		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile)
+		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile, pollingProjectConfigManager.initSignature, ConfigSourceInitialDatafile)
+	} else {
+		pollingProjectConfigManager.loadCachedDatafile()
 	}
 	return pollingProjectConfigManager
 }
@@ -262,6 +712,11 @@ func (cm *PollingProjectConfigManager) GetConfig() (ProjectConfig, error) {
 	return cm.projectConfig, nil
 }
 
+// Ready returns a channel which is closed once the first valid project config has been set
+func (cm *PollingProjectConfigManager) Ready() <-chan struct{} {
+	return cm.ready
+}
+
 // GetOptimizelyConfig returns the optimizely project config
 func (cm *PollingProjectConfigManager) GetOptimizelyConfig() *OptimizelyConfig {
 	cm.configLock.RLock()
@@ -300,6 +755,58 @@ func (cm *PollingProjectConfigManager) RemoveOnProjectConfigUpdate(id int) error
 	return nil
 }
 
+// OnProjectConfigStale registers a handler for ProjectConfigStale notifications
+func (cm *PollingProjectConfigManager) OnProjectConfigStale(callback func(notification.ProjectConfigStaleNotification)) (int, error) {
+	handler := func(payload interface{}) {
+		if projectConfigStaleNotification, ok := payload.(notification.ProjectConfigStaleNotification); ok {
+			callback(projectConfigStaleNotification)
+		} else {
+			cm.logger.Warning(fmt.Sprintf("Unable to convert notification payload %v into ProjectConfigStaleNotification", payload))
+		}
+	}
+	id, err := cm.notificationCenter.AddHandler(notification.ProjectConfigStale, handler)
+	if err != nil {
+		cm.logger.Warning("Problem with adding notification handler")
+		return 0, err
+	}
+	return id, nil
+}
+
+// RemoveOnProjectConfigStale removes handler for ProjectConfigStale notification with given id
+func (cm *PollingProjectConfigManager) RemoveOnProjectConfigStale(id int) error {
+	if err := cm.notificationCenter.RemoveHandler(id, notification.ProjectConfigStale); err != nil {
+		cm.logger.Warning("Problem with removing notification handler")
+		return err
+	}
+	return nil
+}
+
+// OnProjectConfigRejected registers a handler for ProjectConfigRejected notifications
+func (cm *PollingProjectConfigManager) OnProjectConfigRejected(callback func(notification.ProjectConfigRejectedNotification)) (int, error) {
+	handler := func(payload interface{}) {
+		if projectConfigRejectedNotification, ok := payload.(notification.ProjectConfigRejectedNotification); ok {
+			callback(projectConfigRejectedNotification)
+		} else {
+			cm.logger.Warning(fmt.Sprintf("Unable to convert notification payload %v into ProjectConfigRejectedNotification", payload))
+		}
+	}
+	id, err := cm.notificationCenter.AddHandler(notification.ProjectConfigRejected, handler)
+	if err != nil {
+		cm.logger.Warning("Problem with adding notification handler")
+		return 0, err
+	}
+	return id, nil
+}
+
+// RemoveOnProjectConfigRejected removes handler for ProjectConfigRejected notification with given id
+func (cm *PollingProjectConfigManager) RemoveOnProjectConfigRejected(id int) error {
+	if err := cm.notificationCenter.RemoveHandler(id, notification.ProjectConfigRejected); err != nil {
+		cm.logger.Warning("Problem with removing notification handler")
+		return err
+	}
+	return nil
+}
+
 func (cm *PollingProjectConfigManager) setConfig(projectConfig ProjectConfig) error {
 	if projectConfig == nil {
 		return errors.New("unable to set nil config")
@@ -308,26 +815,51 @@ func (cm *PollingProjectConfigManager) setConfig(projectConfig ProjectConfig) er
 	if cm.optimizelyConfig != nil {
 		cm.optimizelyConfig = NewOptimizelyConfig(projectConfig)
 	}
+	cm.readyOnce.Do(func() {
+		close(cm.ready)
+	})
 	return nil
 }
 
-func (cm *PollingProjectConfigManager) setInitialDatafile(datafile []byte) {
+// setInitialDatafile sets the project config of the given datafile, its base64 encoded signature is verified when a
+// signature verifier is set and it is validated in strict mode, a refused datafile is rejected
+func (cm *PollingProjectConfigManager) setInitialDatafile(datafile []byte, signature string, source ConfigSource) {
 	if len(datafile) != 0 {
+		if cm.signatureVerifier != nil {
+			if err := verifyDatafile(cm.signatureVerifier, datafile, signature); err != nil {
+				_ = cm.rejectDatafile(err)
+				return
+			}
+		}
+		if cm.strictValidation {
+			if err := validateDatafile(datafile, cm.logger); err != nil {
+				_ = cm.rejectDatafile(err)
+				return
+			}
+		}
 		cm.configLock.Lock()
 		defer cm.configLock.Unlock()
 		projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "DatafileProjectConfig"))
 		if projectConfig != nil {
 			err = cm.setConfig(projectConfig)
 		}
+		if err == nil {
+			cm.setRefreshed()
+			cm.configSource = source
+		}
 		cm.err = err
 	}
 }
 
-func (cm *PollingProjectConfigManager) sendConfigUpdateNotification() {
+func (cm *PollingProjectConfigManager) sendConfigUpdateNotification(previousConfig, projectConfig ProjectConfig) {
 	if cm.notificationCenter != nil {
 		projectConfigUpdateNotification := notification.ProjectConfigUpdateNotification{
 			Type:     notification.ProjectConfigUpdate,
-			Revision: cm.projectConfig.GetRevision(),
+			Revision: projectConfig.GetRevision(),
+			Diff:     DiffProjectConfigs(previousConfig, projectConfig),
+		}
+		if previousConfig != nil {
+			projectConfigUpdateNotification.OldRevision = previousConfig.GetRevision()
 		}
 		if err := cm.notificationCenter.Send(notification.ProjectConfigUpdate, projectConfigUpdateNotification); err != nil {
 			cm.logger.Warning("Problem with sending notification")
@@ -18,13 +18,16 @@ package config
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/optimizely/go-sdk/pkg/config/datafileprojectconfig"
 	"github.com/optimizely/go-sdk/pkg/logging"
+	"github.com/optimizely/go-sdk/pkg/metrics"
 	"github.com/optimizely/go-sdk/pkg/notification"
 	"github.com/optimizely/go-sdk/pkg/utils"
 
@@ -271,6 +274,127 @@ func TestNewAsyncPollingProjectConfigManagerWithLastModifiedDates(t *testing.T)
 	mockRequester.AssertExpectations(t)
 }
 
+type MockMetricsRegistry struct {
+	counters map[string]*MockCounter
+	gauges   map[string]*MockGauge
+}
+
+type MockCounter struct {
+	value float64
+}
+
+func (c *MockCounter) Add(delta float64) {
+	c.value += delta
+}
+
+type MockGauge struct {
+	value float64
+}
+
+func (g *MockGauge) Set(value float64) {
+	g.value = value
+}
+
+func (r *MockMetricsRegistry) GetCounter(key string) metrics.Counter {
+	if r.counters == nil {
+		r.counters = map[string]*MockCounter{}
+	}
+	if _, ok := r.counters[key]; !ok {
+		r.counters[key] = &MockCounter{}
+	}
+	return r.counters[key]
+}
+
+func (r *MockMetricsRegistry) GetGauge(key string) metrics.Gauge {
+	if r.gauges == nil {
+		r.gauges = map[string]*MockGauge{}
+	}
+	if _, ok := r.gauges[key]; !ok {
+		r.gauges[key] = &MockGauge{}
+	}
+	return r.gauges[key]
+}
+
+func TestNewAsyncPollingProjectConfigManagerWithETag(t *testing.T) {
+	mockDatafile1 := []byte(`{"revision":"42","botFiltering":true,"version": "4"}`)
+	mockRequester := new(MockRequester)
+	etag := `"5d8ab6b5b1c8e"`
+	responseHeaders := http.Header{}
+	responseHeaders.Set(ETag, etag)
+
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile1, responseHeaders, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header{{Name: IfNoneMatch, Value: etag}}).Return([]byte{}, responseHeaders, http.StatusNotModified, nil).Twice()
+
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithMetricsRegistry(metricsRegistry))
+
+	// Fetch valid config (first poll)
+	configManager.SyncConfig()
+	actual, _ := configManager.GetConfig()
+	assert.Equal(t, "42", actual.GetRevision())
+
+	// The entity tag is sent on the next polls, and the config is kept on 304
+	configManager.SyncConfig()
+	configManager.SyncConfig()
+	actual, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", actual.GetRevision())
+	mockRequester.AssertExpectations(t)
+
+	assert.Equal(t, 1.0, metricsRegistry.counters[metrics.ConfigManagerFetch].value)
+	assert.Equal(t, 2.0, metricsRegistry.counters[metrics.ConfigManagerNotModified].value)
+	assert.Equal(t, 0.0, metricsRegistry.counters[metrics.ConfigManagerFailedFetch].value)
+	assert.Equal(t, float64(2*len(mockDatafile1)), metricsRegistry.counters[metrics.ConfigManagerBytesSaved].value)
+}
+
+func TestFetchLatency(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil)
+
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithMetricsRegistry(metricsRegistry))
+	now := time.Now()
+	configManager.now = func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+
+	configManager.SyncConfig()
+	assert.Equal(t, 0.25, metricsRegistry.gauges[metrics.ConfigManagerFetchLatency].value)
+}
+
+func TestNewAsyncPollingProjectConfigManagerWithETagAndLastModified(t *testing.T) {
+	mockDatafile1 := []byte(`{"revision":"42","botFiltering":true,"version": "4"}`)
+	mockRequester := new(MockRequester)
+	etag := `"5d8ab6b5b1c8e"`
+	modifiedDate := "Wed, 16 Oct 2019 20:16:45 GMT"
+	responseHeaders := http.Header{}
+	responseHeaders.Set(ETag, etag)
+	responseHeaders.Set(LastModified, modifiedDate)
+
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile1, responseHeaders, http.StatusOK, nil)
+	mockRequester.On("Get", []utils.Header{{Name: ModifiedSince, Value: modifiedDate}, {Name: IfNoneMatch, Value: etag}}).Return([]byte{}, responseHeaders, http.StatusNotModified, nil)
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester))
+	configManager.SyncConfig()
+	configManager.SyncConfig()
+	actual, _ := configManager.GetConfig()
+	assert.Equal(t, "42", actual.GetRevision())
+	mockRequester.AssertExpectations(t)
+}
+
+func TestFailedFetchMetric(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unavailable"))
+
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithMetricsRegistry(metricsRegistry))
+	configManager.SyncConfig()
+
+	assert.Equal(t, 1.0, metricsRegistry.counters[metrics.ConfigManagerFailedFetch].value)
+	assert.Equal(t, 0.0, metricsRegistry.counters[metrics.ConfigManagerFetch].value)
+}
+
 func TestNewPollingProjectConfigManagerWithDifferentDatafileRevisions(t *testing.T) {
 	// Test newer datafile should replace the older one if revisions are different
 	mockDatafile1 := []byte(`{"revision":"42","botFiltering":true,"version": "4"}`)
@@ -677,3 +801,238 @@ func TestSetDatafileAccessTokenRequest(t *testing.T) {
 	assert.NotEqual(t, configManagerRequester, configManager.requester)
 	assert.NotEqual(t, asyncConfigManagerRequester, asyncConfigManager.requester)
 }
+
+func TestPollingProjectConfigManagerReady(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	sdkKey := "test_sdk_key"
+
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, http.Header{}, http.StatusOK, nil)
+
+	asyncConfigManager := NewAsyncPollingProjectConfigManager(sdkKey, WithRequester(mockRequester))
+	select {
+	case <-asyncConfigManager.Ready():
+		t.Fatal("config manager should not be ready before the first datafile is fetched")
+	default:
+	}
+
+	asyncConfigManager.SyncConfig()
+	select {
+	case <-asyncConfigManager.Ready():
+	default:
+		t.Fatal("config manager should be ready after the first datafile is fetched")
+	}
+
+	// syncing again must not close the channel twice
+	asyncConfigManager.SyncConfig()
+	mockRequester.AssertExpectations(t)
+}
+
+func TestPollingProjectConfigManagerReadyWithInitialDatafile(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	asyncConfigManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithInitialDatafile(mockDatafile))
+
+	select {
+	case <-asyncConfigManager.Ready():
+	default:
+		t.Fatal("config manager should be ready when an initial datafile is set")
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithPollingInterval(time.Minute), WithMaxBackoff(5*time.Minute))
+	configManager.jitter = func(max time.Duration) time.Duration {
+		return max
+	}
+
+	assert.Equal(t, 2*time.Minute, configManager.backoffDelay(1))
+	assert.Equal(t, 4*time.Minute, configManager.backoffDelay(2))
+	assert.Equal(t, 5*time.Minute, configManager.backoffDelay(3))
+	assert.Equal(t, 5*time.Minute, configManager.backoffDelay(100))
+
+	// half of the delay is random
+	configManager.jitter = func(max time.Duration) time.Duration {
+		return 0
+	}
+	assert.Equal(t, time.Minute, configManager.backoffDelay(1))
+	assert.Equal(t, 2*time.Minute, configManager.backoffDelay(2))
+
+	// the polling interval is the lower bound when it is above the maximum backoff
+	configManager.maxBackoff = time.Second
+	assert.Equal(t, 30*time.Second, configManager.backoffDelay(1))
+}
+
+func TestStartBacksOffOnFailureAndResetsOnSuccess(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unavailable")).Times(3)
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, http.Header{}, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unavailable")).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, http.Header{}, http.StatusOK, nil)
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester),
+		WithPollingInterval(10*time.Millisecond), WithMaxBackoff(80*time.Millisecond))
+	var delays []time.Duration
+	var lock sync.Mutex
+	configManager.jitter = func(max time.Duration) time.Duration {
+		lock.Lock()
+		defer lock.Unlock()
+		delays = append(delays, max)
+		return max
+	}
+
+	eg := newExecGroup()
+	eg.Go(configManager.Start)
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(delays) == 5
+	}, time.Second, 5*time.Millisecond)
+	eg.TerminateAndWait()
+
+	// startup jitter, then half of 20ms, 40ms and 80ms after each failure, and half of 20ms again after the failure
+	// following a successful fetch
+	expected := []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 10 * time.Millisecond}
+	assert.Equal(t, expected, delays)
+}
+
+func TestStartupJitter(t *testing.T) {
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithPollingInterval(time.Hour), WithStartupJitter(time.Minute))
+	jitters := make(chan time.Duration, 1)
+	configManager.jitter = func(max time.Duration) time.Duration {
+		jitters <- max
+		return 0
+	}
+
+	eg := newExecGroup()
+	eg.Go(configManager.Start)
+	assert.Equal(t, time.Minute, <-jitters)
+	eg.TerminateAndWait()
+}
+
+func TestStalenessNotification(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusNotModified, nil)
+
+	now := time.Now()
+	configManager := newConfigManager("test_sdk_key_stale", logger, WithRequester(mockRequester), WithStalenessThreshold(time.Hour))
+	configManager.now = func() time.Time {
+		return now
+	}
+	configManager.setInitialDatafile(mockDatafile, "", ConfigSourceInitialDatafile)
+
+	var notifications []notification.ProjectConfigStaleNotification
+	id, err := configManager.OnProjectConfigStale(func(n notification.ProjectConfigStaleNotification) {
+		notifications = append(notifications, n)
+	})
+	assert.NoError(t, err)
+	defer configManager.RemoveOnProjectConfigStale(id)
+
+	now = now.Add(30 * time.Minute)
+	configManager.checkStaleness()
+	assert.Equal(t, 30*time.Minute, configManager.Staleness())
+	assert.Empty(t, notifications)
+
+	now = now.Add(31 * time.Minute)
+	configManager.checkStaleness()
+	configManager.checkStaleness()
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, notification.ProjectConfigStale, notifications[0].Type)
+		assert.Equal(t, "42", notifications[0].Revision)
+		assert.Equal(t, 61*time.Minute, notifications[0].Staleness)
+	}
+
+	// a poll confirming the config resets the staleness
+	configManager.SyncConfig()
+	assert.Equal(t, time.Duration(0), configManager.Staleness())
+
+	now = now.Add(2 * time.Hour)
+	configManager.checkStaleness()
+	assert.Len(t, notifications, 2)
+}
+
+func TestStalenessCheckedBetweenPolls(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	configManager := newConfigManager("test_sdk_key_stale_timer", logger, WithPollingInterval(time.Hour),
+		WithStalenessThreshold(20*time.Millisecond))
+	configManager.setInitialDatafile(mockDatafile, "", ConfigSourceInitialDatafile)
+
+	notifications := make(chan notification.ProjectConfigStaleNotification, 1)
+	id, err := configManager.OnProjectConfigStale(func(n notification.ProjectConfigStaleNotification) {
+		notifications <- n
+	})
+	assert.NoError(t, err)
+	defer configManager.RemoveOnProjectConfigStale(id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go configManager.Start(ctx)
+
+	// the config becomes stale long before the next poll
+	select {
+	case n := <-notifications:
+		assert.Equal(t, "42", n.Revision)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the stale config should be reported")
+	}
+}
+
+func TestRandomJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), randomJitter(0))
+	for i := 0; i < 100; i++ {
+		jitter := randomJitter(time.Second)
+		assert.True(t, jitter >= 0 && jitter < time.Second)
+	}
+}
+
+func TestPollingProjectConfigManagerStrictValidation(t *testing.T) {
+	invalidDatafile := []byte(`{"revision":"43","version": "4","experiments":[{"id":"e1","key":"exp_1","audienceIds":["a1"]}]}`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(invalidDatafile, http.Header{}, http.StatusOK, nil)
+
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewAsyncPollingProjectConfigManager("strict_sdk_key", WithRequester(mockRequester),
+		WithMetricsRegistry(metricsRegistry), WithStrictValidation(true),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	var rejections []notification.ProjectConfigRejectedNotification
+	id, err := configManager.OnProjectConfigRejected(func(n notification.ProjectConfigRejectedNotification) {
+		rejections = append(rejections, n)
+	})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, configManager.RemoveOnProjectConfigRejected(id))
+	}()
+
+	err = configManager.syncConfig()
+	if assert.IsType(t, datafileprojectconfig.ValidationError{}, err) {
+		findings := err.(datafileprojectconfig.ValidationError).Report.Errors()
+		assert.Len(t, findings, 1)
+		assert.Equal(t, datafileprojectconfig.FindingMissingAudience, findings[0].Code)
+	}
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+	if assert.Len(t, rejections, 1) {
+		assert.Equal(t, "42", rejections[0].Revision)
+		assert.IsType(t, datafileprojectconfig.ValidationError{}, rejections[0].Reason)
+	}
+	assert.Equal(t, 1.0, metricsRegistry.counters[metrics.ConfigManagerRejectedDatafile].value)
+
+	// the same datafile is accepted when the validation isn't strict
+	lenientConfigManager := NewAsyncPollingProjectConfigManager("strict_sdk_key", WithRequester(mockRequester))
+	assert.NoError(t, lenientConfigManager.syncConfig())
+	projectConfig, err = lenientConfigManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "43", projectConfig.GetRevision())
+}
+
+func TestPollingProjectConfigManagerStrictValidationOfInitialDatafile(t *testing.T) {
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewAsyncPollingProjectConfigManager("strict_sdk_key", WithStrictValidation(true), WithMetricsRegistry(metricsRegistry),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4","featureFlags":[{"id":"f1","key":"flag_1","rolloutId":"r1"}]}`)))
+	projectConfig, err := configManager.GetConfig()
+	assert.Nil(t, projectConfig)
+	assert.IsType(t, datafileprojectconfig.ValidationError{}, err)
+	assert.Equal(t, 1.0, metricsRegistry.counters[metrics.ConfigManagerRejectedDatafile].value)
+}
@@ -0,0 +1,86 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrOlderRevision is returned when a datafile is rejected because its revision is older than the current one
+var ErrOlderRevision = errors.New("datafile revision is older than the current revision")
+
+// MonotonicRevisionPolicy rejects the datafiles with an older revision than the revision of the current project
+// config, such as the ones served by a stale CDN edge. Revisions which are not numbers are always accepted.
+type MonotonicRevisionPolicy struct {
+	allowedRollbacks map[rollbackKey]bool
+	rejections       int64
+	lock             sync.Mutex
+}
+
+// rollbackKey identifies an allowed rollback, revisions are only comparable within the same project
+type rollbackKey struct {
+	sdkKey   string
+	revision string
+}
+
+// NewMonotonicRevisionPolicy returns a new monotonic revision policy, it can be shared by the config managers of
+// several SDK keys
+func NewMonotonicRevisionPolicy() *MonotonicRevisionPolicy {
+	return &MonotonicRevisionPolicy{allowedRollbacks: map[rollbackKey]bool{}}
+}
+
+// AllowRollback forces the next datafile of the given SDK key with the given revision to be accepted even if it is
+// older than the current one, to deliberately roll back a project
+func (p *MonotonicRevisionPolicy) AllowRollback(sdkKey, revision string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.allowedRollbacks[rollbackKey{sdkKey: sdkKey, revision: revision}] = true
+}
+
+// Check returns ErrOlderRevision when the given revision of the SDK key is older than the current one and no rollback
+// to it is allowed
+func (p *MonotonicRevisionPolicy) Check(sdkKey, currentRevision, revision string) error {
+	current, err := strconv.ParseInt(currentRevision, 10, 64)
+	if err != nil {
+		return nil
+	}
+	next, err := strconv.ParseInt(revision, 10, 64)
+	if err != nil || next >= current {
+		return nil
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	key := rollbackKey{sdkKey: sdkKey, revision: revision}
+	if p.allowedRollbacks[key] {
+		delete(p.allowedRollbacks, key)
+		return nil
+	}
+	p.rejections++
+	return ErrOlderRevision
+}
+
+// Rejections returns the number of revisions rejected by the policy
+func (p *MonotonicRevisionPolicy) Rejections() int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.rejections
+}
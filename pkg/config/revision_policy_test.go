@@ -0,0 +1,147 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/metrics"
+	"github.com/optimizely/go-sdk/pkg/notification"
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonotonicRevisionPolicy(t *testing.T) {
+	policy := NewMonotonicRevisionPolicy()
+	assert.NoError(t, policy.Check("sdk_key", "42", "42"))
+	assert.NoError(t, policy.Check("sdk_key", "42", "43"))
+	assert.NoError(t, policy.Check("sdk_key", "9", "10"))
+	assert.NoError(t, policy.Check("sdk_key", "abc", "41"))
+	assert.NoError(t, policy.Check("sdk_key", "42", "abc"))
+	assert.Equal(t, ErrOlderRevision, policy.Check("sdk_key", "42", "41"))
+	assert.Equal(t, ErrOlderRevision, policy.Check("sdk_key", "10", "9"))
+	assert.Equal(t, int64(2), policy.Rejections())
+
+	// a rollback is only allowed once
+	policy.AllowRollback("sdk_key", "41")
+	assert.NoError(t, policy.Check("sdk_key", "42", "41"))
+	assert.Equal(t, ErrOlderRevision, policy.Check("sdk_key", "42", "41"))
+	assert.Equal(t, int64(3), policy.Rejections())
+
+	// a rollback is only allowed for its SDK key
+	policy.AllowRollback("other_sdk_key", "41")
+	assert.Equal(t, ErrOlderRevision, policy.Check("sdk_key", "42", "41"))
+	assert.NoError(t, policy.Check("other_sdk_key", "42", "41"))
+	assert.Equal(t, int64(4), policy.Rejections())
+}
+
+func TestPollingProjectConfigManagerRejectsOlderRevision(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"41","version": "4"}`), http.Header{}, http.StatusOK, nil)
+
+	policy := NewMonotonicRevisionPolicy()
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewAsyncPollingProjectConfigManager("revision_sdk_key", WithRequester(mockRequester),
+		WithMetricsRegistry(metricsRegistry), WithRevisionPolicy(policy),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	var rejections []notification.ProjectConfigRejectedNotification
+	id, err := configManager.OnProjectConfigRejected(func(n notification.ProjectConfigRejectedNotification) {
+		rejections = append(rejections, n)
+	})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, configManager.RemoveOnProjectConfigRejected(id))
+	}()
+
+	assert.Equal(t, ErrOlderRevision, configManager.syncConfig())
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+	assert.Equal(t, []notification.ProjectConfigRejectedNotification{
+		{Type: notification.ProjectConfigRejected, Revision: "42", Reason: ErrOlderRevision},
+	}, rejections)
+	assert.Equal(t, 1.0, metricsRegistry.counters[metrics.ConfigManagerRejectedRevision].value)
+	assert.Equal(t, 1.0, metricsRegistry.counters[metrics.ConfigManagerRejectedDatafile].value)
+
+	policy.AllowRollback("revision_sdk_key", "41")
+	assert.NoError(t, configManager.syncConfig())
+	projectConfig, err = configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "41", projectConfig.GetRevision())
+}
+
+func TestPollingProjectConfigManagerDoesNotKeepETagOfRejectedDatafile(t *testing.T) {
+	responseHeaders := http.Header{}
+	responseHeaders.Set(ETag, `"41"`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"41","version": "4"}`), responseHeaders, http.StatusOK, nil).Twice()
+
+	configManager := NewAsyncPollingProjectConfigManager("revision_sdk_key", WithRequester(mockRequester),
+		WithRevisionPolicy(NewMonotonicRevisionPolicy()), WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+
+	// the rejected datafile is downloaded again instead of being reported as not modified
+	assert.Equal(t, ErrOlderRevision, configManager.syncConfig())
+	assert.Equal(t, ErrOlderRevision, configManager.syncConfig())
+	mockRequester.AssertExpectations(t)
+}
+
+func TestPollingProjectConfigManagerAcceptsOlderRevisionWithoutPolicy(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"41","version": "4"}`), http.Header{}, http.StatusOK, nil)
+
+	configManager := NewAsyncPollingProjectConfigManager("revision_sdk_key", WithRequester(mockRequester),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	assert.NoError(t, configManager.syncConfig())
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "41", projectConfig.GetRevision())
+}
+
+func TestFileProjectConfigManagerRejectsOlderRevision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revision")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datafile.json")
+	writeDatafile := func(datafile string, modTime time.Time) {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(datafile), 0600))
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	writeDatafile(`{"revision":"42","version": "4"}`, time.Now().Add(-time.Hour))
+	policy := NewMonotonicRevisionPolicy()
+	configManager := NewFileProjectConfigManager("revision_sdk_key", path, WithFileRevisionPolicy(policy))
+
+	writeDatafile(`{"revision":"41","version": "4"}`, time.Now().Add(-time.Minute))
+	configManager.SyncConfig()
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+	assert.Equal(t, int64(1), policy.Rejections())
+
+	policy.AllowRollback("revision_sdk_key", "41")
+	writeDatafile(`{"revision":"41","version": "4"}`, time.Now())
+	configManager.SyncConfig()
+	projectConfig, err = configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "41", projectConfig.GetRevision())
+}
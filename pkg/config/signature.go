@@ -0,0 +1,96 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DatafileSignatureHeader is the default response header holding the signature of the datafile
+const DatafileSignatureHeader = "X-Datafile-Signature"
+
+// DefaultSignatureSidecar is the default suffix of the sidecar file holding the signature of a datafile on disk
+const DefaultSignatureSidecar = ".sig"
+
+// ErrMissingSignature is returned when a datafile which must be verified has no signature
+var ErrMissingSignature = errors.New("datafile signature is missing")
+
+// ErrInvalidSignature is returned when the signature of a datafile does not match any of the configured keys
+var ErrInvalidSignature = errors.New("datafile signature is invalid")
+
+// SignatureVerifier verifies the detached signature of a datafile
+type SignatureVerifier interface {
+	Verify(datafile, signature []byte) error
+}
+
+// SignatureSource is implemented by the datafile sources which can serve the signature of a datafile from a sidecar
+// file, the location of the datafile followed by the given suffix
+type SignatureSource interface {
+	FetchSignature(request DatafileRequest, suffix string) ([]byte, error)
+}
+
+// HMACSHA256Verifier verifies HMAC-SHA256 signatures
+type HMACSHA256Verifier struct {
+	keys [][]byte
+}
+
+// NewHMACSHA256Verifier returns a verifier accepting the signatures made with any of the given secret keys, which
+// allows keys to be rotated
+func NewHMACSHA256Verifier(keys ...[]byte) *HMACSHA256Verifier {
+	return &HMACSHA256Verifier{keys: keys}
+}
+
+// Verify verifies the signature of the datafile
+func (v *HMACSHA256Verifier) Verify(datafile, signature []byte) error {
+	for _, key := range v.keys {
+		mac := hmac.New(sha256.New, key)
+		_, _ = mac.Write(datafile)
+		if hmac.Equal(mac.Sum(nil), signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// verifyDatafile verifies the datafile against its base64 encoded signature
+func verifyDatafile(verifier SignatureVerifier, datafile []byte, encoded string) error {
+	signature, err := decodeSignature(encoded)
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(datafile, signature)
+}
+
+// decodeSignature decodes a base64 encoded signature
+func decodeSignature(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, ErrMissingSignature
+	}
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the datafile signature: %v", err)
+	}
+	return signature, nil
+}
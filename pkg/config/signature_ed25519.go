@@ -0,0 +1,46 @@
+//go:build go1.13
+// +build go1.13
+
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"crypto/ed25519"
+)
+
+// Ed25519Verifier verifies ed25519 signatures
+type Ed25519Verifier struct {
+	publicKeys []ed25519.PublicKey
+}
+
+// NewEd25519Verifier returns a verifier accepting the signatures made with the private key of any of the given public
+// keys, which allows keys to be rotated
+func NewEd25519Verifier(publicKeys ...ed25519.PublicKey) *Ed25519Verifier {
+	return &Ed25519Verifier{publicKeys: publicKeys}
+}
+
+// Verify verifies the signature of the datafile
+func (v *Ed25519Verifier) Verify(datafile, signature []byte) error {
+	for _, publicKey := range v.publicKeys {
+		if len(publicKey) == ed25519.PublicKeySize && ed25519.Verify(publicKey, datafile, signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,53 @@
+//go:build go1.13
+// +build go1.13
+
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEd25519Verifier(t *testing.T) {
+	oldPublicKey, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	datafile := []byte(`{"revision":"42","version": "4"}`)
+	signature := ed25519.Sign(privateKey, datafile)
+
+	assert.NoError(t, NewEd25519Verifier(oldPublicKey, publicKey).Verify(datafile, signature))
+	assert.Equal(t, ErrInvalidSignature, NewEd25519Verifier(oldPublicKey).Verify(datafile, signature))
+	assert.Equal(t, ErrInvalidSignature, NewEd25519Verifier(publicKey).Verify([]byte(`{"revision":"43"}`), signature))
+	assert.Equal(t, ErrInvalidSignature, NewEd25519Verifier(ed25519.PublicKey("short")).Verify(datafile, signature))
+
+	headers := http.Header{}
+	headers.Set(DatafileSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(datafile, headers, http.StatusOK, nil)
+	configManager := NewAsyncPollingProjectConfigManager("signature_sdk_key", WithRequester(mockRequester),
+		WithSignatureVerifier(NewEd25519Verifier(publicKey)))
+	assert.NoError(t, configManager.syncConfig())
+}
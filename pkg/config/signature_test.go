@@ -0,0 +1,200 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/metrics"
+	"github.com/optimizely/go-sdk/pkg/notification"
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// urlRequester serves the responses of its URLs
+type urlRequester struct {
+	utils.Requester
+	responses map[string]string
+}
+
+func (r urlRequester) Get(url string, headers ...utils.Header) (response []byte, responseHeaders http.Header, code int, err error) {
+	body, ok := r.responses[url]
+	if !ok {
+		return nil, http.Header{}, http.StatusNotFound, errors.New("404 Not Found")
+	}
+	return []byte(body), http.Header{}, http.StatusOK, nil
+}
+
+func hmacSignature(key, datafile []byte) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(datafile)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestHMACSHA256Verifier(t *testing.T) {
+	datafile := []byte(`{"revision":"42"}`)
+	mac := hmac.New(sha256.New, []byte("new_key"))
+	_, _ = mac.Write(datafile)
+	signature := mac.Sum(nil)
+
+	assert.NoError(t, NewHMACSHA256Verifier([]byte("old_key"), []byte("new_key")).Verify(datafile, signature))
+	assert.Equal(t, ErrInvalidSignature, NewHMACSHA256Verifier([]byte("old_key")).Verify(datafile, signature))
+	assert.Equal(t, ErrInvalidSignature, NewHMACSHA256Verifier([]byte("new_key")).Verify([]byte(`{"revision":"43"}`), signature))
+}
+
+func TestDecodeSignature(t *testing.T) {
+	signature, err := decodeSignature(" c2lnbmF0dXJl\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("signature"), signature)
+
+	_, err = decodeSignature("")
+	assert.Equal(t, ErrMissingSignature, err)
+	_, err = decodeSignature("not base64!")
+	assert.Error(t, err)
+}
+
+func TestSignatureFromHeader(t *testing.T) {
+	key := []byte("secret")
+	datafile1 := []byte(`{"revision":"42","version": "4"}`)
+	datafile2 := []byte(`{"revision":"43","version": "4"}`)
+	validHeaders := http.Header{}
+	validHeaders.Set(DatafileSignatureHeader, hmacSignature(key, datafile1))
+	tamperedHeaders := http.Header{}
+	tamperedHeaders.Set(DatafileSignatureHeader, hmacSignature(key, datafile1))
+
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(datafile1, validHeaders, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return(datafile2, tamperedHeaders, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return(datafile2, http.Header{}, http.StatusOK, nil).Once()
+
+	metricsRegistry := &MockMetricsRegistry{}
+	configManager := NewAsyncPollingProjectConfigManager("signature_sdk_key", WithRequester(mockRequester),
+		WithMetricsRegistry(metricsRegistry), WithSignatureVerifier(NewHMACSHA256Verifier(key)))
+	var rejections []notification.ProjectConfigRejectedNotification
+	id, err := configManager.OnProjectConfigRejected(func(n notification.ProjectConfigRejectedNotification) {
+		rejections = append(rejections, n)
+	})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, configManager.RemoveOnProjectConfigRejected(id))
+	}()
+
+	assert.NoError(t, configManager.syncConfig())
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+
+	// datafiles with an invalid or missing signature are rejected and the current config is kept
+	assert.Equal(t, ErrInvalidSignature, configManager.syncConfig())
+	assert.Equal(t, ErrMissingSignature, configManager.syncConfig())
+	projectConfig, err = configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+
+	assert.Equal(t, []notification.ProjectConfigRejectedNotification{
+		{Type: notification.ProjectConfigRejected, Revision: "42", Reason: ErrInvalidSignature},
+		{Type: notification.ProjectConfigRejected, Revision: "42", Reason: ErrMissingSignature},
+	}, rejections)
+	assert.Equal(t, 2.0, metricsRegistry.counters[metrics.ConfigManagerRejectedDatafile].value)
+	mockRequester.AssertExpectations(t)
+}
+
+func TestSignatureFromCustomHeader(t *testing.T) {
+	key := []byte("secret")
+	datafile := []byte(`{"revision":"42","version": "4"}`)
+	headers := http.Header{}
+	headers.Set("X-Signature", hmacSignature(key, datafile))
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(datafile, headers, http.StatusOK, nil)
+
+	configManager := NewAsyncPollingProjectConfigManager("signature_sdk_key", WithRequester(mockRequester),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)), WithSignatureHeader("X-Signature"))
+	assert.NoError(t, configManager.syncConfig())
+}
+
+func TestSignatureFromHTTPSidecar(t *testing.T) {
+	key := []byte("secret")
+	datafile := `{"revision":"42","version": "4"}`
+	requester := urlRequester{responses: map[string]string{
+		"https://cdn.example.com/signature_sdk_key.json":     datafile,
+		"https://cdn.example.com/signature_sdk_key.json.sig": hmacSignature(key, []byte(datafile)),
+	}}
+
+	configManager := NewAsyncPollingProjectConfigManager("signature_sdk_key", WithRequester(requester),
+		WithDatafileURLTemplate("https://cdn.example.com/%s.json"),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)), WithSignatureSidecar(".sig"))
+	assert.NoError(t, configManager.syncConfig())
+
+	delete(requester.responses, "https://cdn.example.com/signature_sdk_key.json.sig")
+	assert.Error(t, configManager.syncConfig())
+}
+
+func TestStaticManagerWithSignatureSidecarFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signature")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datafile.json")
+	key := []byte("secret")
+	datafile := []byte(`{"revision":"42","version": "4"}`)
+	assert.NoError(t, ioutil.WriteFile(path, datafile, 0600))
+
+	options := []OptionFunc{
+		WithDatafileSource(NewFileDatafileSource(path)),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)),
+		WithSignatureSidecar(".sig"),
+	}
+	assert.Nil(t, NewStaticProjectConfigManagerWithOptions("", options...))
+
+	assert.NoError(t, ioutil.WriteFile(path+".sig", []byte(hmacSignature(key, datafile)), 0600))
+	configManager := NewStaticProjectConfigManagerWithOptions("", options...)
+	if assert.NotNil(t, configManager) {
+		projectConfig, err := configManager.GetConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, "42", projectConfig.GetRevision())
+	}
+}
+
+func TestStaticManagerWithSignedInitialDatafile(t *testing.T) {
+	key := []byte("secret")
+	datafile := []byte(`{"revision":"42","version": "4"}`)
+	metricsRegistry := &MockMetricsRegistry{}
+
+	// unsigned or tampered initial datafiles are rejected
+	assert.Nil(t, NewStaticProjectConfigManagerWithOptions("", WithInitialDatafile(datafile),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)), WithMetricsRegistry(metricsRegistry)))
+	assert.Nil(t, NewStaticProjectConfigManagerWithOptions("", WithInitialDatafile(datafile),
+		WithInitialDatafileSignature(hmacSignature(key, []byte(`{"revision":"43","version": "4"}`))),
+		WithSignatureVerifier(NewHMACSHA256Verifier(key)), WithMetricsRegistry(metricsRegistry)))
+	assert.Equal(t, 2.0, metricsRegistry.counters[metrics.ConfigManagerRejectedDatafile].value)
+
+	configManager := NewStaticProjectConfigManagerWithOptions("", WithInitialDatafile(datafile),
+		WithInitialDatafileSignature(hmacSignature(key, datafile)), WithSignatureVerifier(NewHMACSHA256Verifier(key)))
+	if assert.NotNil(t, configManager) {
+		projectConfig, err := configManager.GetConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, "42", projectConfig.GetRevision())
+	}
+}
@@ -36,15 +36,17 @@ type StaticProjectConfigManager struct {
 	logger           logging.OptimizelyLogProducer
 }
 
-// NewStaticProjectConfigManagerWithOptions creates a new instance of the manager with the given sdk key and some options
+// NewStaticProjectConfigManagerWithOptions creates a new instance of the manager with the given sdk key and some options.
+// When a signature verifier is set with WithSignatureVerifier, the fetched or initial datafile is verified the same way
+// as by the polling manager and no manager is returned when it is rejected.
 func NewStaticProjectConfigManagerWithOptions(sdkKey string, configMangerOptions ...OptionFunc) *StaticProjectConfigManager {
 
 	logger := logging.GetLogger(sdkKey, "StaticProjectConfigManager")
 	staticProjectConfigManager := newConfigManager(sdkKey, logger, configMangerOptions...)
-	if sdkKey != "" {
+	if sdkKey != "" || staticProjectConfigManager.datafileSource != nil {
 		staticProjectConfigManager.SyncConfig()
 	} else if len(staticProjectConfigManager.initDatafile) > 0 {
-		staticProjectConfigManager.setInitialDatafile(staticProjectConfigManager.initDatafile)
+		staticProjectConfigManager.setInitialDatafile(staticProjectConfigManager.initDatafile, staticProjectConfigManager.initSignature, ConfigSourceInitialDatafile)
 	}
 	projectConfig, err := staticProjectConfigManager.GetConfig()
 	if err != nil {
@@ -68,7 +70,8 @@ func NewStaticProjectConfigManager(config ProjectConfig, logger logging.Optimize
 
 /********************* Old Constructors not used in go-sdk, kept for backward compatibility **********/
 
-// NewStaticProjectConfigManagerFromURL returns new instance of StaticProjectConfigManager for URL
+// NewStaticProjectConfigManagerFromURL returns new instance of StaticProjectConfigManager for URL, the signature of the
+// datafile is not verified
 func NewStaticProjectConfigManagerFromURL(sdkKey string) (*StaticProjectConfigManager, error) {
 
 	requester := utils.NewHTTPRequester(logging.GetLogger(sdkKey, "HTTPRequester"))
@@ -85,7 +88,8 @@ func NewStaticProjectConfigManagerFromURL(sdkKey string) (*StaticProjectConfigMa
 	return NewStaticProjectConfigManagerFromPayload(datafile, logger)
 }
 
-// NewStaticProjectConfigManagerFromPayload returns new instance of StaticProjectConfigManager for payload
+// NewStaticProjectConfigManagerFromPayload returns new instance of StaticProjectConfigManager for payload, the signature
+// of the datafile is not verified
 func NewStaticProjectConfigManagerFromPayload(payload []byte, logger logging.OptimizelyLogProducer) (*StaticProjectConfigManager, error) {
 	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(payload, logger)
 
@@ -0,0 +1,371 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/logging"
+	"github.com/optimizely/go-sdk/pkg/notification"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultReconnectDelay sets the default delay before reconnecting after the stream went down
+const DefaultReconnectDelay = time.Second
+
+// DefaultMaxReconnectDelay sets the default maximum delay between reconnection attempts
+const DefaultMaxReconnectDelay = time.Minute
+
+// DefaultReadIdleTimeout sets the default time after which a stream which sent nothing, not even a keep-alive comment,
+// is considered down
+const DefaultReadIdleTimeout = time.Minute
+
+// StreamEventDatafile is the type of the stream events carrying a complete datafile
+const StreamEventDatafile = "datafile"
+
+// StreamEventRevision is the type of the stream events announcing a new revision, which is then fetched
+const StreamEventRevision = "revision"
+
+// StreamingProjectConfigManager maintains the project config with the datafile updates pushed by a Server-Sent Events
+// stream. Events carrying a datafile are applied right away, events announcing a revision trigger a fetch with the
+// requester. While the stream is down the datafile is polled at the polling interval.
+type StreamingProjectConfigManager struct {
+	streamURLTemplate string
+	client            *http.Client
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+	readIdleTimeout   time.Duration
+	logger            logging.OptimizelyLogProducer
+
+	pollingOptions []OptionFunc
+	poller         *PollingProjectConfigManager
+	lastEventID    string
+}
+
+// StreamingOptionFunc is used to provide custom configuration to the StreamingProjectConfigManager.
+type StreamingOptionFunc func(*StreamingProjectConfigManager)
+
+// WithStreamClient is an optional function, sets the HTTP client used for the stream, it must not have a timeout
+func WithStreamClient(client *http.Client) StreamingOptionFunc {
+	return func(cm *StreamingProjectConfigManager) {
+		cm.client = client
+	}
+}
+
+// WithReconnectDelay is an optional function, sets the delay before the first reconnection attempt and the maximum
+// delay it doubles up to after each failed attempt
+func WithReconnectDelay(delay, maxDelay time.Duration) StreamingOptionFunc {
+	return func(cm *StreamingProjectConfigManager) {
+		cm.reconnectDelay = delay
+		cm.maxReconnectDelay = maxDelay
+	}
+}
+
+// WithReadIdleTimeout is an optional function, sets the time after which a stream which sent nothing, not even a
+// keep-alive comment, is closed and reopened. A non-positive timeout disables it.
+func WithReadIdleTimeout(timeout time.Duration) StreamingOptionFunc {
+	return func(cm *StreamingProjectConfigManager) {
+		cm.readIdleTimeout = timeout
+	}
+}
+
+// WithPollingOptions is an optional function, sets the options of the polling used to fetch datafiles, such as the
+// requester, the datafile URL template and the polling interval used while the stream is down
+func WithPollingOptions(options ...OptionFunc) StreamingOptionFunc {
+	return func(cm *StreamingProjectConfigManager) {
+		cm.pollingOptions = append(cm.pollingOptions, options...)
+	}
+}
+
+// NewStreamingProjectConfigManager returns a manager listening to the stream at the given URL template, which is
+// formatted with the SDK key. The stream is only opened when the manager is started.
+func NewStreamingProjectConfigManager(sdkKey, streamURLTemplate string, options ...StreamingOptionFunc) *StreamingProjectConfigManager {
+	streamingProjectConfigManager := &StreamingProjectConfigManager{
+		streamURLTemplate: streamURLTemplate,
+		client:            &http.Client{},
+		reconnectDelay:    DefaultReconnectDelay,
+		maxReconnectDelay: DefaultMaxReconnectDelay,
+		readIdleTimeout:   DefaultReadIdleTimeout,
+		logger:            logging.GetLogger(sdkKey, "StreamingProjectConfigManager"),
+	}
+
+	for _, opt := range options {
+		opt(streamingProjectConfigManager)
+	}
+
+	poller := newConfigManager(sdkKey, logging.GetLogger(sdkKey, "PollingProjectConfigManager"), streamingProjectConfigManager.pollingOptions...)
+	streamingProjectConfigManager.poller = poller
+	if len(poller.initDatafile) > 0 {
+		poller.setInitialDatafile(poller.initDatafile, poller.initSignature, ConfigSourceInitialDatafile)
+	} else {
+		poller.loadCachedDatafile()
+	}
+	return streamingProjectConfigManager
+}
+
+// Start fetches the datafile when there is no project config yet, then listens to the stream until the context is
+// done. The stream is reopened with an exponential backoff whenever it goes down.
+func (cm *StreamingProjectConfigManager) Start(ctx context.Context) {
+	if cm.poller.ConfigSource() != ConfigSourceInitialDatafile {
+		cm.poller.SyncConfig()
+	}
+
+	fallback := &fallbackPolling{poller: cm.poller}
+	defer fallback.stop()
+
+	failures := 0
+	for {
+		connected, err := cm.stream(ctx, func() {
+			if fallback.stop() {
+				// updates may have been missed while the stream was down
+				cm.poller.SyncConfig()
+			}
+		})
+		if ctx.Err() != nil {
+			cm.logger.Debug("Streaming Config Manager Stopped")
+			return
+		}
+		if connected {
+			failures = 0
+		}
+		failures++
+		delay := cm.backoffDelay(failures)
+		cm.logger.Warning(fmt.Sprintf("Datafile stream is down, reconnecting in %v: %v", delay, err))
+		fallback.start(ctx)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			cm.logger.Debug("Streaming Config Manager Stopped")
+			return
+		}
+	}
+}
+
+// fallbackPolling polls the datafile while the stream is down, starting with an immediate fetch
+type fallbackPolling struct {
+	poller *PollingProjectConfigManager
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (p *fallbackPolling) start(ctx context.Context) {
+	if p.cancel != nil {
+		return
+	}
+	var pollCtx context.Context
+	pollCtx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		// the first poll of the poller only comes after the polling interval
+		p.poller.SyncConfig()
+		p.poller.Start(pollCtx)
+	}(p.done)
+}
+
+// stop stops the polling and returns whether it was running
+func (p *fallbackPolling) stop() bool {
+	if p.cancel == nil {
+		return false
+	}
+	p.cancel()
+	<-p.done
+	p.cancel = nil
+	return true
+}
+
+// backoffDelay returns the delay before the given reconnection attempt: the reconnect delay doubled for each failed
+// attempt up to the maximum reconnect delay, of which a random half is subtracted
+func (cm *StreamingProjectConfigManager) backoffDelay(failures int) time.Duration {
+	delay := cm.reconnectDelay
+	for i := 1; i < failures && delay < cm.maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	if delay > cm.maxReconnectDelay {
+		delay = cm.maxReconnectDelay
+	}
+	return delay/2 + cm.poller.jitter(delay/2)
+}
+
+// stream listens to the stream until it is closed, it returns whether the stream was opened along with the reason
+// it went down. The connected function is called once the stream is open. The stream is closed when nothing is read
+// from it for the read idle timeout.
+func (cm *StreamingProjectConfigManager) stream(ctx context.Context, connected func()) (bool, error) {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf(cm.streamURLTemplate, cm.poller.sdkKey), nil)
+	if err != nil {
+		return false, err
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	request = request.WithContext(streamCtx)
+	request.Header.Set("Accept", "text/event-stream")
+	request.Header.Set("Cache-Control", "no-cache")
+	if cm.poller.datafileAccessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+cm.poller.datafileAccessToken)
+	}
+	if cm.lastEventID != "" {
+		request.Header.Set("Last-Event-ID", cm.lastEventID)
+	}
+
+	response, err := cm.client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if e := response.Body.Close(); e != nil {
+			cm.logger.Warning(fmt.Sprintf("can't close the datafile stream: %v", e))
+		}
+	}()
+	if response.StatusCode != http.StatusOK {
+		return false, errors.New(fmt.Sprintf("unexpected stream response: %s", response.Status))
+	}
+
+	cm.logger.Debug("Datafile stream connected")
+	connected()
+
+	read := func() {}
+	var idle int32
+	if cm.readIdleTimeout > 0 {
+		watchdog := time.AfterFunc(cm.readIdleTimeout, func() {
+			atomic.StoreInt32(&idle, 1)
+			cancel()
+		})
+		defer watchdog.Stop()
+		read = func() {
+			watchdog.Reset(cm.readIdleTimeout)
+		}
+	}
+	err = cm.readEvents(response.Body, read)
+	if atomic.LoadInt32(&idle) == 1 {
+		err = errors.New(fmt.Sprintf("nothing read from the stream for %v", cm.readIdleTimeout))
+	}
+	return true, err
+}
+
+// readEvents dispatches the events read from the stream until it is closed, the read function is called for every
+// line read, including the keep-alive comments
+func (cm *StreamingProjectConfigManager) readEvents(body io.Reader, read func()) error {
+	reader := bufio.NewReader(body)
+	var eventType, eventID string
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return errors.New("stream closed by the server")
+			}
+			return err
+		}
+		read()
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if len(data) > 0 {
+				if eventID != "" {
+					cm.lastEventID = eventID
+				}
+				cm.handleEvent(eventType, strings.Join(data, "\n"))
+			}
+			eventType, eventID, data = "", "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// comments keep the connection alive
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			eventID = value
+		}
+	}
+}
+
+func (cm *StreamingProjectConfigManager) handleEvent(eventType, data string) {
+	switch eventType {
+	case StreamEventDatafile:
+		if cm.poller.signatureVerifier != nil {
+			// streamed datafiles have no signature, the datafile is fetched to be verified
+			cm.logger.Debug("Fetching the streamed datafile to verify its signature")
+			cm.poller.SyncConfig()
+			return
+		}
+		if err := cm.poller.applyDatafile([]byte(data), ""); err != nil {
+			cm.logger.Warning(fmt.Sprintf("Unable to apply the streamed datafile: %v", err))
+		}
+	case StreamEventRevision:
+		revision := strings.TrimSpace(data)
+		if projectConfig, err := cm.poller.GetConfig(); err == nil && projectConfig != nil && projectConfig.GetRevision() == revision {
+			cm.logger.Debug(fmt.Sprintf("Revision %s is already in use", revision))
+			return
+		}
+		cm.logger.Debug(fmt.Sprintf("Fetching the datafile of revision %s", revision))
+		cm.poller.SyncConfig()
+	default:
+		cm.logger.Debug(fmt.Sprintf("Ignoring stream event of type %q", eventType))
+	}
+}
+
+// GetConfig returns the project config
+func (cm *StreamingProjectConfigManager) GetConfig() (ProjectConfig, error) {
+	return cm.poller.GetConfig()
+}
+
+// GetOptimizelyConfig returns the optimizely project config
+func (cm *StreamingProjectConfigManager) GetOptimizelyConfig() *OptimizelyConfig {
+	return cm.poller.GetOptimizelyConfig()
+}
+
+// Ready returns a channel which is closed once the first valid project config has been set
+func (cm *StreamingProjectConfigManager) Ready() <-chan struct{} {
+	return cm.poller.Ready()
+}
+
+// ConfigSource returns where the current project config comes from
+func (cm *StreamingProjectConfigManager) ConfigSource() ConfigSource {
+	return cm.poller.ConfigSource()
+}
+
+// OnProjectConfigUpdate registers a handler for ProjectConfigUpdate notifications
+func (cm *StreamingProjectConfigManager) OnProjectConfigUpdate(callback func(notification.ProjectConfigUpdateNotification)) (int, error) {
+	return cm.poller.OnProjectConfigUpdate(callback)
+}
+
+// RemoveOnProjectConfigUpdate removes handler for ProjectConfigUpdate notification with given id
+func (cm *StreamingProjectConfigManager) RemoveOnProjectConfigUpdate(id int) error {
+	return cm.poller.RemoveOnProjectConfigUpdate(id)
+}
@@ -0,0 +1,279 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/notification"
+	"github.com/optimizely/go-sdk/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// closeStream makes the SSE stand-in server close the current stream
+const closeStream = "close"
+
+// sseServer is a stand-in for the datafile stream, the messages sent on its events channel are written as is
+type sseServer struct {
+	*httptest.Server
+	events      chan string
+	rejections  int32
+	connections int32
+	paths       chan string
+}
+
+func newSSEServer(rejections int32) *sseServer {
+	server := &sseServer{
+		events:     make(chan string),
+		rejections: rejections,
+		paths:      make(chan string, 10),
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&server.connections, 1)
+		if atomic.AddInt32(&server.rejections, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		server.paths <- r.URL.Path
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+		_, _ = fmt.Fprint(w, ": connected\n\n")
+		flusher.Flush()
+		for {
+			select {
+			case event := <-server.events:
+				if event == closeStream {
+					return
+				}
+				_, _ = fmt.Fprint(w, event)
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	return server
+}
+
+func (s *sseServer) getConnections() int32 {
+	return atomic.LoadInt32(&s.connections)
+}
+
+type StreamingProjectConfigManagerTestSuite struct {
+	suite.Suite
+	server    *sseServer
+	requester *MockRequester
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) SetupTest() {
+	s.requester = new(MockRequester)
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TearDownTest() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	// the streams are closed once the manager is stopped
+	s.server.Close()
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) newServer(rejections int32) *sseServer {
+	s.server = newSSEServer(rejections)
+	return s.server
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) start(server *sseServer, options ...StreamingOptionFunc) *StreamingProjectConfigManager {
+	options = append([]StreamingOptionFunc{
+		WithReconnectDelay(10*time.Millisecond, 40*time.Millisecond),
+		WithPollingOptions(WithRequester(s.requester), WithPollingInterval(20*time.Millisecond)),
+	}, options...)
+	configManager := NewStreamingProjectConfigManager("stream_sdk_key", server.URL+"/streams/%s", options...)
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		configManager.Start(ctx)
+	}()
+	return configManager
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) assertRevision(configManager *StreamingProjectConfigManager, revision string) {
+	s.Eventually(func() bool {
+		projectConfig, err := configManager.GetConfig()
+		return err == nil && projectConfig.GetRevision() == revision
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TestStreamedDatafile() {
+	s.requester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"1","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	server := s.newServer(0)
+
+	configManager := s.start(server)
+	s.assertRevision(configManager, "1")
+	s.Equal("/streams/stream_sdk_key", <-server.paths)
+
+	revisions := make(chan string, 1)
+	id, err := configManager.OnProjectConfigUpdate(func(n notification.ProjectConfigUpdateNotification) {
+		revisions <- n.Revision
+	})
+	s.NoError(err)
+	defer func() {
+		s.NoError(configManager.RemoveOnProjectConfigUpdate(id))
+	}()
+
+	// the data of an event may span several lines
+	server.events <- "id: 7\nevent: datafile\ndata: {\"revision\":\"2\",\ndata: \"version\": \"4\"}\n\n"
+	s.Equal("2", <-revisions)
+	s.assertRevision(configManager, "2")
+	s.Equal(ConfigSourceRemote, configManager.ConfigSource())
+	s.requester.AssertNumberOfCalls(s.T(), "Get", 1)
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TestRevisionEventFetchesDatafile() {
+	s.requester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"1","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+	s.requester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"3","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+	server := s.newServer(0)
+
+	configManager := s.start(server)
+	s.assertRevision(configManager, "1")
+	<-server.paths
+
+	// the revision in use is not fetched again
+	server.events <- "event: revision\ndata: 1\n\n"
+	server.events <- "event: unknown\ndata: ignored\n\n"
+	server.events <- "event: revision\ndata: 3\n\n"
+	s.assertRevision(configManager, "3")
+	s.requester.AssertExpectations(s.T())
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TestReconnectWithFallbackPolling() {
+	s.requester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"1","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+	s.requester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"2","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	server := s.newServer(3)
+
+	configManager := s.start(server)
+	// the datafile is polled while the stream is rejected
+	s.assertRevision(configManager, "2")
+	<-server.paths
+	s.Equal(int32(4), server.getConnections())
+
+	server.events <- "event: datafile\ndata: {\"revision\":\"4\",\"version\": \"4\"}\n\n"
+	s.assertRevision(configManager, "4")
+
+	// the stream is reopened when the server closes it
+	server.events <- closeStream
+	<-server.paths
+	server.events <- "event: datafile\ndata: {\"revision\":\"5\",\"version\": \"4\"}\n\n"
+	s.assertRevision(configManager, "5")
+	s.Equal(int32(5), server.getConnections())
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TestStreamedDatafileWithSignatureVerifier() {
+	key := []byte("secret")
+	datafile := []byte(`{"revision":"2","version": "4"}`)
+	headers := http.Header{}
+	headers.Set(DatafileSignatureHeader, hmacSignature(key, datafile))
+	s.requester.On("Get", []utils.Header(nil)).Return(datafile, headers, http.StatusOK, nil)
+	server := s.newServer(0)
+
+	initialDatafile := []byte(`{"revision":"1","version": "4"}`)
+	configManager := s.start(server, WithPollingOptions(WithInitialDatafile(initialDatafile),
+		WithInitialDatafileSignature(hmacSignature(key, initialDatafile)), WithSignatureVerifier(NewHMACSHA256Verifier(key))))
+	<-server.paths
+
+	// the unsigned streamed datafile is not applied, the signed datafile is fetched instead
+	server.events <- "event: datafile\ndata: {\"revision\":\"3\",\"version\": \"4\"}\n\n"
+	s.assertRevision(configManager, "2")
+	s.requester.AssertNumberOfCalls(s.T(), "Get", 1)
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TestFallbackPollingFetchesRightAway() {
+	s.requester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"2","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	server := s.newServer(1000)
+
+	configManager := s.start(server, WithPollingOptions(WithInitialDatafile([]byte(`{"revision":"1","version": "4"}`)),
+		WithPollingInterval(time.Hour)))
+	// the datafile is fetched as soon as the stream is down rather than after the polling interval
+	s.assertRevision(configManager, "2")
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TestReadIdleTimeout() {
+	s.requester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"1","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	server := s.newServer(0)
+
+	s.start(server, WithReadIdleTimeout(50*time.Millisecond))
+	<-server.paths
+
+	// keep-alive comments keep the stream open
+	for i := 0; i < 5; i++ {
+		time.Sleep(20 * time.Millisecond)
+		server.events <- ": keep-alive\n"
+	}
+	s.Equal(int32(1), server.getConnections())
+
+	// the stream is reopened once nothing is read from it for the idle timeout
+	select {
+	case path := <-server.paths:
+		s.Equal("/streams/stream_sdk_key", path)
+	case <-time.After(time.Second):
+		s.Fail("the idle stream should be reopened")
+	}
+	s.Equal(int32(2), server.getConnections())
+}
+
+func (s *StreamingProjectConfigManagerTestSuite) TestInitialDatafile() {
+	server := s.newServer(0)
+
+	configManager := s.start(server, WithPollingOptions(WithInitialDatafile([]byte(`{"revision":"1","version": "4"}`))))
+	s.assertRevision(configManager, "1")
+	<-server.paths
+	s.Equal(ConfigSourceInitialDatafile, configManager.ConfigSource())
+	s.requester.AssertNotCalled(s.T(), "Get", []utils.Header(nil))
+}
+
+func TestStreamingProjectConfigManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamingProjectConfigManagerTestSuite))
+}
+
+func TestStreamingBackoffDelay(t *testing.T) {
+	configManager := NewStreamingProjectConfigManager("", "", WithReconnectDelay(time.Second, 5*time.Second))
+	configManager.poller.jitter = func(max time.Duration) time.Duration {
+		return max
+	}
+
+	assert.Equal(t, time.Second, configManager.backoffDelay(1))
+	assert.Equal(t, 2*time.Second, configManager.backoffDelay(2))
+	assert.Equal(t, 4*time.Second, configManager.backoffDelay(3))
+	assert.Equal(t, 5*time.Second, configManager.backoffDelay(4))
+	assert.Equal(t, 5*time.Second, configManager.backoffDelay(10))
+}
@@ -0,0 +1,41 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decide //
+package decide
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDecideMessageSDKNotReady(t *testing.T) {
+	assert.Equal(t, "Optimizely SDK not configured properly yet", GetDecideMessage(SDKNotReady))
+}
+
+func TestGetDecideMessageFlagKeyInvalid(t *testing.T) {
+	assert.Equal(t, `No flag was found for key "test_flag".`, GetDecideMessage(FlagKeyInvalid, "test_flag"))
+}
+
+func TestGetDecideMessageVariableValueInvalid(t *testing.T) {
+	assert.Equal(t, `Variable value for key "test_var" is invalid or wrong type.`, GetDecideMessage(VariableValueInvalid, "test_var"))
+}
+
+func TestGetDecideError(t *testing.T) {
+	assert.Equal(t, errors.New(`No flag was found for key "test_flag".`), GetDecideError(FlagKeyInvalid, "test_flag"))
+}
@@ -0,0 +1,99 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decide //
+package decide
+
+import (
+	"errors"
+)
+
+// OptimizelyDecideOptions controlling flag decisions.
+type OptimizelyDecideOptions string
+
+const (
+	// DisableDecisionEvent when set, prevents the SDK from dispatching an impression event when serving a variation.
+	DisableDecisionEvent OptimizelyDecideOptions = "DISABLE_DECISION_EVENT"
+	// EnabledFlagsOnly when set, returns decisions only for flags which are enabled.
+	EnabledFlagsOnly OptimizelyDecideOptions = "ENABLED_FLAGS_ONLY"
+	// IgnoreUserProfileService when set, skips user profile service for decision.
+	IgnoreUserProfileService OptimizelyDecideOptions = "IGNORE_USER_PROFILE_SERVICE"
+	// IncludeReasons when set, includes info and debug messages in the decision reasons.
+	IncludeReasons OptimizelyDecideOptions = "INCLUDE_REASONS"
+	// ExcludeVariables when set, excludes variable values from the decision result.
+	ExcludeVariables OptimizelyDecideOptions = "EXCLUDE_VARIABLES"
+	// IncludeAudienceTrace when set, includes the trace of each audience evaluation in the decision reasons.
+	IncludeAudienceTrace OptimizelyDecideOptions = "INCLUDE_AUDIENCE_TRACE"
+)
+
+// Options defines options for controlling flag decisions.
+type Options struct {
+	DisableDecisionEvent     bool
+	EnabledFlagsOnly         bool
+	IgnoreUserProfileService bool
+	IncludeReasons           bool
+	ExcludeVariables         bool
+	IncludeAudienceTrace     bool
+}
+
+// NewOptions returns the options with the given decide options enabled.
+func NewOptions(options ...OptimizelyDecideOptions) (*Options, error) {
+	decideOptions := &Options{}
+	for _, option := range options {
+		switch option {
+		case DisableDecisionEvent:
+			decideOptions.DisableDecisionEvent = true
+		case EnabledFlagsOnly:
+			decideOptions.EnabledFlagsOnly = true
+		case IgnoreUserProfileService:
+			decideOptions.IgnoreUserProfileService = true
+		case IncludeReasons:
+			decideOptions.IncludeReasons = true
+		case ExcludeVariables:
+			decideOptions.ExcludeVariables = true
+		case IncludeAudienceTrace:
+			decideOptions.IncludeAudienceTrace = true
+		default:
+			return decideOptions, errors.New("invalid option: " + string(option))
+		}
+	}
+	return decideOptions, nil
+}
+
+// TranslateOptions converts string options array to array of OptimizelyDecideOptions
+func TranslateOptions(options []string) ([]OptimizelyDecideOptions, error) {
+	decideOptions := []OptimizelyDecideOptions{}
+	for _, val := range options {
+		option := OptimizelyDecideOptions(val)
+		if _, err := NewOptions(option); err != nil {
+			return []OptimizelyDecideOptions{}, err
+		}
+		decideOptions = append(decideOptions, option)
+	}
+	return decideOptions, nil
+}
+
+// Merge returns options that have every option set which is set in either o or other.
+func (o Options) Merge(other Options) Options {
+	return Options{
+		DisableDecisionEvent:     o.DisableDecisionEvent || other.DisableDecisionEvent,
+		EnabledFlagsOnly:         o.EnabledFlagsOnly || other.EnabledFlagsOnly,
+		IgnoreUserProfileService: o.IgnoreUserProfileService || other.IgnoreUserProfileService,
+		IncludeReasons:           o.IncludeReasons || other.IncludeReasons,
+		ExcludeVariables:         o.ExcludeVariables || other.ExcludeVariables,
+		IncludeAudienceTrace:     o.IncludeAudienceTrace || other.IncludeAudienceTrace,
+	}
+}
@@ -0,0 +1,74 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decide //
+package decide
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptions(t *testing.T) {
+	options, err := NewOptions(DisableDecisionEvent, EnabledFlagsOnly, IgnoreUserProfileService, IncludeReasons, ExcludeVariables,
+		IncludeAudienceTrace)
+	assert.NoError(t, err)
+	assert.Equal(t, &Options{
+		DisableDecisionEvent:     true,
+		EnabledFlagsOnly:         true,
+		IgnoreUserProfileService: true,
+		IncludeReasons:           true,
+		ExcludeVariables:         true,
+		IncludeAudienceTrace:     true,
+	}, options)
+
+	options, err = NewOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, &Options{}, options)
+}
+
+func TestNewOptionsInvalidOption(t *testing.T) {
+	_, err := NewOptions(IncludeReasons, "INVALID")
+	assert.EqualError(t, err, "invalid option: INVALID")
+}
+
+func TestTranslateOptionsValidCases(t *testing.T) {
+	options := []string{"DISABLE_DECISION_EVENT", "ENABLED_FLAGS_ONLY", "IGNORE_USER_PROFILE_SERVICE", "INCLUDE_REASONS", "EXCLUDE_VARIABLES",
+		"INCLUDE_AUDIENCE_TRACE"}
+	translatedOptions, err := TranslateOptions(options)
+	assert.NoError(t, err)
+	assert.Equal(t, []OptimizelyDecideOptions{DisableDecisionEvent, EnabledFlagsOnly, IgnoreUserProfileService, IncludeReasons, ExcludeVariables,
+		IncludeAudienceTrace}, translatedOptions)
+
+	translatedOptions, err = TranslateOptions([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []OptimizelyDecideOptions{}, translatedOptions)
+}
+
+func TestTranslateOptionsInvalidCases(t *testing.T) {
+	translatedOptions, err := TranslateOptions([]string{"DISABLE_DECISION_EVENT", "disable_decision_event"})
+	assert.EqualError(t, err, "invalid option: disable_decision_event")
+	assert.Equal(t, []OptimizelyDecideOptions{}, translatedOptions)
+}
+
+func TestMergeOptions(t *testing.T) {
+	defaultOptions := Options{DisableDecisionEvent: true}
+	options := Options{IncludeReasons: true}
+	assert.Equal(t, Options{DisableDecisionEvent: true, IncludeReasons: true}, defaultOptions.Merge(options))
+	assert.Equal(t, Options{IncludeAudienceTrace: true}, Options{}.Merge(Options{IncludeAudienceTrace: true}))
+	assert.Equal(t, Options{}, Options{}.Merge(Options{}))
+}
@@ -0,0 +1,104 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decide //
+package decide
+
+import (
+	"fmt"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+)
+
+// AudienceTrace is the trace of the audience evaluation of a rule
+type AudienceTrace struct {
+	RuleKey string                  `json:"ruleKey"`
+	Trace   *entities.TreeNodeTrace `json:"trace"`
+}
+
+// DecisionReasons collects the reasons explaining how a decision was made.
+// Errors are always collected, info messages only when the IncludeReasons option is set and audience traces only when
+// the IncludeAudienceTrace option is set.
+// A nil *DecisionReasons is valid and does not collect anything.
+type DecisionReasons struct {
+	errors         []string
+	infos          []string
+	audienceTraces []AudienceTrace
+	includeInfos   bool
+	includeTraces  bool
+}
+
+// NewDecisionReasons returns a new instance of DecisionReasons for the given options.
+func NewDecisionReasons(options *Options) *DecisionReasons {
+	return &DecisionReasons{
+		errors:        []string{},
+		infos:         []string{},
+		includeInfos:  options != nil && options.IncludeReasons,
+		includeTraces: options != nil && options.IncludeAudienceTrace,
+	}
+}
+
+// AddError appends the given error message and returns it.
+func (r *DecisionReasons) AddError(format string, arguments ...interface{}) string {
+	message := fmt.Sprintf(format, arguments...)
+	if r != nil {
+		r.errors = append(r.errors, message)
+	}
+	return message
+}
+
+// AddInfo appends the given info message, if info messages are collected, and returns it.
+func (r *DecisionReasons) AddInfo(format string, arguments ...interface{}) string {
+	message := fmt.Sprintf(format, arguments...)
+	if r != nil && r.includeInfos {
+		r.infos = append(r.infos, message)
+	}
+	return message
+}
+
+// IncludesAudienceTraces returns whether audience traces are collected.
+func (r *DecisionReasons) IncludesAudienceTraces() bool {
+	return r != nil && r.includeTraces
+}
+
+// AddAudienceTrace appends the trace of the audience evaluation of the given rule, if audience traces are collected.
+func (r *DecisionReasons) AddAudienceTrace(ruleKey string, trace *entities.TreeNodeTrace) {
+	if r.IncludesAudienceTraces() {
+		r.audienceTraces = append(r.audienceTraces, AudienceTrace{RuleKey: ruleKey, Trace: trace})
+	}
+}
+
+// AudienceTraces returns the collected audience traces.
+func (r *DecisionReasons) AudienceTraces() []AudienceTrace {
+	if r == nil {
+		return nil
+	}
+	return r.audienceTraces
+}
+
+// ToReport returns the collected errors followed by the collected info messages and audience traces.
+func (r *DecisionReasons) ToReport() []string {
+	if r == nil {
+		return []string{}
+	}
+	report := make([]string, 0, len(r.errors)+len(r.infos)+len(r.audienceTraces))
+	report = append(report, r.errors...)
+	report = append(report, r.infos...)
+	for _, audienceTrace := range r.audienceTraces {
+		report = append(report, fmt.Sprintf(`Audience evaluation trace for rule "%s": %s`, audienceTrace.RuleKey, audienceTrace.Trace))
+	}
+	return report
+}
@@ -0,0 +1,86 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decide //
+package decide
+
+import (
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDecisionReasonsWithEmptyOptions(t *testing.T) {
+	reasons := NewDecisionReasons(&Options{})
+	assert.Equal(t, 0, len(reasons.ToReport()))
+}
+
+func TestAddErrorWorksWithEveryOption(t *testing.T) {
+	reasons := NewDecisionReasons(&Options{IncludeReasons: true})
+	message := reasons.AddError("error message %d", 1)
+	assert.Equal(t, "error message 1", message)
+
+	reasons = NewDecisionReasons(nil)
+	reasons.AddError("error message")
+	assert.Equal(t, []string{"error message"}, reasons.ToReport())
+}
+
+func TestAddInfoIsOnlyValidWithIncludeReasonsOption(t *testing.T) {
+	reasons := NewDecisionReasons(&Options{})
+	message := reasons.AddInfo("info message %s", "1")
+	assert.Equal(t, "info message 1", message)
+	assert.Equal(t, 0, len(reasons.ToReport()))
+
+	reasons = NewDecisionReasons(&Options{IncludeReasons: true})
+	reasons.AddInfo("info message")
+	assert.Equal(t, []string{"info message"}, reasons.ToReport())
+}
+
+func TestToReportListsErrorsFirst(t *testing.T) {
+	reasons := NewDecisionReasons(&Options{IncludeReasons: true})
+	reasons.AddInfo("info message")
+	reasons.AddError("error message")
+	assert.Equal(t, []string{"error message", "info message"}, reasons.ToReport())
+}
+
+func TestAddAudienceTraceIsOnlyValidWithIncludeAudienceTraceOption(t *testing.T) {
+	result := false
+	trace := &entities.TreeNodeTrace{AttributeName: "age", MatchType: "gt", ExpectedValue: 17, ActualValue: 15, Result: &result}
+
+	reasons := NewDecisionReasons(&Options{IncludeReasons: true})
+	assert.False(t, reasons.IncludesAudienceTraces())
+	reasons.AddAudienceTrace("rule_1", trace)
+	assert.Empty(t, reasons.AudienceTraces())
+	assert.Equal(t, 0, len(reasons.ToReport()))
+
+	reasons = NewDecisionReasons(&Options{IncludeAudienceTrace: true})
+	assert.True(t, reasons.IncludesAudienceTraces())
+	reasons.AddAudienceTrace("rule_1", trace)
+	assert.Equal(t, []AudienceTrace{{RuleKey: "rule_1", Trace: trace}}, reasons.AudienceTraces())
+	assert.Equal(t, []string{`Audience evaluation trace for rule "rule_1": age gt 17 (actual 15) => false`}, reasons.ToReport())
+}
+
+func TestNilDecisionReasons(t *testing.T) {
+	var reasons *DecisionReasons
+	assert.Equal(t, "info message", reasons.AddInfo("info message"))
+	assert.Equal(t, "error message", reasons.AddError("error message"))
+	assert.Equal(t, []string{}, reasons.ToReport())
+	assert.False(t, reasons.IncludesAudienceTraces())
+	reasons.AddAudienceTrace("rule_1", &entities.TreeNodeTrace{})
+	assert.Nil(t, reasons.AudienceTraces())
+}
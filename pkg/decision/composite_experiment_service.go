@@ -18,7 +18,9 @@
 package decision
 
 import (
+	"context"
 	"fmt"
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
 )
@@ -40,17 +42,27 @@ func WithOverrideStore(overrideStore ExperimentOverrideStore) CESOptionFunc {
 	}
 }
 
+// WithHoldoutService sets the service bucketing users into the global holdouts, by default only the holdouts of the
+// datafile are checked
+func WithHoldoutService(holdoutService *HoldoutService) CESOptionFunc {
+	return func(f *CompositeExperimentService) {
+		f.holdoutService = holdoutService
+	}
+}
+
 // CompositeExperimentService bridges together the various experiment decision services that ship by default with the SDK
 type CompositeExperimentService struct {
 	experimentServices []ExperimentService
 	overrideStore      ExperimentOverrideStore
 	userProfileService UserProfileService
+	holdoutService     *HoldoutService
 	logger             logging.OptimizelyLogProducer
 }
 
 // NewCompositeExperimentService creates a new instance of the CompositeExperimentService
 func NewCompositeExperimentService(sdkKey string, options ...CESOptionFunc) *CompositeExperimentService {
-	// These decision services are applied in order:
+	// These decision services are applied in order, once the experiment is known to be running and the user not to be
+	// held out:
 	// 1. Overrides (if supplied)
 	// 2. Whitelist
 	// 3. Bucketing (with User profile integration if supplied)
@@ -58,6 +70,9 @@ func NewCompositeExperimentService(sdkKey string, options ...CESOptionFunc) *Com
 	for _, opt := range options {
 		opt(compositeExperimentService)
 	}
+	if compositeExperimentService.holdoutService == nil {
+		compositeExperimentService.holdoutService = NewHoldoutService(sdkKey)
+	}
 	experimentServices := []ExperimentService{
 		NewExperimentWhitelistService(),
 	}
@@ -82,10 +97,31 @@ func NewCompositeExperimentService(sdkKey string, options ...CESOptionFunc) *Com
 
 // GetDecision returns a decision for the given experiment and user context
 func (s CompositeExperimentService) GetDecision(decisionContext ExperimentDecisionContext, userContext entities.UserContext) (decision ExperimentDecision, err error) {
+	return s.GetDecisionWithContext(context.Background(), decisionContext, userContext)
+}
+
+// GetDecisionWithContext returns a decision for the given experiment and user context, bound to the given context.
+// Users get no variation when the experiment is not running, even if they are whitelisted or have a saved decision,
+// and when they are held out by a global holdout. A held out user gets the HeldOut reason along with the decision of
+// the holdout, so that the impression of the holdout can be sent.
+func (s CompositeExperimentService) GetDecisionWithContext(ctx context.Context, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (decision ExperimentDecision, err error) {
+	if experiment := decisionContext.Experiment; experiment != nil && !experiment.IsRunning() {
+		s.logger.Debug(decisionContext.Reasons.AddInfo(`Experiment "%s" is not running, its status is "%s".`, experiment.Key, experiment.Status))
+		decision.Reason = reasons.ExperimentNotRunning
+		return decision, nil
+	}
+
+	if s.holdoutService != nil {
+		if holdoutDecision, ok := s.holdoutService.GetDecision(decisionContext.ProjectConfig, userContext, decisionContext.Reasons); ok {
+			decision.Decision = holdoutDecision.Decision
+			decision.Holdout = &holdoutDecision
+			return decision, nil
+		}
+	}
 
 	// Run through the various decision services until we get a decision
 	for _, experimentService := range s.experimentServices {
-		decision, err = experimentService.GetDecision(decisionContext, userContext)
+		decision, err = getExperimentDecision(ctx, experimentService, decisionContext, userContext)
 		if err != nil {
 			s.logger.Debug(fmt.Sprintf("%v", err))
 		}
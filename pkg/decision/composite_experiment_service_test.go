@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
 )
@@ -155,23 +156,116 @@ func (s *CompositeExperimentTestSuite) TestGetDecisionReturnsError() {
 	s.mockExperimentService2.AssertExpectations(s.T())
 }
 
+func (s *CompositeExperimentTestSuite) TestGetDecisionHeldOut() {
+	// test that held out users get no variation and the experiment services are not called
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	s.mockConfig.On("GetHoldoutList").Return([]entities.Holdout{})
+
+	compositeExperimentService := &CompositeExperimentService{
+		experimentServices: []ExperimentService{s.mockExperimentService},
+		holdoutService:     NewHoldoutService("", newTestHoldout(100)),
+		logger:             logging.GetLogger("sdkKey", "ExperimentService"),
+	}
+	decision, err := compositeExperimentService.GetDecision(s.testDecisionContext, testUserContext)
+	s.NoError(err)
+	s.Nil(decision.Variation)
+	s.Equal(reasons.HeldOut, decision.Reason)
+	if s.NotNil(decision.Holdout) {
+		s.Equal(LocalHoldout, decision.Holdout.Source)
+		s.Equal("holdout_1", decision.Holdout.Experiment.ID)
+		s.Equal("off", decision.Holdout.Variation.Key)
+	}
+	s.mockExperimentService.AssertNotCalled(s.T(), "GetDecision")
+}
+
+func (s *CompositeExperimentTestSuite) TestGetDecisionExperimentNotRunning() {
+	// test that the experiment services are not called when the experiment is not running
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	experiment := testExp1111
+	experiment.Status = entities.ExperimentStatusPaused
+	testDecisionContext := ExperimentDecisionContext{
+		Experiment:    &experiment,
+		ProjectConfig: s.mockConfig,
+	}
+
+	compositeExperimentService := &CompositeExperimentService{
+		experimentServices: []ExperimentService{s.mockExperimentService},
+		holdoutService:     NewHoldoutService("", newTestHoldout(100)),
+		logger:             logging.GetLogger("sdkKey", "ExperimentService"),
+	}
+	decision, err := compositeExperimentService.GetDecision(testDecisionContext, testUserContext)
+	s.NoError(err)
+	s.Equal(ExperimentDecision{Decision: Decision{Reason: reasons.ExperimentNotRunning}}, decision)
+	s.mockExperimentService.AssertNotCalled(s.T(), "GetDecision")
+	s.mockConfig.AssertNotCalled(s.T(), "GetHoldoutList")
+}
+
+func (s *CompositeExperimentTestSuite) TestGetDecisionWhitelistedUserExperimentNotRunning() {
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	experiment := testExpWhitelist
+	experiment.Status = entities.ExperimentStatusArchived
+	testDecisionContext := ExperimentDecisionContext{
+		Experiment:    &experiment,
+		ProjectConfig: s.mockConfig,
+	}
+
+	decision, err := NewCompositeExperimentService("").GetDecision(testDecisionContext, testUserContext)
+	s.NoError(err)
+	s.Nil(decision.Variation)
+	s.Equal(reasons.ExperimentNotRunning, decision.Reason)
+}
+
+func (s *CompositeExperimentTestSuite) TestGetDecisionSavedDecisionExperimentNotRunning() {
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	experiment := testExp1111
+	experiment.Status = entities.ExperimentStatusPaused
+	testDecisionContext := ExperimentDecisionContext{
+		Experiment:    &experiment,
+		ProjectConfig: s.mockConfig,
+	}
+	mockUserProfileService := new(MockUserProfileService)
+	mockUserProfileService.On("Lookup", testUserContext.ID).Return(UserProfile{
+		ID:                  testUserContext.ID,
+		ExperimentBucketMap: map[UserDecisionKey]string{NewUserDecisionKey(experiment.ID): "2222"},
+	})
+
+	compositeExperimentService := NewCompositeExperimentService("", WithUserProfileService(mockUserProfileService))
+	decision, err := compositeExperimentService.GetDecision(testDecisionContext, testUserContext)
+	s.NoError(err)
+	s.Nil(decision.Variation)
+	s.Equal(reasons.ExperimentNotRunning, decision.Reason)
+	mockUserProfileService.AssertNotCalled(s.T(), "Lookup", testUserContext.ID)
+}
+
 func (s *CompositeExperimentTestSuite) TestNewCompositeExperimentService() {
 	// Assert that the service is instantiated with the correct child services in the right order
 	compositeExperimentService := NewCompositeExperimentService("")
 	s.Equal(2, len(compositeExperimentService.experimentServices))
 	s.IsType(&ExperimentWhitelistService{}, compositeExperimentService.experimentServices[0])
 	s.IsType(&ExperimentBucketerService{}, compositeExperimentService.experimentServices[1])
+	s.NotNil(compositeExperimentService.holdoutService)
 }
 
 func (s *CompositeExperimentTestSuite) TestNewCompositeExperimentServiceWithCustomOptions() {
 	mockUserProfileService := new(MockUserProfileService)
 	mockExperimentOverrideStore := new(MapExperimentOverridesStore)
+	holdoutService := NewHoldoutService("", newTestHoldout(5))
 	compositeExperimentService := NewCompositeExperimentService("",
 		WithUserProfileService(mockUserProfileService),
 		WithOverrideStore(mockExperimentOverrideStore),
+		WithHoldoutService(holdoutService),
 	)
 	s.Equal(mockUserProfileService, compositeExperimentService.userProfileService)
 	s.Equal(mockExperimentOverrideStore, compositeExperimentService.overrideStore)
+	s.Equal(holdoutService, compositeExperimentService.holdoutService)
 }
 
 func TestCompositeExperimentTestSuite(t *testing.T) {
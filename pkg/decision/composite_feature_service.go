@@ -18,6 +18,7 @@
 package decision
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/optimizely/go-sdk/pkg/entities"
@@ -27,6 +28,7 @@ import (
 // CompositeFeatureService is the default out-of-the-box feature decision service
 type CompositeFeatureService struct {
 	featureServices []FeatureService
+	holdoutService  *HoldoutService
 	logger logging.OptimizelyLogProducer
 }
 
@@ -34,6 +36,7 @@ type CompositeFeatureService struct {
 func NewCompositeFeatureService(sdkKey string, compositeExperimentService ExperimentService) *CompositeFeatureService {
 	return &CompositeFeatureService{
 		logger:logging.GetLogger(sdkKey, "CompositeFeatureService"),
+		holdoutService: NewHoldoutService(sdkKey),
 		featureServices: []FeatureService{
 			NewFeatureExperimentService(logging.GetLogger(sdkKey, "FeatureExperimentService"), compositeExperimentService),
 			NewRolloutService(sdkKey),
@@ -43,10 +46,26 @@ func NewCompositeFeatureService(sdkKey string, compositeExperimentService Experi
 
 // GetDecision returns a decision for the given feature and user context
 func (f CompositeFeatureService) GetDecision(decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	return f.GetDecisionWithContext(context.Background(), decisionContext, userContext)
+}
+
+// GetDecisionWithContext returns a decision for the given feature and user context, bound to the given context.
+// Users held out by a global holdout get the variation of the holdout, only forced decisions take precedence over it.
+func (f CompositeFeatureService) GetDecisionWithContext(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	if forcedDecision, ok := decisionContext.ForcedDecisionService.FindValidatedForcedDecision(decisionContext.Feature, nil, decisionContext.Reasons); ok {
+		return forcedDecision, nil
+	}
+
+	if f.holdoutService != nil {
+		if holdoutDecision, ok := f.holdoutService.GetDecision(decisionContext.ProjectConfig, userContext, decisionContext.Reasons); ok {
+			return holdoutDecision, nil
+		}
+	}
+
 	var featureDecision = FeatureDecision{}
 	var err error
 	for _, featureDecisionService := range f.featureServices {
-		featureDecision, err = featureDecisionService.GetDecision(decisionContext, userContext)
+		featureDecision, err = getFeatureDecision(ctx, featureDecisionService, decisionContext, userContext)
 		if err != nil {
 			f.logger.Debug(fmt.Sprintf("%v", err))
 		}
@@ -162,6 +162,60 @@ func (s *CompositeFeatureServiceTestSuite) TestGetDecisionReturnsLastDecisionWit
 	s.mockFeatureService2.AssertExpectations(s.T())
 }
 
+func (s *CompositeFeatureServiceTestSuite) TestGetDecisionWithForcedDecision() {
+	// test that a forced decision for the flag is returned without calling the feature services
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	forcedDecisionService := NewForcedDecisionService(testUserContext.ID)
+	forcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: testFeat3335Key}, OptimizelyForcedDecision{VariationKey: "2224"})
+	featureDecisionContext := s.testFeatureDecisionContext
+	featureDecisionContext.ForcedDecisionService = forcedDecisionService
+
+	compositeFeatureService := &CompositeFeatureService{
+		featureServices: []FeatureService{
+			s.mockFeatureService,
+			s.mockFeatureService2,
+		},
+		logger: logging.GetLogger("sdkKey", "CompositeFeatureService"),
+	}
+	expectedDecision := FeatureDecision{
+		Decision:  Decision{Reason: reasons.ForcedDecisionFound},
+		Source:    ForcedDecision,
+		Variation: &testExp1113Var2224,
+	}
+	decision, err := compositeFeatureService.GetDecision(featureDecisionContext, testUserContext)
+	s.Equal(expectedDecision, decision)
+	s.NoError(err)
+	s.mockFeatureService.AssertNotCalled(s.T(), "GetDecision")
+	s.mockFeatureService2.AssertNotCalled(s.T(), "GetDecision")
+}
+
+func (s *CompositeFeatureServiceTestSuite) TestGetDecisionHeldOut() {
+	// test that held out users get the variation of the holdout and the feature services are not called
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	mockConfig := new(mockProjectConfig)
+	mockConfig.On("GetHoldoutList").Return([]entities.Holdout{newTestHoldout(100)})
+	s.testFeatureDecisionContext.ProjectConfig = mockConfig
+
+	compositeFeatureService := &CompositeFeatureService{
+		featureServices: []FeatureService{s.mockFeatureService},
+		holdoutService:  NewHoldoutService(""),
+		logger:          logging.GetLogger("sdkKey", "CompositeFeatureService"),
+	}
+	decision, err := compositeFeatureService.GetDecision(s.testFeatureDecisionContext, testUserContext)
+	s.NoError(err)
+	s.Equal(Holdout, decision.Source)
+	s.Equal(reasons.HeldOut, decision.Reason)
+	s.Equal("holdout_1", decision.Experiment.ID)
+	if s.NotNil(decision.Variation) {
+		s.False(decision.Variation.FeatureEnabled)
+	}
+	s.mockFeatureService.AssertNotCalled(s.T(), "GetDecision")
+}
+
 func (s *CompositeFeatureServiceTestSuite) TestNewCompositeFeatureService() {
 	// Assert that the service is instantiated with the correct child services in the right order
 	compositeExperimentService := NewCompositeExperimentService("")
@@ -18,6 +18,7 @@
 package decision
 
 import (
+	"context"
 	"fmt"
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
@@ -29,6 +30,7 @@ import (
 type CompositeService struct {
 	compositeExperimentService ExperimentService
 	compositeFeatureService    FeatureService
+	holdoutService             *HoldoutService
 	notificationCenter         notification.Center
 	logger                     logging.OptimizelyLogProducer
 }
@@ -43,6 +45,14 @@ func WithCompositeExperimentService(compositeExperimentService ExperimentService
 	}
 }
 
+// WithCompositeHoldoutService sets the service bucketing users into the global holdouts on the feature decisions, it
+// should be the one of the composite experiment service
+func WithCompositeHoldoutService(holdoutService *HoldoutService) CSOptionFunc {
+	return func(f *CompositeService) {
+		f.holdoutService = holdoutService
+	}
+}
+
 // NewCompositeService returns a new instance of the CompositeService with the defaults
 func NewCompositeService(sdkKey string, options ...CSOptionFunc) *CompositeService {
 	compositeService := &CompositeService{
@@ -57,21 +67,35 @@ func NewCompositeService(sdkKey string, options ...CSOptionFunc) *CompositeServi
 	if compositeService.compositeExperimentService == nil {
 		compositeService.compositeExperimentService = NewCompositeExperimentService(sdkKey)
 	}
-	compositeService.compositeFeatureService = NewCompositeFeatureService(sdkKey, compositeService.compositeExperimentService)
+	compositeFeatureService := NewCompositeFeatureService(sdkKey, compositeService.compositeExperimentService)
+	if compositeService.holdoutService != nil {
+		compositeFeatureService.holdoutService = compositeService.holdoutService
+	}
+	compositeService.compositeFeatureService = compositeFeatureService
 
 	return compositeService
 }
 
 // GetFeatureDecision returns a decision for the given feature key
 func (s CompositeService) GetFeatureDecision(featureDecisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
-	featureDecision, err := s.compositeFeatureService.GetDecision(featureDecisionContext, userContext)
+	return s.GetFeatureDecisionWithContext(context.Background(), featureDecisionContext, userContext)
+}
+
+// GetFeatureDecisionWithContext returns a decision for the given feature key, bound to the given context
+func (s CompositeService) GetFeatureDecisionWithContext(ctx context.Context, featureDecisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	featureDecision, err := getFeatureDecision(ctx, s.compositeFeatureService, featureDecisionContext, userContext)
 
 	return featureDecision, err
 }
 
 // GetExperimentDecision returns a decision for the given experiment key
 func (s CompositeService) GetExperimentDecision(experimentDecisionContext ExperimentDecisionContext, userContext entities.UserContext) (experimentDecision ExperimentDecision, err error) {
-	if experimentDecision, err = s.compositeExperimentService.GetDecision(experimentDecisionContext, userContext); err != nil {
+	return s.GetExperimentDecisionWithContext(context.Background(), experimentDecisionContext, userContext)
+}
+
+// GetExperimentDecisionWithContext returns a decision for the given experiment key, bound to the given context
+func (s CompositeService) GetExperimentDecisionWithContext(ctx context.Context, experimentDecisionContext ExperimentDecisionContext, userContext entities.UserContext) (experimentDecision ExperimentDecision, err error) {
+	if experimentDecision, err = getExperimentDecision(ctx, s.compositeExperimentService, experimentDecisionContext, userContext); err != nil {
 		return experimentDecision, err
 	}
 
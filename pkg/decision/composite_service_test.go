@@ -77,6 +77,14 @@ func (s *CompositeServiceFeatureTestSuite) TestNewCompositeServiceWithCustomOpti
 	s.IsType(&CompositeFeatureService{}, compositeService.compositeFeatureService)
 }
 
+func (s *CompositeServiceFeatureTestSuite) TestNewCompositeServiceWithHoldoutService() {
+	holdoutService := NewHoldoutService("", newTestHoldout(5))
+	compositeService := NewCompositeService("sdk_key", WithCompositeHoldoutService(holdoutService))
+	compositeFeatureService, ok := compositeService.compositeFeatureService.(*CompositeFeatureService)
+	s.True(ok)
+	s.Equal(holdoutService, compositeFeatureService.holdoutService)
+}
+
 type CompositeServiceExperimentTestSuite struct {
 	suite.Suite
 	decisionContext       ExperimentDecisionContext
@@ -0,0 +1,101 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decision //
+package decision
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+)
+
+// getExperimentDecision makes the decision bound to the given context when the service supports it
+func getExperimentDecision(ctx context.Context, experimentService ExperimentService, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error) {
+	if contextService, ok := experimentService.(ContextExperimentService); ok {
+		return contextService.GetDecisionWithContext(ctx, decisionContext, userContext)
+	}
+	return experimentService.GetDecision(decisionContext, userContext)
+}
+
+// getFeatureDecision makes the decision bound to the given context when the service supports it
+func getFeatureDecision(ctx context.Context, featureService FeatureService, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	if contextService, ok := featureService.(ContextFeatureService); ok {
+		return contextService.GetDecisionWithContext(ctx, decisionContext, userContext)
+	}
+	return featureService.GetDecision(decisionContext, userContext)
+}
+
+type userProfileResult struct {
+	userProfile UserProfile
+	err         error
+}
+
+// lookupUserProfile returns the saved user profile, or the context error when the context is done first.
+// User profile services which do not accept a context are waited on in the background.
+func lookupUserProfile(ctx context.Context, userProfileService UserProfileService, userID string) (UserProfile, error) {
+	if contextService, ok := userProfileService.(ContextUserProfileService); ok {
+		return contextService.LookupWithContext(ctx, userID)
+	}
+	if ctx.Done() == nil {
+		return userProfileService.Lookup(userID), nil
+	}
+
+	result := runWithContext(ctx, func() UserProfile {
+		return userProfileService.Lookup(userID)
+	})
+	return result.userProfile, result.err
+}
+
+// saveUserProfile saves the user profile, or returns the context error when the context is done first
+func saveUserProfile(ctx context.Context, userProfileService UserProfileService, userProfile UserProfile) error {
+	if contextService, ok := userProfileService.(ContextUserProfileService); ok {
+		return contextService.SaveWithContext(ctx, userProfile)
+	}
+	if ctx.Done() == nil {
+		userProfileService.Save(userProfile)
+		return nil
+	}
+
+	return runWithContext(ctx, func() UserProfile {
+		userProfileService.Save(userProfile)
+		return userProfile
+	}).err
+}
+
+func runWithContext(ctx context.Context, call func() UserProfile) userProfileResult {
+	if err := ctx.Err(); err != nil {
+		return userProfileResult{err: err}
+	}
+
+	results := make(chan userProfileResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				results <- userProfileResult{err: fmt.Errorf("user profile service is panicking: %v", r)}
+			}
+		}()
+		results <- userProfileResult{userProfile: call()}
+	}()
+
+	select {
+	case result := <-results:
+		return result
+	case <-ctx.Done():
+		return userProfileResult{err: ctx.Err()}
+	}
+}
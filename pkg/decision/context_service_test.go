@@ -0,0 +1,128 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package decision
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testContextKey string
+
+func TestLookupUserProfile(t *testing.T) {
+	userProfile := UserProfile{ID: "test_user"}
+	mockUserProfileService := new(MockUserProfileService)
+	mockUserProfileService.On("Lookup", "test_user").Return(userProfile)
+
+	result, err := lookupUserProfile(context.Background(), mockUserProfileService, "test_user")
+	assert.NoError(t, err)
+	assert.Equal(t, userProfile, result)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err = lookupUserProfile(ctx, mockUserProfileService, "test_user")
+	assert.NoError(t, err)
+	assert.Equal(t, userProfile, result)
+	mockUserProfileService.AssertNumberOfCalls(t, "Lookup", 2)
+}
+
+func TestLookupUserProfileDeadlineExceeded(t *testing.T) {
+	mockUserProfileService := new(MockUserProfileService)
+	mockUserProfileService.On("Lookup", "test_user").Return(UserProfile{ID: "test_user"}).After(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := lookupUserProfile(ctx, mockUserProfileService, "test_user")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < 200*time.Millisecond)
+}
+
+func TestLookupUserProfileCancelled(t *testing.T) {
+	mockUserProfileService := new(MockUserProfileService)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := lookupUserProfile(ctx, mockUserProfileService, "test_user")
+	assert.Equal(t, context.Canceled, err)
+	mockUserProfileService.AssertNotCalled(t, "Lookup", mock.Anything)
+}
+
+func TestLookupUserProfilePanics(t *testing.T) {
+	mockUserProfileService := new(MockUserProfileService)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// the mock panics since no return value is set up
+	_, err := lookupUserProfile(ctx, mockUserProfileService, "test_user")
+	assert.Error(t, err)
+}
+
+func TestLookupUserProfileWithContextService(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testContextKey("trace_id"), "1234")
+	userProfile := UserProfile{ID: "test_user"}
+	mockUserProfileService := new(MockContextUserProfileService)
+	mockUserProfileService.On("LookupWithContext", ctx, "test_user").Return(userProfile, nil)
+
+	result, err := lookupUserProfile(ctx, mockUserProfileService, "test_user")
+	assert.NoError(t, err)
+	assert.Equal(t, userProfile, result)
+	mockUserProfileService.AssertNotCalled(t, "Lookup", mock.Anything)
+}
+
+func TestSaveUserProfile(t *testing.T) {
+	userProfile := UserProfile{ID: "test_user"}
+	mockUserProfileService := new(MockUserProfileService)
+	mockUserProfileService.On("Save", userProfile).After(200 * time.Millisecond)
+
+	assert.NoError(t, saveUserProfile(context.Background(), mockUserProfileService, userProfile))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, saveUserProfile(ctx, mockUserProfileService, userProfile))
+
+	mockContextUserProfileService := new(MockContextUserProfileService)
+	mockContextUserProfileService.On("SaveWithContext", ctx, userProfile).Return(nil)
+	assert.NoError(t, saveUserProfile(ctx, mockContextUserProfileService, userProfile))
+	mockContextUserProfileService.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestGetFeatureDecisionWithContextService(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testContextKey("trace_id"), "1234")
+	decisionContext := FeatureDecisionContext{Feature: &testFeat3333}
+	userContext := entities.UserContext{ID: "test_user"}
+	expectedDecision := FeatureDecision{Source: FeatureTest}
+
+	mockFeatureService := new(MockFeatureDecisionService)
+	mockFeatureService.On("GetDecision", decisionContext, userContext).Return(expectedDecision, nil)
+	featureDecision, err := getFeatureDecision(ctx, mockFeatureService, decisionContext, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDecision, featureDecision)
+
+	mockContextFeatureService := new(MockContextFeatureDecisionService)
+	mockContextFeatureService.On("GetDecisionWithContext", ctx, decisionContext, userContext).Return(expectedDecision, nil)
+	featureDecision, err = getFeatureDecision(ctx, mockContextFeatureService, decisionContext, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDecision, featureDecision)
+	mockContextFeatureService.AssertNotCalled(t, "GetDecision", mock.Anything, mock.Anything)
+}
@@ -19,6 +19,7 @@ package decision
 
 import (
 	"github.com/optimizely/go-sdk/pkg/config"
+	"github.com/optimizely/go-sdk/pkg/decide"
 	"github.com/optimizely/go-sdk/pkg/decision/reasons"
 	"github.com/optimizely/go-sdk/pkg/entities"
 )
@@ -27,6 +28,8 @@ import (
 type ExperimentDecisionContext struct {
 	Experiment    *entities.Experiment
 	ProjectConfig config.ProjectConfig
+	Options       decide.Options
+	Reasons       *decide.DecisionReasons // optional, collects the reasons for the decision
 }
 
 // FeatureDecisionContext contains the information needed to be able to make a decision for a given feature
@@ -34,6 +37,10 @@ type FeatureDecisionContext struct {
 	Feature       *entities.Feature
 	ProjectConfig config.ProjectConfig
 	Variable      entities.Variable
+	Options       decide.Options
+	Reasons       *decide.DecisionReasons // optional, collects the reasons for the decision
+	// optional, forced decisions of the user context which take precedence over bucketing
+	ForcedDecisionService *ForcedDecisionService
 }
 
 // UnsafeFeatureDecisionInfo represents response for GetDetailedFeatureDecisionUnsafe api
@@ -52,6 +59,12 @@ const (
 	Rollout Source = "rollout"
 	// FeatureTest - the decision came from a feature test
 	FeatureTest Source = "feature-test"
+	// Holdout - the user is held out of every experiment and rollout by a global holdout
+	Holdout Source = "holdout"
+	// LocalHoldout - the user is held out of every experiment and rollout by a global holdout of the local policy
+	LocalHoldout Source = "local-holdout"
+	// ForcedDecision - the variation was forced for the whole flag through the user context, no rule was evaluated
+	ForcedDecision Source = "forced-decision"
 )
 
 // Decision contains base information about a decision
@@ -71,6 +84,8 @@ type FeatureDecision struct {
 type ExperimentDecision struct {
 	Decision
 	Variation *entities.Variation
+	// Holdout is the decision of the global holdout the user is held out by, if any, the user then gets no Variation
+	Holdout *FeatureDecision
 }
 
 // UserDecisionKey is used to access the saved decisions in a user profile
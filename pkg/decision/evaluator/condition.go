@@ -76,12 +76,23 @@ func NewAudienceConditionEvaluator(logger logging.OptimizelyLogProducer) *Audien
 
 // Evaluate returns true if the given user's attributes match the condition
 func (c AudienceConditionEvaluator) Evaluate(audienceID string, condTreeParams *entities.TreeParameters) (bool, error) {
+	return c.evaluate(audienceID, condTreeParams, nil)
+}
+
+// evaluate evaluates the audience, recording the evaluation of its condition tree in the trace unless it is nil
+func (c AudienceConditionEvaluator) evaluate(audienceID string, condTreeParams *entities.TreeParameters, trace *entities.TreeNodeTrace) (bool, error) {
+	if trace != nil {
+		trace.AudienceID = audienceID
+	}
 
 	if audience, ok := condTreeParams.AudienceMap[audienceID]; ok {
 		c.logger.Debug(fmt.Sprintf(logging.AudienceEvaluationStarted.String(), audienceID))
+		if trace != nil {
+			trace.AudienceName = audience.Name
+		}
 		condTree := audience.ConditionTree
 		conditionTreeEvaluator := NewMixedTreeEvaluator(c.logger)
-		retValue, isValid := conditionTreeEvaluator.Evaluate(condTree, condTreeParams)
+		retValue, isValid := conditionTreeEvaluator.evaluate(condTree, condTreeParams, childTrace(trace))
 		if !isValid {
 			return false, fmt.Errorf(`an error occurred while evaluating nested tree for audience ID "%s"`, audienceID)
 		}
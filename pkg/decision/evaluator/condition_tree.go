@@ -20,6 +20,7 @@ package evaluator
 import (
 	"fmt"
 
+	"github.com/optimizely/go-sdk/pkg/decision/evaluator/matchers"
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
 )
@@ -50,17 +51,46 @@ func NewMixedTreeEvaluator(logger logging.OptimizelyLogProducer) *MixedTreeEvalu
 	return &MixedTreeEvaluator{logger: logger}
 }
 
+// TracingTreeEvaluator is implemented by the tree evaluators which can trace the result of each node of a tree
+type TracingTreeEvaluator interface {
+	EvaluateWithTrace(*entities.TreeNode, *entities.TreeParameters) (evalResult, isValid bool, trace *entities.TreeNodeTrace)
+}
+
 // Evaluate returns whether the userAttributes satisfy the given condition tree and the evaluation of the condition is valid or not (to handle null bubbling)
 func (c MixedTreeEvaluator) Evaluate(node *entities.TreeNode, condTreeParams *entities.TreeParameters) (evalResult, isValid bool) {
+	return c.evaluate(node, condTreeParams, nil)
+}
+
+// EvaluateWithTrace does the same as Evaluate and also returns the trace of the result of each evaluated node, the
+// nodes skipped once the result of an operator is known are not part of the trace
+func (c MixedTreeEvaluator) EvaluateWithTrace(node *entities.TreeNode, condTreeParams *entities.TreeParameters) (evalResult, isValid bool, trace *entities.TreeNodeTrace) {
+	trace = &entities.TreeNodeTrace{}
+	evalResult, isValid = c.evaluate(node, condTreeParams, trace)
+	return evalResult, isValid, trace
+}
+
+// evaluate evaluates the node, recording its result in the trace unless it is nil
+func (c MixedTreeEvaluator) evaluate(node *entities.TreeNode, condTreeParams *entities.TreeParameters, trace *entities.TreeNodeTrace) (evalResult, isValid bool) {
+	evalResult, isValid = c.evaluateNode(node, condTreeParams, trace)
+	if trace != nil && isValid {
+		trace.Result = &evalResult
+	}
+	return evalResult, isValid
+}
+
+func (c MixedTreeEvaluator) evaluateNode(node *entities.TreeNode, condTreeParams *entities.TreeParameters, trace *entities.TreeNodeTrace) (evalResult, isValid bool) {
 	operator := node.Operator
 	if operator != "" {
+		if trace != nil {
+			trace.Operator = operator
+		}
 		switch operator {
 		case andOperator:
-			return c.evaluateAnd(node.Nodes, condTreeParams)
+			return c.evaluateAnd(node.Nodes, condTreeParams, trace)
 		case notOperator:
-			return c.evaluateNot(node.Nodes, condTreeParams)
+			return c.evaluateNot(node.Nodes, condTreeParams, trace)
 		default: // orOperator
-			return c.evaluateOr(node.Nodes, condTreeParams)
+			return c.evaluateOr(node.Nodes, condTreeParams, trace)
 		}
 	}
 
@@ -68,11 +98,14 @@ func (c MixedTreeEvaluator) Evaluate(node *entities.TreeNode, condTreeParams *en
 	var err error
 	switch v := node.Item.(type) {
 	case entities.Condition:
+		if trace != nil {
+			traceCondition(v, condTreeParams, trace)
+		}
 		evaluator := NewCustomAttributeConditionEvaluator(c.logger)
 		result, err = evaluator.Evaluate(node.Item.(entities.Condition), condTreeParams)
 	case string:
 		evaluator := NewAudienceConditionEvaluator(c.logger)
-		result, err = evaluator.Evaluate(node.Item.(string), condTreeParams)
+		result, err = evaluator.evaluate(node.Item.(string), condTreeParams, trace)
 	default:
 		fmt.Printf("I don't know about type %T!\n", v)
 		return false, false
@@ -85,10 +118,32 @@ func (c MixedTreeEvaluator) Evaluate(node *entities.TreeNode, condTreeParams *en
 	return result, true
 }
 
-func (c MixedTreeEvaluator) evaluateAnd(nodes []*entities.TreeNode, condTreeParams *entities.TreeParameters) (evalResult, isValid bool) {
+// childTrace returns the trace of the next child node of the trace, nil when the tree is not traced
+func childTrace(trace *entities.TreeNodeTrace) *entities.TreeNodeTrace {
+	if trace == nil {
+		return nil
+	}
+	child := &entities.TreeNodeTrace{}
+	trace.Nodes = append(trace.Nodes, child)
+	return child
+}
+
+func traceCondition(condition entities.Condition, condTreeParams *entities.TreeParameters, trace *entities.TreeNodeTrace) {
+	trace.AttributeName = condition.Name
+	trace.MatchType = condition.Match
+	if trace.MatchType == "" {
+		trace.MatchType = matchers.ExactMatchType
+	}
+	trace.ExpectedValue = condition.Value
+	if condTreeParams.User != nil {
+		trace.ActualValue = condTreeParams.User.Attributes[condition.Name]
+	}
+}
+
+func (c MixedTreeEvaluator) evaluateAnd(nodes []*entities.TreeNode, condTreeParams *entities.TreeParameters, trace *entities.TreeNodeTrace) (evalResult, isValid bool) {
 	sawInvalid := false
 	for _, node := range nodes {
-		result, isValid := c.Evaluate(node, condTreeParams)
+		result, isValid := c.evaluate(node, condTreeParams, childTrace(trace))
 		if !isValid {
 			return false, isValid
 		} else if !result {
@@ -104,9 +159,9 @@ func (c MixedTreeEvaluator) evaluateAnd(nodes []*entities.TreeNode, condTreePara
 	return true, true
 }
 
-func (c MixedTreeEvaluator) evaluateNot(nodes []*entities.TreeNode, condTreeParams *entities.TreeParameters) (evalResult, isValid bool) {
+func (c MixedTreeEvaluator) evaluateNot(nodes []*entities.TreeNode, condTreeParams *entities.TreeParameters, trace *entities.TreeNodeTrace) (evalResult, isValid bool) {
 	if len(nodes) > 0 {
-		result, isValid := c.Evaluate(nodes[0], condTreeParams)
+		result, isValid := c.evaluate(nodes[0], condTreeParams, childTrace(trace))
 		if !isValid {
 			return false, false
 		}
@@ -115,10 +170,10 @@ func (c MixedTreeEvaluator) evaluateNot(nodes []*entities.TreeNode, condTreePara
 	return false, false
 }
 
-func (c MixedTreeEvaluator) evaluateOr(nodes []*entities.TreeNode, condTreeParams *entities.TreeParameters) (evalResult, isValid bool) {
+func (c MixedTreeEvaluator) evaluateOr(nodes []*entities.TreeNode, condTreeParams *entities.TreeParameters, trace *entities.TreeNodeTrace) (evalResult, isValid bool) {
 	sawInvalid := false
 	for _, node := range nodes {
-		result, isValid := c.Evaluate(node, condTreeParams)
+		result, isValid := c.evaluate(node, condTreeParams, childTrace(trace))
 		if !isValid {
 			sawInvalid = true
 		} else if result {
@@ -492,6 +492,83 @@ func (s *ConditionTreeTestSuite) TestConditionTreeEvaluateAnAudienceTreeMultiple
 	s.mockLogger.AssertExpectations(s.T())
 }
 
+func (s *ConditionTreeTestSuite) TestConditionTreeEvaluateWithTrace() {
+	audienceTree := &e.TreeNode{
+		Operator: "or",
+		Nodes: []*e.TreeNode{
+			{
+				Item: audience11111.ID,
+			},
+			{
+				Item: audience11112.ID,
+			},
+		},
+	}
+
+	// Test audience 11111 is invalid and audience 11112 does not match
+	treeParams := &e.TreeParameters{
+		User: &e.UserContext{
+			ID: "test_user_1",
+			Attributes: map[string]interface{}{
+				"bool_true": true,
+				"int_42":    41,
+			},
+		},
+		AudienceMap: audienceMap,
+	}
+	s.mockLogger.On("Debug", mock.Anything)
+	result, isValid, trace := s.conditionTreeEvaluator.EvaluateWithTrace(audienceTree, treeParams)
+	s.False(result)
+	s.False(isValid)
+
+	trueResult, falseResult := true, false
+	expectedTrace := &e.TreeNodeTrace{
+		Operator: "or",
+		Nodes: []*e.TreeNodeTrace{
+			{
+				AudienceID: "11111",
+				Nodes: []*e.TreeNodeTrace{
+					{
+						Operator: "or",
+						Nodes: []*e.TreeNodeTrace{
+							{
+								Operator: "or",
+								Nodes: []*e.TreeNodeTrace{
+									{AttributeName: "string_foo", MatchType: "exact", ExpectedValue: "foo"},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				AudienceID: "11112",
+				Result:     &falseResult,
+				Nodes: []*e.TreeNodeTrace{
+					{
+						Operator: "or",
+						Result:   &falseResult,
+						Nodes: []*e.TreeNodeTrace{
+							{
+								Operator: "and",
+								Result:   &falseResult,
+								Nodes: []*e.TreeNodeTrace{
+									{AttributeName: "bool_true", MatchType: "exact", ExpectedValue: true, ActualValue: true, Result: &trueResult},
+									{AttributeName: "int_42", MatchType: "exact", ExpectedValue: 42, ActualValue: 41, Result: &falseResult},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.Equal(expectedTrace, trace)
+	s.Equal(`or(audience "11111" ""(or(or(string_foo exact "foo" (actual null) => null) => null) => null) => null, `+
+		`audience "11112" ""(or(and(bool_true exact true (actual true) => true, int_42 exact 42 (actual 41) => false) => false) => false) => false) => null`,
+		trace.String())
+}
+
 func TestConditionTreeTestSuite(t *testing.T) {
 	suite.Run(t, new(ConditionTreeTestSuite))
 }
@@ -20,6 +20,7 @@ package decision
 import (
 	"fmt"
 
+	"github.com/optimizely/go-sdk/pkg/decide"
 	"github.com/optimizely/go-sdk/pkg/decision/bucketer"
 	"github.com/optimizely/go-sdk/pkg/decision/evaluator"
 	"github.com/optimizely/go-sdk/pkg/decision/reasons"
@@ -49,14 +50,20 @@ func (s ExperimentBucketerService) GetDecision(decisionContext ExperimentDecisio
 	experimentDecision := ExperimentDecision{}
 	experiment := decisionContext.Experiment
 
+	if !experiment.IsRunning() {
+		s.logger.Debug(decisionContext.Reasons.AddInfo(`Experiment "%s" is not running, its status is "%s".`, experiment.Key, experiment.Status))
+		experimentDecision.Reason = reasons.ExperimentNotRunning
+		return experimentDecision, nil
+	}
+
 	// Determine if user can be part of the experiment
 	if experiment.AudienceConditionTree != nil {
 		condTreeParams := entities.NewTreeParameters(&userContext, decisionContext.ProjectConfig.GetAudienceMap())
 		s.logger.Debug(fmt.Sprintf(logging.EvaluatingAudiencesForExperiment.String(), experiment.Key))
-		evalResult, _ := s.audienceTreeEvaluator.Evaluate(experiment.AudienceConditionTree, condTreeParams)
-		s.logger.Debug(fmt.Sprintf(logging.ExperimentAudiencesEvaluatedTo.String(), experiment.Key, evalResult))
+		evalResult, _ := evaluateAudienceTree(s.audienceTreeEvaluator, experiment.Key, experiment.AudienceConditionTree, condTreeParams, decisionContext.Reasons)
+		s.logger.Debug(decisionContext.Reasons.AddInfo(logging.ExperimentAudiencesEvaluatedTo.String(), experiment.Key, evalResult))
 		if !evalResult {
-			s.logger.Debug(fmt.Sprintf(logging.UserNotInExperiment.String(), userContext.ID, experiment.Key))
+			s.logger.Debug(decisionContext.Reasons.AddInfo(logging.UserNotInExperiment.String(), userContext.ID, experiment.Key))
 			experimentDecision.Reason = reasons.FailedAudienceTargeting
 			return experimentDecision, nil
 		}
@@ -80,7 +87,21 @@ func (s ExperimentBucketerService) GetDecision(decisionContext ExperimentDecisio
 	}
 	// @TODO: handle error from bucketer
 	variation, reason, _ := s.bucketer.Bucket(bucketingID, *experiment, group)
+	decisionContext.Reasons.AddInfo(`User "%s" bucketing result for experiment "%s": %s.`, userContext.ID, experiment.Key, reason)
 	experimentDecision.Reason = reason
 	experimentDecision.Variation = variation
 	return experimentDecision, nil
 }
+
+// evaluateAudienceTree evaluates the audience condition tree of the given rule, the trace of the evaluation is added to
+// the decision reasons when they collect audience traces
+func evaluateAudienceTree(treeEvaluator evaluator.TreeEvaluator, ruleKey string, conditionTree *entities.TreeNode,
+	condTreeParams *entities.TreeParameters, decisionReasons *decide.DecisionReasons) (evalResult, isValid bool) {
+	if tracingEvaluator, ok := treeEvaluator.(evaluator.TracingTreeEvaluator); ok && decisionReasons.IncludesAudienceTraces() {
+		var trace *entities.TreeNodeTrace
+		evalResult, isValid, trace = tracingEvaluator.EvaluateWithTrace(conditionTree, condTreeParams)
+		decisionReasons.AddAudienceTrace(ruleKey, trace)
+		return evalResult, isValid
+	}
+	return treeEvaluator.Evaluate(conditionTree, condTreeParams)
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/optimizely/go-sdk/pkg/decide"
+	"github.com/optimizely/go-sdk/pkg/decision/evaluator"
 	"github.com/optimizely/go-sdk/pkg/decision/reasons"
 	"github.com/optimizely/go-sdk/pkg/logging"
 
@@ -166,6 +168,66 @@ func (s *ExperimentBucketerTestSuite) TestGetDecisionWithTargetingFails() {
 
 }
 
+func (s *ExperimentBucketerTestSuite) TestGetDecisionWithTargetingTrace() {
+	testUserContext := entities.UserContext{
+		ID:         "test_user_1",
+		Attributes: map[string]interface{}{"age": 15},
+	}
+	testExperiment := testTargetedExp1116
+	testExperiment.AudienceConditionTree = &entities.TreeNode{Operator: "or", Nodes: []*entities.TreeNode{{Item: "7771"}}}
+	ageCondition := entities.Condition{Name: "age", Match: "gt", Type: "custom_attribute", Value: 17}
+	s.mockConfig.On("GetAudienceMap").Return(map[string]entities.Audience{
+		"7771": {ID: "7771", Name: "adults", ConditionTree: &entities.TreeNode{Operator: "or", Nodes: []*entities.TreeNode{{Item: ageCondition}}}},
+	})
+	s.mockLogger.On("Debug", mock.Anything)
+	experimentBucketerService := ExperimentBucketerService{
+		audienceTreeEvaluator: evaluator.NewMixedTreeEvaluator(s.mockLogger),
+		logger:                s.mockLogger,
+		bucketer:              s.mockBucketer,
+	}
+
+	decisionReasons := decide.NewDecisionReasons(&decide.Options{IncludeAudienceTrace: true})
+	testDecisionContext := ExperimentDecisionContext{
+		Experiment:    &testExperiment,
+		ProjectConfig: s.mockConfig,
+		Reasons:       decisionReasons,
+	}
+	decision, err := experimentBucketerService.GetDecision(testDecisionContext, testUserContext)
+	s.NoError(err)
+	s.Equal(reasons.FailedAudienceTargeting, decision.Reason)
+	if s.Len(decisionReasons.AudienceTraces(), 1) {
+		audienceTrace := decisionReasons.AudienceTraces()[0]
+		s.Equal(testTargetedExp1116Key, audienceTrace.RuleKey)
+		s.Equal(`or(audience "7771" "adults"(or(age gt 17 (actual 15) => false) => false) => false) => false`, audienceTrace.Trace.String())
+	}
+	s.mockBucketer.AssertNotCalled(s.T(), "Bucket")
+}
+
+func (s *ExperimentBucketerTestSuite) TestGetDecisionExperimentNotRunning() {
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+
+	for _, status := range []entities.ExperimentStatus{entities.ExperimentStatusPaused, entities.ExperimentStatusNotStarted, entities.ExperimentStatusArchived, entities.ExperimentStatusLaunched} {
+		experiment := testExp1111
+		experiment.Status = status
+		testDecisionContext := ExperimentDecisionContext{
+			Experiment:    &experiment,
+			ProjectConfig: s.mockConfig,
+		}
+		s.mockLogger.On("Debug", fmt.Sprintf(`Experiment "test_experiment_1111" is not running, its status is "%s".`, status))
+		experimentBucketerService := ExperimentBucketerService{
+			bucketer: s.mockBucketer,
+			logger:   s.mockLogger,
+		}
+		decision, err := experimentBucketerService.GetDecision(testDecisionContext, testUserContext)
+		s.Equal(ExperimentDecision{Decision: Decision{Reason: reasons.ExperimentNotRunning}}, decision)
+		s.NoError(err)
+	}
+	s.mockBucketer.AssertNotCalled(s.T(), "Bucket", mock.Anything, mock.Anything, mock.Anything)
+	s.mockLogger.AssertExpectations(s.T())
+}
+
 func TestExperimentBucketerTestSuite(t *testing.T) {
 	suite.Run(t, new(ExperimentBucketerTestSuite))
 }
@@ -19,7 +19,6 @@ package decision
 
 import (
 	"errors"
-	"fmt"
 	"sync"
 
 	"github.com/optimizely/go-sdk/pkg/decision/reasons"
@@ -107,7 +106,7 @@ func (s ExperimentOverrideService) GetDecision(decisionContext ExperimentDecisio
 		if variation, ok := decisionContext.Experiment.Variations[variationID]; ok {
 			decision.Variation = &variation
 			decision.Reason = reasons.OverrideVariationAssignmentFound
-			s.logger.Debug(fmt.Sprintf("Override variation %v found for user %v", variationKey, userContext.ID))
+			s.logger.Debug(decisionContext.Reasons.AddInfo("Override variation %v found for user %v", variationKey, userContext.ID))
 			return decision, nil
 		}
 	}
@@ -51,6 +51,7 @@ func (s ExperimentWhitelistService) GetDecision(decisionContext ExperimentDecisi
 		if variation, ok := decisionContext.Experiment.Variations[id]; ok {
 			decision.Reason = reasons.WhitelistVariationAssignmentFound
 			decision.Variation = &variation
+			decisionContext.Reasons.AddInfo(`User "%s" is forced in variation "%s" of experiment "%s".`, userContext.ID, variationKey, decisionContext.Experiment.Key)
 			return decision, nil
 		}
 	}
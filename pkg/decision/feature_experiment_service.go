@@ -18,8 +18,9 @@
 package decision
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
 )
@@ -40,17 +41,34 @@ func NewFeatureExperimentService(logger logging.OptimizelyLogProducer, composite
 
 // GetDecision returns a decision for the given feature test and user context
 func (f FeatureExperimentService) GetDecision(decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	return f.GetDecisionWithContext(context.Background(), decisionContext, userContext)
+}
+
+// GetDecisionWithContext returns a decision for the given feature test and user context, bound to the given context
+func (f FeatureExperimentService) GetDecisionWithContext(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
 	feature := decisionContext.Feature
 	// @TODO this can be improved by getting group ID first and determining experiment and then bucketing in experiment
 	for _, featureExperiment := range feature.FeatureExperiments {
 		experiment := featureExperiment
+		// forced decisions take precedence over the user profile and bucketing
+		if forcedDecision, ok := decisionContext.ForcedDecisionService.FindValidatedForcedDecision(feature, &experiment, decisionContext.Reasons); ok {
+			return forcedDecision, nil
+		}
+
+		if !experiment.IsRunning() {
+			f.logger.Debug(decisionContext.Reasons.AddInfo(`Skipping feature test "%s" for feature "%s": %s.`, experiment.Key, feature.Key, reasons.ExperimentNotRunning))
+			continue
+		}
+
 		experimentDecisionContext := ExperimentDecisionContext{
 			Experiment:    &experiment,
 			ProjectConfig: decisionContext.ProjectConfig,
+			Options:       decisionContext.Options,
+			Reasons:       decisionContext.Reasons,
 		}
 
-		experimentDecision, err := f.compositeExperimentService.GetDecision(experimentDecisionContext, userContext)
-		f.logger.Debug(fmt.Sprintf(
+		experimentDecision, err := getExperimentDecision(ctx, f.compositeExperimentService, experimentDecisionContext, userContext)
+		f.logger.Debug(decisionContext.Reasons.AddInfo(
 			`Decision made for feature test with key "%s" for user "%s" with the following reason: "%s".`,
 			feature.Key,
 			userContext.ID,
@@ -19,6 +19,7 @@ package decision
 import (
 	"testing"
 
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
 
@@ -112,6 +113,78 @@ func (s *FeatureExperimentServiceTestSuite) TestGetDecisionMutex() {
 	s.mockExperimentService.AssertExpectations(s.T())
 }
 
+func (s *FeatureExperimentServiceTestSuite) TestGetDecisionWithForcedDecision() {
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+
+	// the forced decision for the second experiment is returned without bucketing into it
+	nilDecision := ExperimentDecision{}
+	testExperimentDecisionContext1 := ExperimentDecisionContext{
+		Experiment:    &testExp1113,
+		ProjectConfig: s.mockConfig,
+	}
+	s.mockExperimentService.On("GetDecision", testExperimentDecisionContext1, testUserContext).Return(nilDecision, nil)
+
+	forcedDecisionService := NewForcedDecisionService(testUserContext.ID)
+	forcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: testFeat3335Key, RuleKey: testExp1114Key}, OptimizelyForcedDecision{VariationKey: "2226"})
+	featureDecisionContext := s.testFeatureDecisionContext
+	featureDecisionContext.ForcedDecisionService = forcedDecisionService
+
+	expectedFeatureDecision := FeatureDecision{
+		Decision:   Decision{Reason: reasons.ForcedDecisionFound},
+		Experiment: testExp1114,
+		Variation:  &testExp1114Var2226,
+		Source:     FeatureTest,
+	}
+	featureExperimentService := &FeatureExperimentService{
+		compositeExperimentService: s.mockExperimentService,
+		logger:                     logging.GetLogger("sdkKey", "FeatureExperimentService"),
+	}
+	decision, err := featureExperimentService.GetDecision(featureDecisionContext, testUserContext)
+	s.Equal(expectedFeatureDecision, decision)
+	s.NoError(err)
+	s.mockExperimentService.AssertExpectations(s.T())
+	s.mockExperimentService.AssertNumberOfCalls(s.T(), "GetDecision", 1)
+}
+
+func (s *FeatureExperimentServiceTestSuite) TestGetDecisionSkipsExperimentNotRunning() {
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+
+	pausedExperiment := testExp1113
+	pausedExperiment.Status = entities.ExperimentStatusPaused
+	runningExperiment := testExp1114
+	runningExperiment.Status = entities.ExperimentStatusRunning
+	feature := testFeat3335
+	feature.FeatureExperiments = []entities.Experiment{pausedExperiment, runningExperiment}
+	featureDecisionContext := s.testFeatureDecisionContext
+	featureDecisionContext.Feature = &feature
+
+	expectedVariation := runningExperiment.Variations["2225"]
+	testExperimentDecisionContext := ExperimentDecisionContext{
+		Experiment:    &runningExperiment,
+		ProjectConfig: s.mockConfig,
+	}
+	s.mockExperimentService.On("GetDecision", testExperimentDecisionContext, testUserContext).Return(ExperimentDecision{Variation: &expectedVariation}, nil)
+
+	featureExperimentService := &FeatureExperimentService{
+		compositeExperimentService: s.mockExperimentService,
+		logger:                     logging.GetLogger("sdkKey", "FeatureExperimentService"),
+	}
+	expectedFeatureDecision := FeatureDecision{
+		Experiment: runningExperiment,
+		Variation:  &expectedVariation,
+		Source:     FeatureTest,
+	}
+	decision, err := featureExperimentService.GetDecision(featureDecisionContext, testUserContext)
+	s.Equal(expectedFeatureDecision, decision)
+	s.NoError(err)
+	s.mockExperimentService.AssertExpectations(s.T())
+	s.mockExperimentService.AssertNumberOfCalls(s.T(), "GetDecision", 1)
+}
+
 func (s *FeatureExperimentServiceTestSuite) TestNewFeatureExperimentService() {
 	compositeExperimentService := &CompositeExperimentService{logger:logging.GetLogger("sdkKey", "CompositeExperimentService")}
 	featureExperimentService := NewFeatureExperimentService(logging.GetLogger("", ""), compositeExperimentService)
@@ -68,3 +68,29 @@ func FeatureNotification(featureKey string, featureDecision *FeatureDecision, us
 	}
 	return decisionNotification
 }
+
+// FlagNotification constructs default flag notification
+func FlagNotification(flagKey, variationKey, ruleKey string, enabled, decisionEventDispatched bool, userContext entities.UserContext,
+	variables map[string]interface{}, reasons []string) *notification.DecisionNotification {
+
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	decisionInfo := map[string]interface{}{
+		"flagKey":                 flagKey,
+		"enabled":                 enabled,
+		"variables":               variables,
+		"variationKey":            variationKey,
+		"ruleKey":                 ruleKey,
+		"reasons":                 reasons,
+		"decisionEventDispatched": decisionEventDispatched,
+	}
+
+	decisionNotification := &notification.DecisionNotification{
+		DecisionInfo: decisionInfo,
+		Type:         notification.Flag,
+		UserContext:  userContext,
+	}
+	return decisionNotification
+}
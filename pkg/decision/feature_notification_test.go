@@ -58,3 +58,14 @@ func TestFeatureNotificationWithVariables(t *testing.T) {
 	assert.NotNil(t, decision)
 	assert.Equal(t, expectedDecision, decision)
 }
+
+func TestFlagNotification(t *testing.T) {
+	userContext := entities.UserContext{ID: "test_user"}
+	variables := map[string]interface{}{"var_key": "some_value"}
+	decision := FlagNotification("flag_key", "variation_key", "rule_key", true, true, userContext, variables, nil)
+
+	expectedDecision := &notification.DecisionNotification{Type: notification.Flag, UserContext: userContext,
+		DecisionInfo: map[string]interface{}{"flagKey": "flag_key", "enabled": true, "variables": variables, "variationKey": "variation_key",
+			"ruleKey": "rule_key", "reasons": []string{}, "decisionEventDispatched": true}}
+	assert.Equal(t, expectedDecision, decision)
+}
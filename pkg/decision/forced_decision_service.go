@@ -0,0 +1,173 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decision //
+package decision
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/optimizely/go-sdk/pkg/decide"
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
+	"github.com/optimizely/go-sdk/pkg/entities"
+)
+
+// OptimizelyDecisionContext identifies what a forced decision applies to: a whole flag, or a single
+// experiment or rollout rule of the flag when RuleKey is set
+type OptimizelyDecisionContext struct {
+	FlagKey string
+	RuleKey string
+}
+
+// OptimizelyForcedDecision contains the variation a flag or rule is forced to
+type OptimizelyForcedDecision struct {
+	VariationKey string
+}
+
+// ForcedDecisionService stores the forced decisions of a single user
+type ForcedDecisionService struct {
+	UserID          string
+	forcedDecisions map[OptimizelyDecisionContext]OptimizelyForcedDecision
+	mutex           *sync.RWMutex
+}
+
+// NewForcedDecisionService returns a new instance of the ForcedDecisionService
+func NewForcedDecisionService(userID string) *ForcedDecisionService {
+	return &ForcedDecisionService{
+		UserID:          userID,
+		forcedDecisions: map[OptimizelyDecisionContext]OptimizelyForcedDecision{},
+		mutex:           new(sync.RWMutex),
+	}
+}
+
+// SetForcedDecision forces the flag or rule of the given context to the given variation
+func (f *ForcedDecisionService) SetForcedDecision(context OptimizelyDecisionContext, decision OptimizelyForcedDecision) bool {
+	if context.FlagKey == "" {
+		return false
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.forcedDecisions[context] = decision
+	return true
+}
+
+// GetForcedDecision returns the forced decision for the given context
+func (f *ForcedDecisionService) GetForcedDecision(context OptimizelyDecisionContext) (OptimizelyForcedDecision, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	if decision, ok := f.forcedDecisions[context]; ok {
+		return decision, nil
+	}
+	return OptimizelyForcedDecision{}, errors.New("forced decision not found")
+}
+
+// RemoveForcedDecision removes the forced decision for the given context, returns false if none was set
+func (f *ForcedDecisionService) RemoveForcedDecision(context OptimizelyDecisionContext) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.forcedDecisions[context]; !ok {
+		return false
+	}
+	delete(f.forcedDecisions, context)
+	return true
+}
+
+// RemoveAllForcedDecisions removes all the forced decisions
+func (f *ForcedDecisionService) RemoveAllForcedDecisions() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.forcedDecisions = map[OptimizelyDecisionContext]OptimizelyForcedDecision{}
+	return true
+}
+
+// CreateCopy returns a copy of the service which is not affected by later changes to the original
+func (f *ForcedDecisionService) CreateCopy() *ForcedDecisionService {
+	if f == nil {
+		return nil
+	}
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	forcedDecisionService := NewForcedDecisionService(f.UserID)
+	for k, v := range f.forcedDecisions {
+		forcedDecisionService.forcedDecisions[k] = v
+	}
+	return forcedDecisionService
+}
+
+// FindValidatedForcedDecision returns a decision for the forced decision set on the given feature, or on the given rule
+// of the feature when rule is not nil. The second return value is false when no forced decision is set or when the
+// forced variation does not exist in the rules of the feature.
+// A forced decision for the whole flag is not attributed to any rule: it has the ForcedDecision source and no
+// experiment, so that no impression is sent for a rule the user was never bucketed into.
+func (f *ForcedDecisionService) FindValidatedForcedDecision(feature *entities.Feature, rule *entities.Experiment, decisionReasons *decide.DecisionReasons) (FeatureDecision, bool) {
+	if f == nil || feature == nil {
+		return FeatureDecision{}, false
+	}
+
+	ruleKey := ""
+	if rule != nil {
+		ruleKey = rule.Key
+	}
+
+	forcedDecision, err := f.GetForcedDecision(OptimizelyDecisionContext{FlagKey: feature.Key, RuleKey: ruleKey})
+	if err != nil {
+		return FeatureDecision{}, false
+	}
+
+	target := fmt.Sprintf(`flag "%s"`, feature.Key)
+	if ruleKey != "" {
+		target = fmt.Sprintf(`flag "%s", rule "%s"`, feature.Key, ruleKey)
+	}
+
+	findDecision := func(rules []entities.Experiment, source Source) (FeatureDecision, bool) {
+		for _, candidate := range rules {
+			if rule != nil && candidate.ID != rule.ID {
+				continue
+			}
+			for _, variation := range candidate.Variations {
+				if variation.Key == forcedDecision.VariationKey {
+					forcedVariation := variation
+					return FeatureDecision{
+						Decision:   Decision{Reason: reasons.ForcedDecisionFound},
+						Source:     source,
+						Experiment: candidate,
+						Variation:  &forcedVariation,
+					}, true
+				}
+			}
+		}
+		return FeatureDecision{}, false
+	}
+
+	featureDecision, ok := findDecision(feature.FeatureExperiments, FeatureTest)
+	if !ok {
+		featureDecision, ok = findDecision(feature.Rollout.Experiments, Rollout)
+	}
+	if !ok {
+		decisionReasons.AddInfo(`Invalid variation "%s" is mapped to %s and user "%s" in the forced decision map.`, forcedDecision.VariationKey, target, f.UserID)
+		return FeatureDecision{}, false
+	}
+
+	if rule == nil {
+		featureDecision.Source = ForcedDecision
+		featureDecision.Experiment = entities.Experiment{}
+	}
+
+	decisionReasons.AddInfo(`Variation "%s" is mapped to %s and user "%s" in the forced decision map.`, forcedDecision.VariationKey, target, f.UserID)
+	return featureDecision, true
+}
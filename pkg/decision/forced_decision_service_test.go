@@ -0,0 +1,120 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package decision
+
+import (
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/decide"
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetGetAndRemoveForcedDecision(t *testing.T) {
+	forcedDecisionService := NewForcedDecisionService("test_user")
+	flagContext := OptimizelyDecisionContext{FlagKey: testFeat3335Key}
+	ruleContext := OptimizelyDecisionContext{FlagKey: testFeat3335Key, RuleKey: testExp1113Key}
+
+	assert.False(t, forcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{}, OptimizelyForcedDecision{VariationKey: "2223"}))
+	assert.True(t, forcedDecisionService.SetForcedDecision(flagContext, OptimizelyForcedDecision{VariationKey: "2223"}))
+	assert.True(t, forcedDecisionService.SetForcedDecision(ruleContext, OptimizelyForcedDecision{VariationKey: "2224"}))
+
+	forcedDecision, err := forcedDecisionService.GetForcedDecision(flagContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "2223", forcedDecision.VariationKey)
+	forcedDecision, err = forcedDecisionService.GetForcedDecision(ruleContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "2224", forcedDecision.VariationKey)
+
+	assert.True(t, forcedDecisionService.RemoveForcedDecision(flagContext))
+	assert.False(t, forcedDecisionService.RemoveForcedDecision(flagContext))
+	_, err = forcedDecisionService.GetForcedDecision(flagContext)
+	assert.Error(t, err)
+
+	assert.True(t, forcedDecisionService.RemoveAllForcedDecisions())
+	_, err = forcedDecisionService.GetForcedDecision(ruleContext)
+	assert.Error(t, err)
+}
+
+func TestForcedDecisionServiceCreateCopy(t *testing.T) {
+	var nilService *ForcedDecisionService
+	assert.Nil(t, nilService.CreateCopy())
+
+	forcedDecisionService := NewForcedDecisionService("test_user")
+	flagContext := OptimizelyDecisionContext{FlagKey: testFeat3335Key}
+	forcedDecisionService.SetForcedDecision(flagContext, OptimizelyForcedDecision{VariationKey: "2223"})
+
+	copiedService := forcedDecisionService.CreateCopy()
+	forcedDecisionService.RemoveAllForcedDecisions()
+
+	forcedDecision, err := copiedService.GetForcedDecision(flagContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "2223", forcedDecision.VariationKey)
+}
+
+func TestFindValidatedForcedDecisionForFlag(t *testing.T) {
+	forcedDecisionService := NewForcedDecisionService("test_user")
+	forcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: testFeat3335Key}, OptimizelyForcedDecision{VariationKey: "2227"})
+	decisionReasons := decide.NewDecisionReasons(&decide.Options{IncludeReasons: true})
+
+	featureDecision, ok := forcedDecisionService.FindValidatedForcedDecision(&testFeat3335, nil, decisionReasons)
+	assert.True(t, ok)
+	assert.Equal(t, FeatureDecision{
+		Decision:  Decision{Reason: reasons.ForcedDecisionFound},
+		Source:    ForcedDecision,
+		Variation: &testExp1115Var2227,
+	}, featureDecision)
+	assert.Equal(t, []string{`Variation "2227" is mapped to flag "test_feature_3335_key" and user "test_user" in the forced decision map.`}, decisionReasons.ToReport())
+}
+
+func TestFindValidatedForcedDecisionForRule(t *testing.T) {
+	forcedDecisionService := NewForcedDecisionService("test_user")
+	forcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: testFeat3335Key, RuleKey: testExp1114Key}, OptimizelyForcedDecision{VariationKey: "2226"})
+
+	// the forced decision of a rule does not apply to the flag or to other rules
+	_, ok := forcedDecisionService.FindValidatedForcedDecision(&testFeat3335, nil, nil)
+	assert.False(t, ok)
+	_, ok = forcedDecisionService.FindValidatedForcedDecision(&testFeat3335, &testExp1113, nil)
+	assert.False(t, ok)
+
+	featureDecision, ok := forcedDecisionService.FindValidatedForcedDecision(&testFeat3335, &testExp1114, nil)
+	assert.True(t, ok)
+	assert.Equal(t, FeatureDecision{
+		Decision:   Decision{Reason: reasons.ForcedDecisionFound},
+		Source:     FeatureTest,
+		Experiment: testExp1114,
+		Variation:  &testExp1114Var2226,
+	}, featureDecision)
+}
+
+func TestFindValidatedForcedDecisionWithInvalidVariation(t *testing.T) {
+	forcedDecisionService := NewForcedDecisionService("test_user")
+	forcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: testFeat3335Key, RuleKey: testExp1113Key}, OptimizelyForcedDecision{VariationKey: "2227"})
+	decisionReasons := decide.NewDecisionReasons(&decide.Options{IncludeReasons: true})
+
+	featureDecision, ok := forcedDecisionService.FindValidatedForcedDecision(&testFeat3335, &testExp1113, decisionReasons)
+	assert.False(t, ok)
+	assert.Equal(t, FeatureDecision{}, featureDecision)
+	assert.Equal(t, []string{`Invalid variation "2227" is mapped to flag "test_feature_3335_key", rule "test_experiment_1113" and user "test_user" in the forced decision map.`}, decisionReasons.ToReport())
+}
+
+func TestFindValidatedForcedDecisionWithNilService(t *testing.T) {
+	var forcedDecisionService *ForcedDecisionService
+	_, ok := forcedDecisionService.FindValidatedForcedDecision(&testFeat3335, nil, nil)
+	assert.False(t, ok)
+}
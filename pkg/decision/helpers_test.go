@@ -22,8 +22,11 @@
 package decision
 
 import (
+	"context"
+
 	"github.com/optimizely/go-sdk/pkg/config"
 	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/optimizely/go-sdk/pkg/notification"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -53,6 +56,35 @@ func (c *mockProjectConfig) GetAudienceMap() map[string]entities.Audience {
 	return args.Get(0).(map[string]entities.Audience)
 }
 
+func (c *mockProjectConfig) GetHoldoutList() []entities.Holdout {
+	args := c.Called()
+	return args.Get(0).([]entities.Holdout)
+}
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) GetFeatureDecision(decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	args := m.Called(decisionContext, userContext)
+	return args.Get(0).(FeatureDecision), args.Error(1)
+}
+
+func (m *MockService) GetExperimentDecision(decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error) {
+	args := m.Called(decisionContext, userContext)
+	return args.Get(0).(ExperimentDecision), args.Error(1)
+}
+
+func (m *MockService) OnDecision(callback func(notification.DecisionNotification)) (int, error) {
+	args := m.Called(callback)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockService) RemoveOnDecision(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 type MockExperimentDecisionService struct {
 	mock.Mock
 }
@@ -89,6 +121,29 @@ func (m *MockUserProfileService) Save(userProfile UserProfile) {
 	m.Called(userProfile)
 }
 
+type MockContextUserProfileService struct {
+	MockUserProfileService
+}
+
+func (m *MockContextUserProfileService) LookupWithContext(ctx context.Context, userID string) (UserProfile, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(UserProfile), args.Error(1)
+}
+
+func (m *MockContextUserProfileService) SaveWithContext(ctx context.Context, userProfile UserProfile) error {
+	args := m.Called(ctx, userProfile)
+	return args.Error(0)
+}
+
+type MockContextFeatureDecisionService struct {
+	MockFeatureDecisionService
+}
+
+func (m *MockContextFeatureDecisionService) GetDecisionWithContext(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	args := m.Called(ctx, decisionContext, userContext)
+	return args.Get(0).(FeatureDecision), args.Error(1)
+}
+
 func (m *MockAudienceTreeEvaluator) Evaluate(node *entities.TreeNode, condTreeParams *entities.TreeParameters) (evalResult, isValid bool) {
 	args := m.Called(node, condTreeParams)
 	return args.Bool(0), args.Bool(1)
@@ -0,0 +1,134 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decision //
+package decision
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/optimizely/go-sdk/pkg/config"
+	"github.com/optimizely/go-sdk/pkg/decide"
+	"github.com/optimizely/go-sdk/pkg/decision/bucketer"
+	"github.com/optimizely/go-sdk/pkg/decision/evaluator"
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/optimizely/go-sdk/pkg/logging"
+)
+
+// HoldoutService buckets users into the global holdouts of the datafile and of the local policy. The users it buckets
+// are held out of every experiment and rollout. The decisions of the local holdouts have the LocalHoldout source, their
+// impressions are sent like the ones of the holdouts of the datafile.
+type HoldoutService struct {
+	localHoldouts         []entities.Holdout
+	audienceTreeEvaluator evaluator.TreeEvaluator
+	bucketer              bucketer.Bucketer
+	logger                logging.OptimizelyLogProducer
+}
+
+// NewHoldoutService returns a new instance of the HoldoutService, the local holdouts are checked after the ones of the
+// datafile
+func NewHoldoutService(sdkKey string, localHoldouts ...entities.Holdout) *HoldoutService {
+	logger := logging.GetLogger(sdkKey, "HoldoutService")
+	return &HoldoutService{
+		localHoldouts:         localHoldouts,
+		audienceTreeEvaluator: evaluator.NewMixedTreeEvaluator(logger),
+		bucketer:              bucketer.NewMurmurhashBucketer(logger, bucketer.DefaultHashSeed),
+		logger:                logger,
+	}
+}
+
+// NewLocalHoldout returns a holdout defined outside of the datafile, holding out the given percentage of the users
+// rounded to a hundredth of a percent. Its ID is the experiment and campaign ID of its impressions, and its only
+// variation has the ID suffixed with "_off". It returns an error when the percentage is not between 0 and 100.
+func NewLocalHoldout(id, key string, percentage float64) (entities.Holdout, error) {
+	if !(percentage >= 0 && percentage <= 100) {
+		return entities.Holdout{}, fmt.Errorf(`invalid percentage %v of holdout "%s", it must be between 0 and 100`, percentage, key)
+	}
+	variation := entities.Variation{ID: id + "_off", Key: "off", FeatureEnabled: false}
+	return entities.Holdout{
+		ID:                id,
+		Key:               key,
+		Variations:        map[string]entities.Variation{variation.ID: variation},
+		TrafficAllocation: []entities.Range{{EntityID: variation.ID, EndOfRange: int(math.Round(percentage * 100))}},
+	}, nil
+}
+
+// GetDecision returns the decision of the holdout the user is bucketed into, if any. The flag defaults are served with
+// the variation of the holdout, its feature is never enabled.
+func (s HoldoutService) GetDecision(projectConfig config.ProjectConfig, userContext entities.UserContext,
+	decisionReasons *decide.DecisionReasons) (FeatureDecision, bool) {
+	if projectConfig != nil {
+		if featureDecision, ok := s.getHoldoutDecision(projectConfig.GetHoldoutList(), Holdout, projectConfig, userContext, decisionReasons); ok {
+			return featureDecision, true
+		}
+	}
+	return s.getHoldoutDecision(s.localHoldouts, LocalHoldout, projectConfig, userContext, decisionReasons)
+}
+
+// getHoldoutDecision returns the decision of the first of the given holdouts the user is bucketed into, if any
+func (s HoldoutService) getHoldoutDecision(holdouts []entities.Holdout, source Source, projectConfig config.ProjectConfig,
+	userContext entities.UserContext, decisionReasons *decide.DecisionReasons) (FeatureDecision, bool) {
+	for _, holdout := range holdouts {
+		if !holdout.IsRunning() {
+			continue
+		}
+
+		if holdout.AudienceConditionTree != nil && projectConfig != nil {
+			condTreeParams := entities.NewTreeParameters(&userContext, projectConfig.GetAudienceMap())
+			if evalResult, _ := evaluateAudienceTree(s.audienceTreeEvaluator, holdout.Key, holdout.AudienceConditionTree, condTreeParams, decisionReasons); !evalResult {
+				s.logger.Debug(fmt.Sprintf(`User "%s" does not meet the conditions of holdout "%s".`, userContext.ID, holdout.Key))
+				continue
+			}
+		}
+
+		bucketingID, err := userContext.GetBucketingID()
+		if err != nil {
+			s.logger.Debug(fmt.Sprintf(`Error computing bucketing ID for holdout "%s": "%s"`, holdout.Key, err.Error()))
+		}
+		variation, ok := holdout.Variations[s.bucketer.BucketToEntity(bucketingID+holdout.ID, holdout.TrafficAllocation)]
+		if !ok {
+			continue
+		}
+
+		// the variation is disabled whatever the datafile says, so that held out users get the flag defaults
+		variation.FeatureEnabled = false
+		s.logger.Debug(decisionReasons.AddInfo(`User "%s" is held out of every experiment and rollout by holdout "%s".`, userContext.ID, holdout.Key))
+		return FeatureDecision{
+			Decision:   Decision{Reason: reasons.HeldOut},
+			Source:     source,
+			Experiment: holdoutExperiment(holdout),
+			Variation:  &variation,
+		}, true
+	}
+
+	return FeatureDecision{}, false
+}
+
+// holdoutExperiment returns the holdout as the experiment of its impressions, the holdout is its own campaign
+func holdoutExperiment(holdout entities.Holdout) entities.Experiment {
+	return entities.Experiment{
+		ID:                    holdout.ID,
+		Key:                   holdout.Key,
+		LayerID:               holdout.ID,
+		AudienceIds:           holdout.AudienceIds,
+		AudienceConditionTree: holdout.AudienceConditionTree,
+		Variations:            holdout.Variations,
+		TrafficAllocation:     holdout.TrafficAllocation,
+		Status:                holdout.Status,
+	}
+}
@@ -0,0 +1,135 @@
+/****************************************************************************
+ * Copyright 2019, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package decision
+
+import (
+	"math"
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/decide"
+	"github.com/optimizely/go-sdk/pkg/decision/reasons"
+	"github.com/optimizely/go-sdk/pkg/entities"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HoldoutServiceTestSuite struct {
+	suite.Suite
+	mockConfig      *mockProjectConfig
+	testUserContext entities.UserContext
+}
+
+// newTestHoldout returns the local holdout "global_holdout" holding out the given percentage of the users
+func newTestHoldout(percentage float64) entities.Holdout {
+	holdout, err := NewLocalHoldout("holdout_1", "global_holdout", percentage)
+	if err != nil {
+		panic(err)
+	}
+	return holdout
+}
+
+func (s *HoldoutServiceTestSuite) SetupTest() {
+	s.mockConfig = new(mockProjectConfig)
+	s.testUserContext = entities.UserContext{ID: "test_user_1"}
+}
+
+func (s *HoldoutServiceTestSuite) TestGetDecisionWithLocalHoldout() {
+	s.mockConfig.On("GetHoldoutList").Return([]entities.Holdout{})
+	holdoutService := NewHoldoutService("", newTestHoldout(100))
+	decisionReasons := decide.NewDecisionReasons(&decide.Options{IncludeReasons: true})
+
+	decision, ok := holdoutService.GetDecision(s.mockConfig, s.testUserContext, decisionReasons)
+	s.True(ok)
+	s.Equal(LocalHoldout, decision.Source)
+	s.Equal(reasons.HeldOut, decision.Reason)
+	s.Equal("holdout_1", decision.Experiment.ID)
+	s.Equal("global_holdout", decision.Experiment.Key)
+	s.Equal(&entities.Variation{ID: "holdout_1_off", Key: "off"}, decision.Variation)
+	s.Equal([]string{`User "test_user_1" is held out of every experiment and rollout by holdout "global_holdout".`}, decisionReasons.ToReport())
+}
+
+func (s *HoldoutServiceTestSuite) TestGetDecisionNotHeldOut() {
+	s.mockConfig.On("GetHoldoutList").Return([]entities.Holdout{})
+	holdoutService := NewHoldoutService("", newTestHoldout(0))
+
+	decision, ok := holdoutService.GetDecision(s.mockConfig, s.testUserContext, nil)
+	s.False(ok)
+	s.Nil(decision.Variation)
+}
+
+func (s *HoldoutServiceTestSuite) TestGetDecisionChecksDatafileHoldoutsFirst() {
+	datafileHoldout := entities.Holdout{
+		ID:                "holdout_2",
+		Key:               "datafile_holdout",
+		Status:            entities.ExperimentStatusRunning,
+		Variations:        map[string]entities.Variation{"off_2": {ID: "off_2", Key: "off", FeatureEnabled: true}},
+		TrafficAllocation: []entities.Range{{EntityID: "off_2", EndOfRange: 10000}},
+	}
+	s.mockConfig.On("GetHoldoutList").Return([]entities.Holdout{datafileHoldout})
+	holdoutService := NewHoldoutService("", newTestHoldout(100))
+
+	decision, ok := holdoutService.GetDecision(s.mockConfig, s.testUserContext, nil)
+	s.True(ok)
+	s.Equal(Holdout, decision.Source)
+	s.Equal("holdout_2", decision.Experiment.ID)
+	// held out users get the flag defaults whatever the variation of the holdout
+	s.Equal(&entities.Variation{ID: "off_2", Key: "off"}, decision.Variation)
+}
+
+func (s *HoldoutServiceTestSuite) TestGetDecisionSkipsHoldoutsNotRunning() {
+	pausedHoldout := newTestHoldout(100)
+	pausedHoldout.Status = entities.ExperimentStatusPaused
+	s.mockConfig.On("GetHoldoutList").Return([]entities.Holdout{pausedHoldout})
+	holdoutService := NewHoldoutService("")
+
+	_, ok := holdoutService.GetDecision(s.mockConfig, s.testUserContext, nil)
+	s.False(ok)
+}
+
+func (s *HoldoutServiceTestSuite) TestGetDecisionEvaluatesAudiences() {
+	holdout := newTestHoldout(100)
+	holdout.AudienceConditionTree = &entities.TreeNode{
+		Operator: "or",
+		Nodes:    []*entities.TreeNode{{Item: "audience_1"}},
+	}
+	s.mockConfig.On("GetHoldoutList").Return([]entities.Holdout{holdout})
+	s.mockConfig.On("GetAudienceMap").Return(map[string]entities.Audience{})
+	holdoutService := NewHoldoutService("")
+
+	_, ok := holdoutService.GetDecision(s.mockConfig, s.testUserContext, nil)
+	s.False(ok)
+	s.mockConfig.AssertExpectations(s.T())
+}
+
+func (s *HoldoutServiceTestSuite) TestNewLocalHoldout() {
+	holdout, err := NewLocalHoldout("holdout_1", "global_holdout", 0.29)
+	s.NoError(err)
+	s.Equal([]entities.Range{{EntityID: "holdout_1_off", EndOfRange: 29}}, holdout.TrafficAllocation)
+
+	holdout, err = NewLocalHoldout("holdout_1", "global_holdout", 100)
+	s.NoError(err)
+	s.Equal(10000, holdout.TrafficAllocation[0].EndOfRange)
+
+	for _, percentage := range []float64{-1, 100.01, math.NaN()} {
+		_, err = NewLocalHoldout("holdout_1", "global_holdout", percentage)
+		s.Error(err)
+	}
+}
+
+func TestHoldoutServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(HoldoutServiceTestSuite))
+}
@@ -0,0 +1,126 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decision //
+package decision
+
+import (
+	"context"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/optimizely/go-sdk/pkg/notification"
+)
+
+// FeatureDecisionFunc evaluates a feature decision
+type FeatureDecisionFunc func(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error)
+
+// ExperimentDecisionFunc evaluates an experiment decision
+type ExperimentDecisionFunc func(ctx context.Context, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error)
+
+// Hook wraps the evaluation of feature and experiment decisions. A hook can change the decision context or the user
+// before calling next, change or replace the decision returned by next, or return its own decision without calling
+// next at all.
+type Hook interface {
+	WrapFeatureDecision(next FeatureDecisionFunc) FeatureDecisionFunc
+	WrapExperimentDecision(next ExperimentDecisionFunc) ExperimentDecisionFunc
+}
+
+// HookFuncs is a Hook made of functions, a nil function leaves the corresponding evaluation untouched
+type HookFuncs struct {
+	Feature    func(next FeatureDecisionFunc) FeatureDecisionFunc
+	Experiment func(next ExperimentDecisionFunc) ExperimentDecisionFunc
+}
+
+// WrapFeatureDecision wraps the feature evaluation with the Feature function
+func (h HookFuncs) WrapFeatureDecision(next FeatureDecisionFunc) FeatureDecisionFunc {
+	if h.Feature == nil {
+		return next
+	}
+	return h.Feature(next)
+}
+
+// WrapExperimentDecision wraps the experiment evaluation with the Experiment function
+func (h HookFuncs) WrapExperimentDecision(next ExperimentDecisionFunc) ExperimentDecisionFunc {
+	if h.Experiment == nil {
+		return next
+	}
+	return h.Experiment(next)
+}
+
+// HookService runs the decisions of the wrapped Service through an ordered chain of hooks, the first hook being the
+// outermost one
+type HookService struct {
+	service            Service
+	featureDecision    FeatureDecisionFunc
+	experimentDecision ExperimentDecisionFunc
+}
+
+// NewHookService returns a new instance of the HookService wrapping the given service with the given hooks
+func NewHookService(service Service, hooks ...Hook) *HookService {
+	featureDecision := func(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+		if contextService, ok := service.(ContextService); ok {
+			return contextService.GetFeatureDecisionWithContext(ctx, decisionContext, userContext)
+		}
+		return service.GetFeatureDecision(decisionContext, userContext)
+	}
+	experimentDecision := func(ctx context.Context, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error) {
+		if contextService, ok := service.(ContextService); ok {
+			return contextService.GetExperimentDecisionWithContext(ctx, decisionContext, userContext)
+		}
+		return service.GetExperimentDecision(decisionContext, userContext)
+	}
+
+	hookService := &HookService{
+		service:            service,
+		featureDecision:    featureDecision,
+		experimentDecision: experimentDecision,
+	}
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hookService.featureDecision = hooks[i].WrapFeatureDecision(hookService.featureDecision)
+		hookService.experimentDecision = hooks[i].WrapExperimentDecision(hookService.experimentDecision)
+	}
+	return hookService
+}
+
+// GetFeatureDecision returns a decision for the given feature key
+func (s HookService) GetFeatureDecision(featureDecisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	return s.GetFeatureDecisionWithContext(context.Background(), featureDecisionContext, userContext)
+}
+
+// GetFeatureDecisionWithContext returns a decision for the given feature key, bound to the given context
+func (s HookService) GetFeatureDecisionWithContext(ctx context.Context, featureDecisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+	return s.featureDecision(ctx, featureDecisionContext, userContext)
+}
+
+// GetExperimentDecision returns a decision for the given experiment key
+func (s HookService) GetExperimentDecision(experimentDecisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error) {
+	return s.GetExperimentDecisionWithContext(context.Background(), experimentDecisionContext, userContext)
+}
+
+// GetExperimentDecisionWithContext returns a decision for the given experiment key, bound to the given context
+func (s HookService) GetExperimentDecisionWithContext(ctx context.Context, experimentDecisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error) {
+	return s.experimentDecision(ctx, experimentDecisionContext, userContext)
+}
+
+// OnDecision registers a handler for Decision notifications on the wrapped service
+func (s HookService) OnDecision(callback func(notification.DecisionNotification)) (int, error) {
+	return s.service.OnDecision(callback)
+}
+
+// RemoveOnDecision removes handler for Decision notification with given id on the wrapped service
+func (s HookService) RemoveOnDecision(id int) error {
+	return s.service.RemoveOnDecision(id)
+}
@@ -0,0 +1,180 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package decision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/optimizely/go-sdk/pkg/entities"
+	"github.com/optimizely/go-sdk/pkg/notification"
+)
+
+type HookServiceTestSuite struct {
+	suite.Suite
+	featureDecisionContext    FeatureDecisionContext
+	experimentDecisionContext ExperimentDecisionContext
+	mockService               *MockService
+	testUserContext           entities.UserContext
+}
+
+func (s *HookServiceTestSuite) SetupTest() {
+	mockConfig := new(mockProjectConfig)
+	s.featureDecisionContext = FeatureDecisionContext{
+		Feature:       &testFeat3333,
+		ProjectConfig: mockConfig,
+	}
+	s.experimentDecisionContext = ExperimentDecisionContext{
+		Experiment:    &testExp1111,
+		ProjectConfig: mockConfig,
+	}
+	s.mockService = new(MockService)
+	s.testUserContext = entities.UserContext{
+		ID: "test_user",
+	}
+}
+
+func (s *HookServiceTestSuite) TestHooksRunInOrder() {
+	var calls []string
+	recordingHook := func(name string) Hook {
+		return HookFuncs{
+			Feature: func(next FeatureDecisionFunc) FeatureDecisionFunc {
+				return func(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+					calls = append(calls, "before "+name)
+					featureDecision, err := next(ctx, decisionContext, userContext)
+					calls = append(calls, "after "+name)
+					return featureDecision, err
+				}
+			},
+		}
+	}
+	expectedFeatureDecision := FeatureDecision{
+		Experiment: testExp1111,
+		Variation:  &testExp1111Var2222,
+	}
+	s.mockService.On("GetFeatureDecision", s.featureDecisionContext, s.testUserContext).Return(expectedFeatureDecision, nil)
+
+	hookService := NewHookService(s.mockService, recordingHook("first"), recordingHook("second"))
+	featureDecision, err := hookService.GetFeatureDecision(s.featureDecisionContext, s.testUserContext)
+
+	s.NoError(err)
+	s.Equal(expectedFeatureDecision, featureDecision)
+	s.Equal([]string{"before first", "before second", "after second", "after first"}, calls)
+	s.mockService.AssertExpectations(s.T())
+}
+
+func (s *HookServiceTestSuite) TestHookChangesUserContext() {
+	addAttribute := HookFuncs{
+		Feature: func(next FeatureDecisionFunc) FeatureDecisionFunc {
+			return func(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+				userContext.Attributes = map[string]interface{}{"plan": "enterprise"}
+				return next(ctx, decisionContext, userContext)
+			}
+		},
+	}
+	expectedUserContext := entities.UserContext{
+		ID:         "test_user",
+		Attributes: map[string]interface{}{"plan": "enterprise"},
+	}
+	s.mockService.On("GetFeatureDecision", s.featureDecisionContext, expectedUserContext).Return(FeatureDecision{}, nil)
+
+	hookService := NewHookService(s.mockService, addAttribute)
+	_, err := hookService.GetFeatureDecision(s.featureDecisionContext, s.testUserContext)
+
+	s.NoError(err)
+	s.mockService.AssertExpectations(s.T())
+}
+
+func (s *HookServiceTestSuite) TestHookVetoesFeatureDecision() {
+	veto := HookFuncs{
+		Feature: func(next FeatureDecisionFunc) FeatureDecisionFunc {
+			return func(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+				return FeatureDecision{Decision: Decision{Reason: "blocked account"}}, nil
+			}
+		},
+	}
+
+	hookService := NewHookService(s.mockService, veto)
+	featureDecision, err := hookService.GetFeatureDecision(s.featureDecisionContext, s.testUserContext)
+
+	s.NoError(err)
+	s.Equal(FeatureDecision{Decision: Decision{Reason: "blocked account"}}, featureDecision)
+	s.mockService.AssertNotCalled(s.T(), "GetFeatureDecision", mock.Anything, mock.Anything)
+}
+
+func (s *HookServiceTestSuite) TestHookReplacesExperimentDecision() {
+	testVariation := entities.Variation{ID: "3333", Key: "3333"}
+	replace := HookFuncs{
+		Experiment: func(next ExperimentDecisionFunc) ExperimentDecisionFunc {
+			return func(ctx context.Context, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error) {
+				experimentDecision, err := next(ctx, decisionContext, userContext)
+				experimentDecision.Variation = &testVariation
+				return experimentDecision, err
+			}
+		},
+	}
+	s.mockService.On("GetExperimentDecision", s.experimentDecisionContext, s.testUserContext).Return(ExperimentDecision{Variation: &testExp1111Var2222}, nil)
+
+	hookService := NewHookService(s.mockService, replace, HookFuncs{})
+	experimentDecision, err := hookService.GetExperimentDecision(s.experimentDecisionContext, s.testUserContext)
+
+	s.NoError(err)
+	s.Equal(&testVariation, experimentDecision.Variation)
+	s.mockService.AssertExpectations(s.T())
+}
+
+func (s *HookServiceTestSuite) TestContextIsPassedToHooksAndService() {
+	ctx := context.WithValue(context.Background(), testContextKey("request"), "123")
+	var hookCtx context.Context
+	recordContext := HookFuncs{
+		Feature: func(next FeatureDecisionFunc) FeatureDecisionFunc {
+			return func(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error) {
+				hookCtx = ctx
+				return next(ctx, decisionContext, userContext)
+			}
+		},
+	}
+	mockFeatureService := new(MockContextFeatureDecisionService)
+	mockFeatureService.On("GetDecisionWithContext", ctx, s.featureDecisionContext, s.testUserContext).Return(FeatureDecision{}, nil)
+	compositeService := &CompositeService{compositeFeatureService: mockFeatureService}
+
+	hookService := NewHookService(compositeService, recordContext)
+	_, err := hookService.GetFeatureDecisionWithContext(ctx, s.featureDecisionContext, s.testUserContext)
+
+	s.NoError(err)
+	s.Equal(ctx, hookCtx)
+	mockFeatureService.AssertExpectations(s.T())
+}
+
+func (s *HookServiceTestSuite) TestOnDecision() {
+	s.mockService.On("OnDecision", mock.Anything).Return(1, nil)
+	s.mockService.On("RemoveOnDecision", 1).Return(nil)
+
+	hookService := NewHookService(s.mockService)
+	id, err := hookService.OnDecision(func(notification.DecisionNotification) {})
+	s.NoError(err)
+	s.Equal(1, id)
+	s.NoError(hookService.RemoveOnDecision(id))
+	s.mockService.AssertExpectations(s.T())
+}
+
+func TestHookServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(HookServiceTestSuite))
+}
@@ -18,6 +18,8 @@
 package decision
 
 import (
+	"context"
+
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/notification"
 )
@@ -45,3 +47,30 @@ type UserProfileService interface {
 	Lookup(string) UserProfile
 	Save(UserProfile)
 }
+
+// ContextService is a Service which can also make decisions bound to a context.Context, for cancellation, deadlines
+// and request-scoped values
+type ContextService interface {
+	Service
+	GetFeatureDecisionWithContext(context.Context, FeatureDecisionContext, entities.UserContext) (FeatureDecision, error)
+	GetExperimentDecisionWithContext(context.Context, ExperimentDecisionContext, entities.UserContext) (ExperimentDecision, error)
+}
+
+// ContextExperimentService is an ExperimentService which can also make decisions bound to a context.Context
+type ContextExperimentService interface {
+	ExperimentService
+	GetDecisionWithContext(ctx context.Context, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, error)
+}
+
+// ContextFeatureService is a FeatureService which can also make decisions bound to a context.Context
+type ContextFeatureService interface {
+	FeatureService
+	GetDecisionWithContext(ctx context.Context, decisionContext FeatureDecisionContext, userContext entities.UserContext) (FeatureDecision, error)
+}
+
+// ContextUserProfileService is a UserProfileService whose backend calls can be cancelled or given a deadline
+type ContextUserProfileService interface {
+	UserProfileService
+	LookupWithContext(ctx context.Context, userID string) (UserProfile, error)
+	SaveWithContext(ctx context.Context, userProfile UserProfile) error
+}
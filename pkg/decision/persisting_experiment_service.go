@@ -18,6 +18,7 @@
 package decision
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/optimizely/go-sdk/pkg/entities"
@@ -46,57 +47,74 @@ func NewPersistingExperimentService(userProfileService UserProfileService, exper
 
 // GetDecision returns the decision with the variation the user is bucketed into
 func (p PersistingExperimentService) GetDecision(decisionContext ExperimentDecisionContext, userContext entities.UserContext) (experimentDecision ExperimentDecision, err error) {
-	if p.userProfileService == nil {
-		return p.experimentBucketedService.GetDecision(decisionContext, userContext)
+	return p.GetDecisionWithContext(context.Background(), decisionContext, userContext)
+}
+
+// GetDecisionWithContext returns the decision with the variation the user is bucketed into. The user profile service
+// calls are bound to the given context: if it is done before the saved profile is returned, the decision is computed
+// without it and is not saved.
+func (p PersistingExperimentService) GetDecisionWithContext(ctx context.Context, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (experimentDecision ExperimentDecision, err error) {
+	if p.userProfileService == nil || decisionContext.Options.IgnoreUserProfileService {
+		return getExperimentDecision(ctx, p.experimentBucketedService, decisionContext, userContext)
 	}
 
 	var userProfile UserProfile
 	// check to see if there is a saved decision for the user
-	experimentDecision, userProfile = p.getSavedDecision(decisionContext, userContext)
+	experimentDecision, userProfile, err = p.getSavedDecision(ctx, decisionContext, userContext)
+	if err != nil {
+		p.logger.Warning(decisionContext.Reasons.AddInfo(`Unable to look up the user profile for user "%s": %v. Bucketing without it.`, userContext.ID, err))
+		return getExperimentDecision(ctx, p.experimentBucketedService, decisionContext, userContext)
+	}
 	if experimentDecision.Variation != nil {
 		return experimentDecision, nil
 	}
 
-	experimentDecision, err = p.experimentBucketedService.GetDecision(decisionContext, userContext)
+	experimentDecision, err = getExperimentDecision(ctx, p.experimentBucketedService, decisionContext, userContext)
 	if experimentDecision.Variation != nil {
 		// save decision if a user profile service is provided
 		userProfile.ID = userContext.ID
-		p.saveDecision(userProfile, decisionContext.Experiment, experimentDecision)
+		p.saveDecision(ctx, userProfile, decisionContext.Experiment, experimentDecision)
 	}
 
 	return experimentDecision, err
 }
 
-func (p PersistingExperimentService) getSavedDecision(decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, UserProfile) {
+func (p PersistingExperimentService) getSavedDecision(ctx context.Context, decisionContext ExperimentDecisionContext, userContext entities.UserContext) (ExperimentDecision, UserProfile, error) {
 	experimentDecision := ExperimentDecision{}
-	userProfile := p.userProfileService.Lookup(userContext.ID)
+	userProfile, err := lookupUserProfile(ctx, p.userProfileService, userContext.ID)
+	if err != nil {
+		return experimentDecision, userProfile, err
+	}
 
 	// look up experiment decision from user profile
 	decisionKey := NewUserDecisionKey(decisionContext.Experiment.ID)
 	if userProfile.ExperimentBucketMap == nil {
-		return experimentDecision, userProfile
+		return experimentDecision, userProfile, nil
 	}
 
 	if savedVariationID, ok := userProfile.ExperimentBucketMap[decisionKey]; ok {
 		if variation, ok := decisionContext.Experiment.Variations[savedVariationID]; ok {
 			experimentDecision.Variation = &variation
-			p.logger.Debug(fmt.Sprintf(`User "%s" was previously bucketed into variation "%s" of experiment "%s".`, userContext.ID, variation.Key, decisionContext.Experiment.Key))
+			p.logger.Debug(decisionContext.Reasons.AddInfo(`User "%s" was previously bucketed into variation "%s" of experiment "%s".`, userContext.ID, variation.Key, decisionContext.Experiment.Key))
 		} else {
-			p.logger.Warning(fmt.Sprintf(`User "%s" was previously bucketed into variation with ID "%s" for experiment "%s", but no matching variation was found.`, userContext.ID, savedVariationID, decisionContext.Experiment.Key))
+			p.logger.Warning(decisionContext.Reasons.AddInfo(`User "%s" was previously bucketed into variation with ID "%s" for experiment "%s", but no matching variation was found.`, userContext.ID, savedVariationID, decisionContext.Experiment.Key))
 		}
 	}
 
-	return experimentDecision, userProfile
+	return experimentDecision, userProfile, nil
 }
 
-func (p PersistingExperimentService) saveDecision(userProfile UserProfile, experiment *entities.Experiment, decision ExperimentDecision) {
+func (p PersistingExperimentService) saveDecision(ctx context.Context, userProfile UserProfile, experiment *entities.Experiment, decision ExperimentDecision) {
 	if p.userProfileService != nil {
 		decisionKey := NewUserDecisionKey(experiment.ID)
 		if userProfile.ExperimentBucketMap == nil {
 			userProfile.ExperimentBucketMap = map[UserDecisionKey]string{}
 		}
 		userProfile.ExperimentBucketMap[decisionKey] = decision.Variation.ID
-		p.userProfileService.Save(userProfile)
+		if err := saveUserProfile(ctx, p.userProfileService, userProfile); err != nil {
+			p.logger.Warning(fmt.Sprintf(`Unable to save decision for user "%s": %v`, userProfile.ID, err))
+			return
+		}
 		p.logger.Debug(fmt.Sprintf(`Decision saved for user "%s".`, userProfile.ID))
 	}
 }
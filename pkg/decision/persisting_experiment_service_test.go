@@ -18,7 +18,9 @@
 package decision
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
@@ -122,6 +124,51 @@ func (s *PersistingExperimentServiceTestSuite) TestSavedVariationNoLongerValid()
 	s.mockUserProfileService.AssertExpectations(s.T())
 }
 
+func (s *PersistingExperimentServiceTestSuite) TestIgnoreUserProfileService() {
+	testDecisionContext := s.testDecisionContext
+	testDecisionContext.Options.IgnoreUserProfileService = true
+	s.mockExperimentService.On("GetDecision", testDecisionContext, testUserContext).Return(s.testComputedDecision, nil)
+
+	persistingExperimentService := NewPersistingExperimentService(s.mockUserProfileService, s.mockExperimentService, logging.GetLogger("", "NewPersistingExperimentService"))
+	decision, err := persistingExperimentService.GetDecision(testDecisionContext, testUserContext)
+	s.Equal(s.testComputedDecision, decision)
+	s.NoError(err)
+	s.mockUserProfileService.AssertNotCalled(s.T(), "Lookup", mock.Anything)
+	s.mockUserProfileService.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *PersistingExperimentServiceTestSuite) TestLookupDeadlineExceeded() {
+	// the decision is bucketed without the user profile and is not saved
+	s.mockUserProfileService.On("Lookup", testUserContext.ID).Return(UserProfile{ID: testUserContext.ID}).After(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	persistingExperimentService := NewPersistingExperimentService(s.mockUserProfileService, s.mockExperimentService, logging.GetLogger("", "NewPersistingExperimentService"))
+	decision, err := persistingExperimentService.GetDecisionWithContext(ctx, s.testDecisionContext, testUserContext)
+	s.Equal(s.testComputedDecision, decision)
+	s.NoError(err)
+	s.mockExperimentService.AssertExpectations(s.T())
+	s.mockUserProfileService.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *PersistingExperimentServiceTestSuite) TestContextUserProfileService() {
+	ctx := context.WithValue(context.Background(), testContextKey("trace_id"), "1234")
+	decisionKey := NewUserDecisionKey(s.testDecisionContext.Experiment.ID)
+	updatedUserProfile := UserProfile{
+		ID:                  testUserContext.ID,
+		ExperimentBucketMap: map[UserDecisionKey]string{decisionKey: s.testComputedDecision.Variation.ID},
+	}
+	mockUserProfileService := new(MockContextUserProfileService)
+	mockUserProfileService.On("LookupWithContext", ctx, testUserContext.ID).Return(UserProfile{ID: testUserContext.ID}, nil)
+	mockUserProfileService.On("SaveWithContext", ctx, updatedUserProfile).Return(nil)
+
+	persistingExperimentService := NewPersistingExperimentService(mockUserProfileService, s.mockExperimentService, logging.GetLogger("", "NewPersistingExperimentService"))
+	decision, err := persistingExperimentService.GetDecisionWithContext(ctx, s.testDecisionContext, testUserContext)
+	s.Equal(s.testComputedDecision, decision)
+	s.NoError(err)
+	mockUserProfileService.AssertExpectations(s.T())
+}
+
 func TestPersistingExperimentServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(PersistingExperimentServiceTestSuite))
 }
@@ -57,4 +57,10 @@ const (
 	InvalidOverrideVariationAssignment Reason = "Invalid override variation assignment"
 	// OverrideVariationAssignmentFound - A valid override variation was found for the given user and experiment
 	OverrideVariationAssignmentFound Reason = "Override variation assignment found"
+	// ExperimentNotRunning - the experiment is not running so users are not bucketed into it
+	ExperimentNotRunning Reason = "Experiment is not running"
+	// ForcedDecisionFound - A valid forced decision was set on the user context for the given flag or rule
+	ForcedDecisionFound Reason = "Forced decision found"
+	// HeldOut - the user is bucketed into a global holdout and held out of every experiment and rollout
+	HeldOut Reason = "Held out of every experiment and rollout"
 )
@@ -56,7 +56,7 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 	evaluateConditionTree := func(experiment *entities.Experiment, loggingKey string) bool {
 		condTreeParams := entities.NewTreeParameters(&userContext, decisionContext.ProjectConfig.GetAudienceMap())
 		r.logger.Debug(fmt.Sprintf(logging.EvaluatingAudiencesForRollout.String(), loggingKey))
-		evalResult, _ := r.audienceTreeEvaluator.Evaluate(experiment.AudienceConditionTree, condTreeParams)
+		evalResult, _ := evaluateAudienceTree(r.audienceTreeEvaluator, experiment.Key, experiment.AudienceConditionTree, condTreeParams, decisionContext.Reasons)
 		if !evalResult {
 			featureDecision.Reason = reasons.FailedRolloutTargeting
 		}
@@ -76,7 +76,7 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 
 		featureDecision.Experiment = *experiment
 		featureDecision.Variation = decision.Variation
-		r.logger.Debug(fmt.Sprintf(`Decision made for user "%s" for feature rollout with key "%s": %s.`, userContext.ID, feature.Key, featureDecision.Reason))
+		r.logger.Debug(decisionContext.Reasons.AddInfo(`Decision made for user "%s" for feature rollout with key "%s": %s.`, userContext.ID, feature.Key, featureDecision.Reason))
 		return featureDecision, nil
 	}
 
@@ -84,6 +84,8 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 		return ExperimentDecisionContext{
 			Experiment:    experiment,
 			ProjectConfig: decisionContext.ProjectConfig,
+			Options:       decisionContext.Options,
+			Reasons:       decisionContext.Reasons,
 		}
 	}
 
@@ -101,13 +103,16 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 	for index := 0; index < numberOfExperiments-1; index++ {
 		loggingKey := strconv.Itoa(index + 1)
 		experiment := &rollout.Experiments[index]
+		if forcedDecision, ok := decisionContext.ForcedDecisionService.FindValidatedForcedDecision(feature, experiment, decisionContext.Reasons); ok {
+			return forcedDecision, nil
+		}
 		experimentDecisionContext := getExperimentDecisionContext(experiment)
 		// Move to next evaluation if condition tree is available and evaluation fails
 
 		evaluationResult := experiment.AudienceConditionTree == nil || evaluateConditionTree(experiment, loggingKey)
-		r.logger.Debug(fmt.Sprintf(logging.RolloutAudiencesEvaluatedTo.String(), loggingKey, evaluationResult))
+		r.logger.Debug(decisionContext.Reasons.AddInfo(logging.RolloutAudiencesEvaluatedTo.String(), loggingKey, evaluationResult))
 		if !evaluationResult {
-			r.logger.Debug(fmt.Sprintf(logging.UserNotInRollout.String(), userContext.ID, loggingKey))
+			r.logger.Debug(decisionContext.Reasons.AddInfo(logging.UserNotInRollout.String(), userContext.ID, loggingKey))
 			// Evaluate this user for the next rule
 			continue
 		}
@@ -122,16 +127,19 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 
 	// fall back rule / last rule
 	experiment := &rollout.Experiments[numberOfExperiments-1]
+	if forcedDecision, ok := decisionContext.ForcedDecisionService.FindValidatedForcedDecision(feature, experiment, decisionContext.Reasons); ok {
+		return forcedDecision, nil
+	}
 	experimentDecisionContext := getExperimentDecisionContext(experiment)
 	// Move to bucketing if conditionTree is unavailable or evaluation passes
 	evaluationResult := experiment.AudienceConditionTree == nil || evaluateConditionTree(experiment, "Everyone Else")
-	r.logger.Debug(fmt.Sprintf(logging.RolloutAudiencesEvaluatedTo.String(), "Everyone Else", evaluationResult))
+	r.logger.Debug(decisionContext.Reasons.AddInfo(logging.RolloutAudiencesEvaluatedTo.String(), "Everyone Else", evaluationResult))
 
 	if evaluationResult {
 		decision, err := r.experimentBucketerService.GetDecision(experimentDecisionContext, userContext)
 		if err == nil {
 
-			r.logger.Debug(fmt.Sprintf(logging.UserInEveryoneElse.String(), userContext.ID))
+			r.logger.Debug(decisionContext.Reasons.AddInfo(logging.UserInEveryoneElse.String(), userContext.ID))
 		}
 		return getFeatureDecision(experiment, &decision)
 	}
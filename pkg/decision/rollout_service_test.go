@@ -283,6 +283,37 @@ func (s *RolloutServiceTestSuite) TestGetDecisionFailsTargeting() {
 	s.mockLogger.AssertExpectations(s.T())
 }
 
+func (s *RolloutServiceTestSuite) TestGetDecisionWithForcedDecision() {
+	// the forced decision of the second rule is returned before evaluating its audiences and bucketing
+	s.mockAudienceTreeEvaluator.On("Evaluate", testExp1112.AudienceConditionTree, s.testConditionTreeParams).Return(false, true)
+	testRolloutService := RolloutService{
+		audienceTreeEvaluator:     s.mockAudienceTreeEvaluator,
+		experimentBucketerService: s.mockExperimentService,
+		logger:                    s.mockLogger,
+	}
+	forcedDecisionService := NewForcedDecisionService(s.testUserContext.ID)
+	forcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: testFeatRollout3334Key, RuleKey: testExp1117.Key}, OptimizelyForcedDecision{VariationKey: "2223"})
+	featureDecisionContext := s.testFeatureDecisionContext
+	featureDecisionContext.ForcedDecisionService = forcedDecisionService
+
+	expectedFeatureDecision := FeatureDecision{
+		Experiment: testExp1117,
+		Variation:  &testExp1117Var2223,
+		Source:     Rollout,
+		Decision:   Decision{Reason: reasons.ForcedDecisionFound},
+	}
+	s.mockLogger.On("Debug", fmt.Sprintf(logging.EvaluatingAudiencesForRollout.String(), "1"))
+	s.mockLogger.On("Debug", fmt.Sprintf(logging.RolloutAudiencesEvaluatedTo.String(), "1", false))
+	s.mockLogger.On("Debug", fmt.Sprintf(logging.UserNotInRollout.String(), "test_user", "1"))
+	decision, err := testRolloutService.GetDecision(featureDecisionContext, s.testUserContext)
+	s.NoError(err)
+	s.Equal(expectedFeatureDecision, decision)
+	s.mockAudienceTreeEvaluator.AssertExpectations(s.T())
+	s.mockAudienceTreeEvaluator.AssertNumberOfCalls(s.T(), "Evaluate", 1)
+	s.mockExperimentService.AssertNotCalled(s.T(), "GetDecision")
+	s.mockLogger.AssertExpectations(s.T())
+}
+
 func TestNewRolloutService(t *testing.T) {
 	rolloutService := NewRolloutService("")
 	assert.IsType(t, &evaluator.MixedTreeEvaluator{}, rolloutService.audienceTreeEvaluator)
@@ -22,6 +22,8 @@ type Audience struct {
 	ID            string
 	Name          string
 	ConditionTree *TreeNode
+	// Conditions holds the conditions of the audience as a JSON string, as found in the datafile
+	Conditions string
 }
 
 // Condition has condition info
@@ -17,6 +17,12 @@
 // Package entities //
 package entities
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // TreeNode in a condition tree
 type TreeNode struct {
 	Item     interface{} // can be a condition or a string
@@ -35,3 +41,60 @@ type TreeParameters struct {
 func NewTreeParameters(user *UserContext, audience map[string]Audience) *TreeParameters {
 	return &TreeParameters{User: user, AudienceMap: audience}
 }
+
+// TreeNodeTrace records the result of a node of an evaluated condition tree, for debugging. Operator nodes hold the
+// traces of the nodes they evaluated, audience nodes the trace of the condition tree of the audience. Result is nil
+// when the node is invalid, such as a condition on a missing attribute.
+type TreeNodeTrace struct {
+	Operator      string           `json:"operator,omitempty"`
+	AudienceID    string           `json:"audienceId,omitempty"`
+	AudienceName  string           `json:"audienceName,omitempty"`
+	AttributeName string           `json:"attributeName,omitempty"`
+	MatchType     string           `json:"matchType,omitempty"`
+	ExpectedValue interface{}      `json:"expectedValue,omitempty"`
+	ActualValue   interface{}      `json:"actualValue,omitempty"`
+	Result        *bool            `json:"result"`
+	Nodes         []*TreeNodeTrace `json:"nodes,omitempty"`
+}
+
+// String returns a single line representation of the trace, such as
+// or(audience "13389141123" "adult"(or(age gt 17 (actual 15) => false) => false) => false) => false
+func (t TreeNodeTrace) String() string {
+	var description string
+	switch {
+	case t.Operator != "":
+		nodes := make([]string, len(t.Nodes))
+		for i, node := range t.Nodes {
+			nodes[i] = node.String()
+		}
+		description = fmt.Sprintf("%s(%s)", t.Operator, strings.Join(nodes, ", "))
+	case t.AudienceID != "":
+		description = fmt.Sprintf("audience %q %q", t.AudienceID, t.AudienceName)
+		if len(t.Nodes) > 0 {
+			description += fmt.Sprintf("(%s)", t.Nodes[0].String())
+		}
+	default:
+		description = fmt.Sprintf("%s %s", t.AttributeName, t.MatchType)
+		if t.ExpectedValue != nil {
+			description += " " + traceValue(t.ExpectedValue)
+		}
+		description += fmt.Sprintf(" (actual %s)", traceValue(t.ActualValue))
+	}
+
+	result := "null"
+	if t.Result != nil {
+		result = strconv.FormatBool(*t.Result)
+	}
+	return description + " => " + result
+}
+
+func traceValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
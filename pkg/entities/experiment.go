@@ -25,6 +25,22 @@ type Variation struct {
 	FeatureEnabled bool
 }
 
+// ExperimentStatus is the status of an experiment as set in the datafile
+type ExperimentStatus string
+
+const (
+	// ExperimentStatusRunning - the experiment is running and users can be bucketed into it
+	ExperimentStatusRunning ExperimentStatus = "Running"
+	// ExperimentStatusPaused - the experiment is paused
+	ExperimentStatusPaused ExperimentStatus = "Paused"
+	// ExperimentStatusNotStarted - the experiment has not been started yet
+	ExperimentStatusNotStarted ExperimentStatus = "Not started"
+	// ExperimentStatusLaunched - the experiment has been launched
+	ExperimentStatusLaunched ExperimentStatus = "Launched"
+	// ExperimentStatusArchived - the experiment has been archived
+	ExperimentStatusArchived ExperimentStatus = "Archived"
+)
+
 // Experiment represents an experiment
 type Experiment struct {
 	AudienceIds           []string
@@ -38,6 +54,20 @@ type Experiment struct {
 	AudienceConditionTree *TreeNode
 	Whitelist             map[string]string
 	IsFeatureExperiment   bool
+	Status                ExperimentStatus
+	// RolloutID is the ID of the rollout of the rollout rules, it is empty for experiments
+	RolloutID string
+}
+
+// IsRunning returns true if users can be bucketed into the experiment.
+// An experiment without a status, for example one that is not built from a datafile, is considered running.
+func (e Experiment) IsRunning() bool {
+	return e.Status == "" || e.Status == ExperimentStatusRunning
+}
+
+// IsRolloutRule returns true if the experiment is a rule of a rollout
+func (e Experiment) IsRolloutRule() bool {
+	return e.RolloutID != ""
 }
 
 // Range represents bucketing range that the specify entityID falls into
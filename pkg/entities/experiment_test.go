@@ -0,0 +1,32 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperimentIsRunning(t *testing.T) {
+	assert.True(t, Experiment{}.IsRunning())
+	assert.True(t, Experiment{Status: ExperimentStatusRunning}.IsRunning())
+	assert.False(t, Experiment{Status: ExperimentStatusPaused}.IsRunning())
+	assert.False(t, Experiment{Status: ExperimentStatusNotStarted}.IsRunning())
+	assert.False(t, Experiment{Status: ExperimentStatusLaunched}.IsRunning())
+	assert.False(t, Experiment{Status: ExperimentStatusArchived}.IsRunning())
+}
@@ -0,0 +1,34 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package entities //
+package entities
+
+// Holdout represents a global holdout, the users bucketed into it are held out of every experiment and rollout
+type Holdout struct {
+	ID                    string
+	Key                   string
+	Status                ExperimentStatus
+	AudienceIds           []string
+	AudienceConditionTree *TreeNode
+	Variations            map[string]Variation // keyed by variation ID
+	TrafficAllocation     []Range
+}
+
+// IsRunning returns true if users can be held out by the holdout, a holdout without a status is considered running
+func (h Holdout) IsRunning() bool {
+	return h.Status == "" || h.Status == ExperimentStatusRunning
+}
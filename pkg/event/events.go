@@ -43,9 +43,10 @@ type ImpressionEvent struct {
 	EntityID     string `json:"entity_id"`
 	Key          string `json:"key"`
 	Attributes   []VisitorAttribute
-	VariationID  string `json:"variation_id"`
-	CampaignID   string `json:"campaign_id"`
-	ExperimentID string `json:"experiment_id"`
+	VariationID  string           `json:"variation_id"`
+	CampaignID   string           `json:"campaign_id"`
+	ExperimentID string           `json:"experiment_id"`
+	Metadata     DecisionMetadata `json:"metadata"`
 }
 
 // ConversionEvent represents a conversion event
@@ -101,9 +102,19 @@ type Snapshot struct {
 
 // Decision represents a decision of a snapshot
 type Decision struct {
-	VariationID  string `json:"variation_id"`
-	CampaignID   string `json:"campaign_id"`
-	ExperimentID string `json:"experiment_id"`
+	VariationID  string           `json:"variation_id"`
+	CampaignID   string           `json:"campaign_id"`
+	ExperimentID string           `json:"experiment_id"`
+	Metadata     DecisionMetadata `json:"metadata"`
+}
+
+// DecisionMetadata describes the flag decision behind an impression
+type DecisionMetadata struct {
+	FlagKey      string `json:"flag_key"`
+	RuleKey      string `json:"rule_key"`
+	RuleType     string `json:"rule_type"`
+	VariationKey string `json:"variation_key"`
+	Enabled      bool   `json:"enabled"`
 }
 
 // SnapshotEvent represents an event of a snapshot
@@ -35,6 +35,18 @@ const botFilteringKey = "$opt_bot_filtering"
 const revenueKey = "revenue"
 const valueKey = "value"
 
+// RuleTypeAB is the rule type of the impressions of a/b tests activated outside of a flag decision
+const RuleTypeAB = "a/b"
+
+// RuleTypeFeatureTest is the rule type of the impressions of feature tests
+const RuleTypeFeatureTest = "feature-test"
+
+// RuleTypeRollout is the rule type of the impressions of rollout rules
+const RuleTypeRollout = "rollout"
+
+// RuleTypeHoldout is the rule type of the impressions of the users held out by a global holdout
+const RuleTypeHoldout = "holdout"
+
 func createLogEvent(event Batch, eventEndPoint string) LogEvent {
 	return LogEvent{EndPoint: eventEndPoint, Event: event}
 }
@@ -67,6 +79,12 @@ func createImpressionEvent(projectConfig config.ProjectConfig, experiment entiti
 	impression.VariationID = variation.ID
 	impression.ExperimentID = experiment.ID
 	impression.CampaignID = experiment.LayerID
+	impression.Metadata = DecisionMetadata{
+		RuleKey:      experiment.Key,
+		RuleType:     RuleTypeAB,
+		VariationKey: variation.Key,
+		Enabled:      variation.FeatureEnabled,
+	}
 
 	return impression
 }
@@ -77,6 +95,24 @@ func CreateImpressionUserEvent(projectConfig config.ProjectConfig, experiment en
 	userContext entities.UserContext) UserEvent {
 
 	impression := createImpressionEvent(projectConfig, experiment, variation, userContext.Attributes)
+	return createImpressionUserEvent(projectConfig, impression, userContext)
+}
+
+// CreateFlagImpressionUserEvent creates and returns ImpressionEvent for user, made by the rule of the given type while
+// deciding the flag
+func CreateFlagImpressionUserEvent(projectConfig config.ProjectConfig, flagKey, ruleType string,
+	experiment entities.Experiment,
+	variation entities.Variation,
+	userContext entities.UserContext) UserEvent {
+
+	impression := createImpressionEvent(projectConfig, experiment, variation, userContext.Attributes)
+	impression.Metadata.FlagKey = flagKey
+	impression.Metadata.RuleType = ruleType
+	return createImpressionUserEvent(projectConfig, impression, userContext)
+}
+
+func createImpressionUserEvent(projectConfig config.ProjectConfig, impression ImpressionEvent,
+	userContext entities.UserContext) UserEvent {
 
 	userEvent := UserEvent{}
 	userEvent.Timestamp = makeTimestamp()
@@ -94,6 +130,7 @@ func createImpressionVisitor(userEvent UserEvent) Visitor {
 	decision.CampaignID = userEvent.Impression.CampaignID
 	decision.ExperimentID = userEvent.Impression.ExperimentID
 	decision.VariationID = userEvent.Impression.VariationID
+	decision.Metadata = userEvent.Impression.Metadata
 
 	dispatchEvent := SnapshotEvent{}
 	dispatchEvent.Timestamp = makeTimestamp()
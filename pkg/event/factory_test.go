@@ -121,6 +121,38 @@ func TestCreateEmptyEvent(t *testing.T) {
 
 }
 
+func TestCreateImpressionEventMetadata(t *testing.T) {
+
+	impressionUserEvent := BuildTestImpressionEvent()
+
+	visitor := createVisitorFromUserEvent(impressionUserEvent)
+
+	assert.Equal(t, DecisionMetadata{RuleKey: "background_experiment", RuleType: RuleTypeAB, VariationKey: "variation_a"},
+		visitor.Snapshots[0].Decisions[0].Metadata)
+}
+
+func TestCreateFlagImpressionEventMetadata(t *testing.T) {
+	config := TestConfig{}
+
+	experiment := entities.Experiment{Key: "background_experiment", LayerID: "15399420423", ID: "15402980349"}
+	variation := entities.Variation{Key: "variation_a", ID: "15410990633", FeatureEnabled: true}
+
+	impressionUserEvent := CreateFlagImpressionUserEvent(config, "background_feature", RuleTypeFeatureTest, experiment, variation, userContext)
+
+	expected := DecisionMetadata{
+		FlagKey:      "background_feature",
+		RuleKey:      "background_experiment",
+		RuleType:     RuleTypeFeatureTest,
+		VariationKey: "variation_a",
+		Enabled:      true,
+	}
+	assert.Equal(t, expected, impressionUserEvent.Impression.Metadata)
+
+	visitor := createVisitorFromUserEvent(impressionUserEvent)
+	assert.Equal(t, expected, visitor.Snapshots[0].Decisions[0].Metadata)
+	assert.Equal(t, "15402980349", visitor.Snapshots[0].Decisions[0].ExperimentID)
+}
+
 func TestCreateAndSendImpressionEvent(t *testing.T) {
 
 	impressionUserEvent := BuildTestImpressionEvent()
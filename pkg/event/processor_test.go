@@ -27,6 +27,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/optimizely/go-sdk/pkg/entities"
 	"github.com/optimizely/go-sdk/pkg/logging"
 	"github.com/optimizely/go-sdk/pkg/utils"
 )
@@ -243,6 +244,38 @@ func TestDefaultEventProcessor_ProcessBatch(t *testing.T) {
 	assert.Equal(t, 4, len(logEvent.Event.Visitors))
 }
 
+func TestDefaultEventProcessor_ProcessBatchKeepsMetadata(t *testing.T) {
+	eg := newExecutionContext()
+	dispatcher := NewMockDispatcher(100, false)
+	processor := NewBatchEventProcessor(
+		WithFlushInterval(1*time.Second),
+		WithQueueSize(100),
+		WithQueue(NewInMemoryQueue(100)),
+		WithEventDispatcher(dispatcher))
+	eg.Go(processor.Start)
+
+	experiment := entities.Experiment{Key: "background_experiment", LayerID: "15399420423", ID: "15402980349"}
+	variation := entities.Variation{Key: "variation_a", ID: "15410990633", FeatureEnabled: true}
+
+	processor.ProcessEvent(CreateFlagImpressionUserEvent(TestConfig{}, "feature_a", RuleTypeRollout, experiment, variation, userContext))
+	processor.ProcessEvent(CreateFlagImpressionUserEvent(TestConfig{}, "feature_b", RuleTypeFeatureTest, experiment, variation, userContext))
+
+	eg.TerminateAndWait()
+
+	assert.Equal(t, 1, dispatcher.Events.Size())
+	evs := dispatcher.Events.Get(1)
+	logEvent, _ := evs[0].(LogEvent)
+	if assert.Equal(t, 2, len(logEvent.Event.Visitors)) {
+		first := logEvent.Event.Visitors[0].Snapshots[0].Decisions[0].Metadata
+		assert.Equal(t, "feature_a", first.FlagKey)
+		assert.Equal(t, RuleTypeRollout, first.RuleType)
+		second := logEvent.Event.Visitors[1].Snapshots[0].Decisions[0].Metadata
+		assert.Equal(t, "feature_b", second.FlagKey)
+		assert.Equal(t, RuleTypeFeatureTest, second.RuleType)
+		assert.True(t, second.Enabled)
+	}
+}
+
 func TestDefaultEventProcessor_BatchSizeMet(t *testing.T) {
 	eg := newExecutionContext()
 	dispatcher := NewMockDispatcher(100, false)
@@ -24,3 +24,18 @@ const (
 	DispatcherRetryFlush   = "dispatcher.retryFlush"
 	DispatcherQueueSize    = "dispatcher.queueSize"
 )
+
+// ConfigManagerFetch counts downloaded datafiles, the other config manager metrics count 304 responses, failed
+// fetches, rejected datafiles and the ones among them rejected for an older revision, measure the staleness of
+// the project config and the duration of the last fetch in seconds, and count the datafile bytes which were not
+// downloaded again thanks to 304 responses
+const (
+	ConfigManagerFetch            = "configManager.fetch"
+	ConfigManagerNotModified      = "configManager.notModified"
+	ConfigManagerFailedFetch      = "configManager.failedFetch"
+	ConfigManagerRejectedDatafile = "configManager.rejectedDatafile"
+	ConfigManagerRejectedRevision = "configManager.rejectedRevision"
+	ConfigManagerStaleness        = "configManager.staleness"
+	ConfigManagerFetchLatency     = "configManager.fetchLatency"
+	ConfigManagerBytesSaved       = "configManager.bytesSaved"
+)
@@ -0,0 +1,128 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package middleware //
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/optimizely/go-sdk/pkg/client"
+	"github.com/optimizely/go-sdk/pkg/decide"
+)
+
+type contextKey struct{}
+
+// requestState holds the user context of a request and the decisions already made for it. The generation is
+// incremented whenever the user context is updated, so that decisions made for an outdated user context are not cached.
+type requestState struct {
+	userContext *client.OptimizelyUserContext
+	decisions   map[string]client.OptimizelyDecision
+	generation  int
+	lock        sync.Mutex
+}
+
+// NewContext returns a copy of ctx holding the given user context and an empty decision cache
+func NewContext(ctx context.Context, userContext *client.OptimizelyUserContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestState{
+		userContext: userContext,
+		decisions:   map[string]client.OptimizelyDecision{},
+	})
+}
+
+// UserContextFromContext returns the user context stored in ctx by the Middleware. The decisions cached by Decide are
+// not invalidated when it is mutated directly, use UpdateUserContext to set attributes or forced decisions once
+// decisions have been made.
+func UserContextFromContext(ctx context.Context) (*client.OptimizelyUserContext, bool) {
+	state, ok := ctx.Value(contextKey{}).(*requestState)
+	if !ok {
+		return nil, false
+	}
+	return state.userContext, true
+}
+
+// UpdateUserContext calls update with the user context stored in ctx, to set its attributes or forced decisions, and
+// clears the decisions cached by Decide. It returns false when ctx holds no user context.
+func UpdateUserContext(ctx context.Context, update func(userContext *client.OptimizelyUserContext)) bool {
+	state, ok := ctx.Value(contextKey{}).(*requestState)
+	if !ok {
+		return false
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	update(state.userContext)
+	state.decisions = map[string]client.OptimizelyDecision{}
+	state.generation++
+	return true
+}
+
+// Decide returns the decision for the given flag and the user context stored in ctx. Decisions are cached for the
+// lifetime of ctx, or until the user context is updated with UpdateUserContext, so a flag is only evaluated, and its
+// impression only sent, once per request and set of options. Flags are evaluated outside of the lock of the cache, a
+// flag decided concurrently by several goroutines may be evaluated more than once.
+// The second return value is false when ctx holds no user context.
+func Decide(ctx context.Context, flagKey string, options ...decide.OptimizelyDecideOptions) (client.OptimizelyDecision, bool) {
+	state, ok := ctx.Value(contextKey{}).(*requestState)
+	if !ok {
+		return client.OptimizelyDecision{FlagKey: flagKey}, false
+	}
+
+	cacheKey := fmt.Sprintf("%s%v", flagKey, options)
+	state.lock.Lock()
+	if decision, ok := state.decisions[cacheKey]; ok {
+		state.lock.Unlock()
+		return decision, true
+	}
+	generation := state.generation
+	state.lock.Unlock()
+
+	decision := state.userContext.DecideWithContext(ctx, flagKey, options...)
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	if state.generation != generation {
+		return decision, true
+	}
+	if cached, ok := state.decisions[cacheKey]; ok {
+		return cached, true
+	}
+	state.decisions[cacheKey] = decision
+	return decision, true
+}
+
+// IsEnabled returns whether the given flag is enabled for the user context stored in ctx, false if there is none
+func IsEnabled(ctx context.Context, flagKey string) bool {
+	decision, _ := Decide(ctx, flagKey)
+	return decision.Enabled
+}
+
+// VariationKey returns the variation of the given flag for the user context stored in ctx, empty if there is none
+func VariationKey(ctx context.Context, flagKey string) string {
+	decision, _ := Decide(ctx, flagKey)
+	return decision.VariationKey
+}
+
+// Variables returns the variables of the given flag for the user context stored in ctx, nil if there is none
+func Variables(ctx context.Context, flagKey string) map[string]interface{} {
+	decision, _ := Decide(ctx, flagKey)
+	if decision.Variables == nil {
+		return nil
+	}
+	return decision.Variables.ToMap()
+}
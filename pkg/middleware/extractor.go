@@ -0,0 +1,91 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package middleware //
+package middleware
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrUserIDNotFound is returned by an IDExtractor when the request does not identify a user
+var ErrUserIDNotFound = errors.New("user ID not found in request")
+
+// IDExtractor returns the ID of the user making the request
+type IDExtractor func(r *http.Request) (string, error)
+
+// AttributesExtractor returns attributes of the user making the request
+type AttributesExtractor func(r *http.Request) map[string]interface{}
+
+// HeaderID extracts the user ID from the given request header
+func HeaderID(header string) IDExtractor {
+	return func(r *http.Request) (string, error) {
+		if userID := r.Header.Get(header); userID != "" {
+			return userID, nil
+		}
+		return "", ErrUserIDNotFound
+	}
+}
+
+// CookieID extracts the user ID from the given cookie
+func CookieID(name string) IDExtractor {
+	return func(r *http.Request) (string, error) {
+		cookie, err := r.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return "", ErrUserIDNotFound
+		}
+		return cookie.Value, nil
+	}
+}
+
+// FirstID returns the first user ID found by the given extractors, in order
+func FirstID(extractors ...IDExtractor) IDExtractor {
+	return func(r *http.Request) (string, error) {
+		for _, extractor := range extractors {
+			if userID, err := extractor(r); err == nil {
+				return userID, nil
+			}
+		}
+		return "", ErrUserIDNotFound
+	}
+}
+
+// HeaderAttributes maps the given request headers, when set, to the given attribute keys
+func HeaderAttributes(headerToAttribute map[string]string) AttributesExtractor {
+	return func(r *http.Request) map[string]interface{} {
+		attributes := map[string]interface{}{}
+		for header, attributeKey := range headerToAttribute {
+			if value := r.Header.Get(header); value != "" {
+				attributes[attributeKey] = value
+			}
+		}
+		return attributes
+	}
+}
+
+// CookieAttributes maps the given cookies, when set, to the given attribute keys
+func CookieAttributes(cookieToAttribute map[string]string) AttributesExtractor {
+	return func(r *http.Request) map[string]interface{} {
+		attributes := map[string]interface{}{}
+		for name, attributeKey := range cookieToAttribute {
+			if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+				attributes[attributeKey] = cookie.Value
+			}
+		}
+		return attributes
+	}
+}
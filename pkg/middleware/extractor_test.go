@@ -0,0 +1,72 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err := HeaderID("X-User-ID")(r)
+	assert.Equal(t, ErrUserIDNotFound, err)
+
+	r.Header.Set("X-User-ID", "test_user")
+	userID, err := HeaderID("X-User-ID")(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_user", userID)
+}
+
+func TestCookieID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err := CookieID("uid")(r)
+	assert.Equal(t, ErrUserIDNotFound, err)
+
+	r.AddCookie(&http.Cookie{Name: "uid", Value: "test_user"})
+	userID, err := CookieID("uid")(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_user", userID)
+}
+
+func TestFirstID(t *testing.T) {
+	extractor := FirstID(HeaderID("X-User-ID"), CookieID("uid"))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err := extractor(r)
+	assert.Equal(t, ErrUserIDNotFound, err)
+
+	r.AddCookie(&http.Cookie{Name: "uid", Value: "cookie_user"})
+	userID, err := extractor(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "cookie_user", userID)
+
+	r.Header.Set("X-User-ID", "header_user")
+	userID, err = extractor(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "header_user", userID)
+}
+
+func TestCookieAttributes(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "plan", Value: "free"})
+
+	attributes := CookieAttributes(map[string]string{"plan": "plan", "country": "country"})(r)
+	assert.Equal(t, map[string]interface{}{"plan": "free"}, attributes)
+}
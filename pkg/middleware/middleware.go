@@ -0,0 +1,95 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package middleware provides net/http middleware which creates the Optimizely user context of each request
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/optimizely/go-sdk/pkg/client"
+	"github.com/optimizely/go-sdk/pkg/logging"
+)
+
+// Middleware creates the user context of every request and stores it, along with a decision cache scoped to the
+// request, in the request context
+type Middleware struct {
+	optimizelyClient     *client.OptimizelyClient
+	idExtractor          IDExtractor
+	attributesExtractors []AttributesExtractor
+	missingUserHandler   http.Handler
+	logger               logging.OptimizelyLogProducer
+}
+
+// OptionFunc is used to provide custom configuration to the Middleware.
+type OptionFunc func(*Middleware)
+
+// WithAttributesExtractors adds extractors for the user attributes, attributes of later extractors override the
+// attributes with the same key of earlier ones
+func WithAttributesExtractors(extractors ...AttributesExtractor) OptionFunc {
+	return func(m *Middleware) {
+		m.attributesExtractors = append(m.attributesExtractors, extractors...)
+	}
+}
+
+// WithMissingUserHandler sets the handler serving the requests for which no user ID is found. By default these
+// requests are passed on without a user context, and the flag helpers return the default values.
+func WithMissingUserHandler(handler http.Handler) OptionFunc {
+	return func(m *Middleware) {
+		m.missingUserHandler = handler
+	}
+}
+
+// NewMiddleware returns a new instance of the Middleware
+func NewMiddleware(optimizelyClient *client.OptimizelyClient, idExtractor IDExtractor, options ...OptionFunc) *Middleware {
+	middleware := &Middleware{
+		optimizelyClient: optimizelyClient,
+		idExtractor:      idExtractor,
+		logger:           logging.GetLogger("", "Middleware"),
+	}
+
+	for _, opt := range options {
+		opt(middleware)
+	}
+	return middleware
+}
+
+// Handler wraps the given handler
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := m.idExtractor(r)
+		if err != nil {
+			m.logger.Debug(fmt.Sprintf("No user ID for request %s %s: %v", r.Method, r.URL.Path, err))
+			if m.missingUserHandler != nil {
+				m.missingUserHandler.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		attributes := map[string]interface{}{}
+		for _, extractor := range m.attributesExtractors {
+			for key, value := range extractor(r) {
+				attributes[key] = value
+			}
+		}
+
+		userContext := m.optimizelyClient.CreateUserContext(userID, attributes)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), &userContext)))
+	})
+}
@@ -0,0 +1,195 @@
+/****************************************************************************
+ * Copyright 2020, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/optimizely/go-sdk/pkg/client"
+	"github.com/optimizely/go-sdk/pkg/decision"
+	"github.com/optimizely/go-sdk/pkg/event"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testDatafile = `{
+	"version": "4",
+	"revision": "1",
+	"projectId": "1",
+	"accountId": "1",
+	"experiments": [{
+		"id": "10",
+		"key": "test_experiment",
+		"status": "Running",
+		"layerId": "1",
+		"audienceIds": [],
+		"forcedVariations": {},
+		"variations": [{"id": "20", "key": "variation_on", "featureEnabled": true, "variables": [{"id": "30", "value": "blue"}]},
+		               {"id": "21", "key": "variation_off", "featureEnabled": false, "variables": []}],
+		"trafficAllocation": [{"entityId": "20", "endOfRange": 10000}]
+	}],
+	"featureFlags": [{
+		"id": "40",
+		"key": "test_flag",
+		"experimentIds": ["10"],
+		"rolloutId": "",
+		"variables": [{"id": "30", "key": "color", "type": "string", "defaultValue": "red"}]
+	}]
+}`
+
+type countingProcessor struct {
+	event.Processor
+	events []event.UserEvent
+	lock   sync.Mutex
+}
+
+func (p *countingProcessor) ProcessEvent(userEvent event.UserEvent) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.events = append(p.events, userEvent)
+	return true
+}
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	optimizelyClient *client.OptimizelyClient
+	processor        *countingProcessor
+}
+
+func (s *MiddlewareTestSuite) SetupTest() {
+	s.processor = &countingProcessor{}
+	factory := client.OptimizelyFactory{Datafile: []byte(testDatafile)}
+	optimizelyClient, err := factory.StaticClient()
+	s.Require().NoError(err)
+	optimizelyClient.EventProcessor = s.processor
+	s.optimizelyClient = optimizelyClient
+}
+
+func (s *MiddlewareTestSuite) TearDownTest() {
+	s.optimizelyClient.Close()
+}
+
+func (s *MiddlewareTestSuite) serve(middleware *Middleware, r *http.Request, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	middleware.Handler(handler).ServeHTTP(recorder, r)
+	return recorder
+}
+
+func (s *MiddlewareTestSuite) TestUserContextInRequest() {
+	middleware := NewMiddleware(s.optimizelyClient, HeaderID("X-User-ID"),
+		WithAttributesExtractors(
+			HeaderAttributes(map[string]string{"X-Plan": "plan", "X-Country": "country"}),
+			func(r *http.Request) map[string]interface{} {
+				return map[string]interface{}{"plan": "enterprise", "beta": true}
+			},
+		))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User-ID", "test_user")
+	r.Header.Set("X-Plan", "free")
+	r.Header.Set("X-Country", "nl")
+
+	called := false
+	s.serve(middleware, r, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		userContext, ok := UserContextFromContext(r.Context())
+		s.True(ok)
+		s.Equal("test_user", userContext.GetUserID())
+		s.Equal(map[string]interface{}{"plan": "enterprise", "country": "nl", "beta": true}, userContext.GetUserAttributes())
+	})
+	s.True(called)
+}
+
+func (s *MiddlewareTestSuite) TestFlagHelpersCacheDecisions() {
+	middleware := NewMiddleware(s.optimizelyClient, CookieID("uid"))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "uid", Value: "test_user"})
+
+	recorder := s.serve(middleware, r, func(w http.ResponseWriter, r *http.Request) {
+		s.True(IsEnabled(r.Context(), "test_flag"))
+		s.Equal("variation_on", VariationKey(r.Context(), "test_flag"))
+		s.Equal(map[string]interface{}{"color": "blue"}, Variables(r.Context(), "test_flag"))
+
+		decision, ok := Decide(r.Context(), "test_flag")
+		s.True(ok)
+		s.Equal("test_experiment", decision.RuleKey)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.Equal(http.StatusNoContent, recorder.Code)
+	// the flag is evaluated once per request, so a single impression is sent
+	s.Len(s.processor.events, 1)
+
+	s.serve(middleware, r, func(w http.ResponseWriter, r *http.Request) {
+		s.True(IsEnabled(r.Context(), "test_flag"))
+	})
+	s.Len(s.processor.events, 2)
+}
+
+func (s *MiddlewareTestSuite) TestUpdateUserContextClearsDecisions() {
+	middleware := NewMiddleware(s.optimizelyClient, CookieID("uid"))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "uid", Value: "test_user"})
+
+	s.serve(middleware, r, func(w http.ResponseWriter, r *http.Request) {
+		s.True(IsEnabled(r.Context(), "test_flag"))
+		s.True(UpdateUserContext(r.Context(), func(userContext *client.OptimizelyUserContext) {
+			userContext.SetForcedDecision(decision.OptimizelyDecisionContext{FlagKey: "test_flag"},
+				decision.OptimizelyForcedDecision{VariationKey: "variation_off"})
+		}))
+		s.False(IsEnabled(r.Context(), "test_flag"))
+		s.Equal("variation_off", VariationKey(r.Context(), "test_flag"))
+	})
+}
+
+func (s *MiddlewareTestSuite) TestMissingUser() {
+	middleware := NewMiddleware(s.optimizelyClient, HeaderID("X-User-ID"))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	called := false
+	s.serve(middleware, r, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_, ok := UserContextFromContext(r.Context())
+		s.False(ok)
+		s.False(IsEnabled(r.Context(), "test_flag"))
+		s.Equal("", VariationKey(r.Context(), "test_flag"))
+		s.Nil(Variables(r.Context(), "test_flag"))
+		s.False(UpdateUserContext(r.Context(), func(userContext *client.OptimizelyUserContext) {
+			s.Fail("there is no user context to update")
+		}))
+	})
+	s.True(called)
+	s.Empty(s.processor.events)
+}
+
+func (s *MiddlewareTestSuite) TestMissingUserHandler() {
+	middleware := NewMiddleware(s.optimizelyClient, HeaderID("X-User-ID"), WithMissingUserHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	recorder := s.serve(middleware, r, func(w http.ResponseWriter, r *http.Request) {
+		s.Fail("the next handler must not be called")
+	})
+	s.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
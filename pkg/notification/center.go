@@ -40,11 +40,15 @@ func NewNotificationCenter() *DefaultCenter {
 	projectConfigUpdateNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
 	processLogEventNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
 	trackNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
+	projectConfigStaleNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
+	projectConfigRejectedNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
 	managerMap := make(map[Type]Manager)
 	managerMap[Decision] = decisionNotificationManager
 	managerMap[ProjectConfigUpdate] = projectConfigUpdateNotificationManager
 	managerMap[LogEvent] = processLogEventNotificationManager
 	managerMap[Track] = trackNotificationManager
+	managerMap[ProjectConfigStale] = projectConfigStaleNotificationManager
+	managerMap[ProjectConfigRejected] = projectConfigRejectedNotificationManager
 	return &DefaultCenter{
 		managerMap: managerMap,
 	}
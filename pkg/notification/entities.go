@@ -1,5 +1,5 @@
 /****************************************************************************
- * Copyright 2019, Optimizely, Inc. and contributors                        *
+ * Copyright 2019-2020, Optimizely, Inc. and contributors                   *
  *                                                                          *
  * Licensed under the Apache License, Version 2.0 (the "License");          *
  * you may not use this file except in compliance with the License.         *
@@ -18,6 +18,8 @@
 package notification
 
 import (
+	"time"
+
 	"github.com/optimizely/go-sdk/pkg/entities"
 )
 
@@ -36,6 +38,10 @@ const (
 	ProjectConfigUpdate Type = "project_config_update"
 	// LogEvent notification type
 	LogEvent Type = "log_event_notification"
+	// ProjectConfigStale notification type
+	ProjectConfigStale Type = "project_config_stale"
+	// ProjectConfigRejected notification type
+	ProjectConfigRejected Type = "project_config_rejected"
 
 	// ABTest is used when the decision is returned as part of evaluating an ab test
 	ABTest DecisionNotificationType = "ab-test"
@@ -47,6 +53,8 @@ const (
 	FeatureVariable DecisionNotificationType = "feature-variable"
 	// AllFeatureVariables is used when the decision is returned as part of evaluating a feature with all variables
 	AllFeatureVariables DecisionNotificationType = "all-feature-variables"
+	// Flag is used when the decision is returned as part of evaluating a flag
+	Flag DecisionNotificationType = "flag"
 )
 
 // DecisionNotification is a notification triggered when a decision is made for either a feature or an experiment
@@ -66,8 +74,56 @@ type TrackNotification struct {
 
 // ProjectConfigUpdateNotification is a notification triggered when a project config is updated
 type ProjectConfigUpdateNotification struct {
+	Type        Type
+	Revision    string
+	OldRevision string
+	Diff        ProjectConfigDiff
+}
+
+// EntityChanges lists the keys of the entities added, removed and changed by a project config update
+type EntityChanges struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// IsEmpty returns true if no entity was added, removed or changed
+func (c EntityChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// ProjectConfigDiff describes the changes made by a project config update. Audiences are identified by their ID and
+// variables by their flag key and variable key joined by a dot. TrafficAllocations lists the keys of the experiments
+// and rollout rules present in both configs whose traffic allocation changed.
+type ProjectConfigDiff struct {
+	Flags              EntityChanges
+	Experiments        EntityChanges
+	Audiences          EntityChanges
+	Variables          EntityChanges
+	TrafficAllocations []string
+}
+
+// IsEmpty returns true if the update made no change
+func (d ProjectConfigDiff) IsEmpty() bool {
+	return d.Flags.IsEmpty() && d.Experiments.IsEmpty() && d.Audiences.IsEmpty() && d.Variables.IsEmpty() &&
+		len(d.TrafficAllocations) == 0
+}
+
+// ProjectConfigStaleNotification is a notification triggered when the project config has not been refreshed for
+// longer than the configured staleness threshold
+type ProjectConfigStaleNotification struct {
+	Type        Type
+	Revision    string
+	LastRefresh time.Time
+	Staleness   time.Duration
+}
+
+// ProjectConfigRejectedNotification is a notification triggered when a datafile is rejected, the project config of
+// the given revision is kept
+type ProjectConfigRejectedNotification struct {
 	Type     Type
 	Revision string
+	Reason   error
 }
 
 // LogEventNotification is the notification triggered before log event is dispatched.
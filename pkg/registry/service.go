@@ -39,3 +39,24 @@ func GetNotificationCenter(sdkKey string) notification.Center {
 
 	return notificationCenter
 }
+
+// RemoveNotificationCenter removes the notification center instance associated with the given SDK Key
+func RemoveNotificationCenter(sdkKey string) {
+	notificationLock.Lock()
+	defer notificationLock.Unlock()
+
+	delete(notificationCenterCache, sdkKey)
+}
+
+// RemoveNotificationCenterIfCurrent removes the given notification center instance of the given SDK Key, returns false
+// if another instance has replaced it in the meantime
+func RemoveNotificationCenterIfCurrent(sdkKey string, notificationCenter notification.Center) bool {
+	notificationLock.Lock()
+	defer notificationLock.Unlock()
+
+	if current, ok := notificationCenterCache[sdkKey]; !ok || current != notificationCenter {
+		return false
+	}
+	delete(notificationCenterCache, sdkKey)
+	return true
+}
@@ -44,6 +44,29 @@ func (s *ServiceRegistryTestSuite) TestGetNotificationCenter() {
 	s.Equal(notificationCenter, notificationCenter2)
 }
 
+func (s *ServiceRegistryTestSuite) TestRemoveNotificationCenter() {
+	sdkKey := "sdk_key_remove"
+	notificationCenter := GetNotificationCenter(sdkKey)
+
+	RemoveNotificationCenter(sdkKey)
+	s.NotContains(notificationCenterCache, sdkKey)
+
+	// a new notification center is created on the next access
+	s.True(notificationCenter != GetNotificationCenter(sdkKey))
+}
+
+func (s *ServiceRegistryTestSuite) TestRemoveNotificationCenterIfCurrent() {
+	sdkKey := "sdk_key_remove_if_current"
+	notificationCenter := GetNotificationCenter(sdkKey)
+	s.True(RemoveNotificationCenterIfCurrent(sdkKey, notificationCenter))
+	s.NotContains(notificationCenterCache, sdkKey)
+
+	// a notification center which has been replaced is not removed
+	newNotificationCenter := GetNotificationCenter(sdkKey)
+	s.False(RemoveNotificationCenterIfCurrent(sdkKey, notificationCenter))
+	s.True(newNotificationCenter == GetNotificationCenter(sdkKey))
+}
+
 func TestServiceRegistryTestSuite(t *testing.T) {
 	suite.Run(t, new(ServiceRegistryTestSuite))
 }